@@ -26,6 +26,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/antchfx/xmlquery"
@@ -41,6 +42,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
@@ -65,6 +67,9 @@ const (
 	configMapCompressed            = "openscap-scan-result/compressed"
 	apiserverOperatorName          = "openshift-apiserver"
 	tailoredProfileSuffix          = "-tp"
+	// aggregatorWorkers bounds the number of API calls the aggregator
+	// issues in parallel when creating, updating or deleting results
+	aggregatorWorkers = 10
 )
 
 var AggregatorCmd = &cobra.Command{
@@ -169,11 +174,11 @@ func readCompressedData(compressed string) (*bzip2.Reader, error) {
 }
 
 // parseResultRemediations parses scan results from a configMap with the help of DS provided in the
-// content parameter.
+// content parameter. Only the results of the selectedRules are parsed, unless it is nil.
 // Returns a triple of (array-of-ParseResults, source, error) where source identifies the entity whose
 // scan produced this configMap -- typically a nodeName for node scans. For platform scans, the source
 // is empty. The source is used later when reconciling inconsistent results
-func parseResultRemediations(client runtimeclient.Client, scheme *runtime.Scheme, scanName, namespace string, content *xmlquery.Node, cm *v1.ConfigMap) ([]*utils.ParseResult, string, error) {
+func parseResultRemediations(client runtimeclient.Client, scheme *runtime.Scheme, scanName, namespace string, content *xmlquery.Node, selectedRules map[string]bool, cm *v1.ConfigMap) ([]*utils.ParseResult, string, error) {
 	var scanReader io.Reader
 
 	_, ok := cm.Annotations[configMapRemediationsProcessed]
@@ -223,10 +228,39 @@ func parseResultRemediations(client runtimeclient.Client, scheme *runtime.Scheme
 		manualRules = xccdf.GetManualRules(tp)
 	}
 
-	table, err := utils.ParseResultsFromContentAndXccdf(scheme, scanName, namespace, content, scanReader, manualRules)
+	table, err := utils.StreamSelectedResultsFromContentAndXccdf(scheme, scanName, namespace, content, scanReader, manualRules, selectedRules)
 	return table, nodeName, nil
 }
 
+// getScanTailoring returns the tailoring the scan uses, or nil if it scans a
+// profile of the content as is
+func getScanTailoring(client runtimeclient.Client, scan *compv1alpha1.ComplianceScan) (*xmlquery.Node, error) {
+	if scan.Spec.TailoringConfigMap == nil {
+		return nil, nil
+	}
+	cm := &v1.ConfigMap{}
+	key := types.NamespacedName{Name: scan.Spec.TailoringConfigMap.Name, Namespace: scan.Namespace}
+	if err := client.Get(context.TODO(), key, cm); err != nil {
+		return nil, fmt.Errorf("couldn't get the tailoring ConfigMap %s: %w", key.Name, err)
+	}
+	tailoringXML, ok := cm.Data["tailoring.xml"]
+	if !ok {
+		return nil, fmt.Errorf("no tailoring.xml in ConfigMap %s", key.Name)
+	}
+	tailoring, err := utils.ParseContent(strings.NewReader(tailoringXML))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the tailoring of ConfigMap %s: %w", key.Name, err)
+	}
+	return tailoring, nil
+}
+
+// getSelectedRules returns the IDs of the rules the profile of the scan
+// selects, so that the rest of the rules can be left out when parsing the
+// results
+func getSelectedRules(scan *compv1alpha1.ComplianceScan, content, tailoring *xmlquery.Node) (map[string]bool, error) {
+	return utils.GetSelectedRuleIDs(content, tailoring, scan.Spec.Profile)
+}
+
 func getScanResult(cm *v1.ConfigMap) (compv1alpha1.ComplianceScanStatusResult, string) {
 	exitcode, ok := cm.Data["exit-code"]
 	if ok {
@@ -270,6 +304,12 @@ func annotateCMWithScanResult(cm *v1.ConfigMap, cmParsedResults []*utils.ParseRe
 		}
 	}
 
+	if scanResult == compv1alpha1.ResultNonCompliant && onlyUnscoredRulesFailed(cmParsedResults) {
+		// The scanner counts the failures of unscored rules too, but
+		// those are only informational
+		scanResult = compv1alpha1.ResultCompliant
+	}
+
 	// Finally annotate the CM with the result. The CM will be deep-copied prior to the
 	// update anyway
 	if cm.Annotations == nil {
@@ -280,6 +320,56 @@ func annotateCMWithScanResult(cm *v1.ConfigMap, cmParsedResults []*utils.ParseRe
 	return cm.DeepCopy()
 }
 
+// onlyUnscoredRulesFailed returns whether the results have failures of
+// unscored rules and no other failures or errors
+func onlyUnscoredRulesFailed(results []*utils.ParseResult) bool {
+	unscoredFailed := false
+	for i := range results {
+		if results[i] == nil || results[i].CheckResult == nil {
+			continue
+		}
+		check := results[i].CheckResult
+		switch check.Status {
+		case compv1alpha1.CheckResultFail, compv1alpha1.CheckResultError:
+			return false
+		case compv1alpha1.CheckResultInfo:
+			if _, ok := check.Annotations[compv1alpha1.ComplianceCheckResultUnscoredAnnotation]; ok {
+				unscoredFailed = true
+			}
+		}
+	}
+	return unscoredFailed
+}
+
+// getAbsentResourceTypeRules returns the IDs of the rules that the scan found
+// to check resources whose type doesn't exist on the cluster
+func getAbsentResourceTypeRules(scan *compv1alpha1.ComplianceScan) map[string]bool {
+	rules := map[string]bool{}
+	for _, ruleID := range scan.Status.AbsentResourceTypeRules {
+		rules[ruleID] = true
+	}
+	return rules
+}
+
+// annotateAbsentResourceTypeRules marks the results of the given rules as
+// checking resources whose type doesn't exist. The results themselves are
+// left alone, the rule may well require the resources to exist.
+func annotateAbsentResourceTypeRules(results []*utils.ParseResult, rules map[string]bool) {
+	for i := range results {
+		if results[i] == nil || results[i].CheckResult == nil {
+			continue
+		}
+		check := results[i].CheckResult
+		if !rules[check.ID] {
+			continue
+		}
+		if check.Annotations == nil {
+			check.Annotations = map[string]string{}
+		}
+		check.Annotations[compv1alpha1.ComplianceCheckResultAbsentResourceTypeAnnotation] = "true"
+	}
+}
+
 func markConfigMapAsProcessed(crClient aggregatorCrClient, cm *v1.ConfigMap) error {
 	cmCopy := cm.DeepCopy()
 
@@ -531,6 +621,12 @@ func getCheckResultLabels(pr *utils.ParseResult, resultLabels map[string]string,
 func getCheckResultAnnotations(cr *compv1alpha1.ComplianceCheckResult, resultAnnotations map[string]string) map[string]string {
 	annotations := make(map[string]string)
 	annotations[compv1alpha1.ComplianceCheckResultRuleAnnotation] = utils.IDToDNSFriendlyName(cr.ID)
+	if v, ok := cr.Annotations[compv1alpha1.ComplianceCheckResultUnscoredAnnotation]; ok {
+		annotations[compv1alpha1.ComplianceCheckResultUnscoredAnnotation] = v
+	}
+	if v, ok := cr.Annotations[compv1alpha1.ComplianceCheckResultAbsentResourceTypeAnnotation]; ok {
+		annotations[compv1alpha1.ComplianceCheckResultAbsentResourceTypeAnnotation] = v
+	}
 	for k, v := range resultAnnotations {
 		annotations[k] = v
 	}
@@ -538,6 +634,82 @@ func getCheckResultAnnotations(cr *compv1alpha1.ComplianceCheckResult, resultAnn
 	return annotations
 }
 
+// getScanProfileBundleName returns the name of the ProfileBundle the profile
+// of the scan comes from. Several bundles may well ship the same content file,
+// so the bundle is found through the Profile or the TailoredProfile of the
+// scan, the same way the suite found the profile when it launched the scan.
+func getScanProfileBundleName(client runtimeclient.Client, scan *compv1alpha1.ComplianceScan) (string, error) {
+	if scan.Spec.TailoringConfigMap != nil {
+		tpName := strings.TrimSuffix(scan.Spec.TailoringConfigMap.Name, tailoredProfileSuffix)
+		tp := &compv1alpha1.TailoredProfile{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: tpName, Namespace: scan.Namespace}, tp); err != nil {
+			return "", fmt.Errorf("Unable to get the TailoredProfile %s: %w", tpName, err)
+		}
+		// The TailoredProfile is owned by the Profile it extends, or by
+		// the ProfileBundle of its rules if it doesn't extend any
+		owner := metav1.GetControllerOf(tp)
+		if owner == nil {
+			return "", fmt.Errorf("TailoredProfile %s has no owner", tpName)
+		}
+		switch owner.Kind {
+		case "ProfileBundle":
+			return owner.Name, nil
+		case "Profile":
+			profile := &compv1alpha1.Profile{}
+			if err := client.Get(context.TODO(), types.NamespacedName{Name: owner.Name, Namespace: scan.Namespace}, profile); err != nil {
+				return "", fmt.Errorf("Unable to get the Profile %s: %w", owner.Name, err)
+			}
+			return profile.Labels[compv1alpha1.ProfileBundleOwnerLabel], nil
+		}
+		return "", fmt.Errorf("TailoredProfile %s is owned by an unexpected %s", tpName, owner.Kind)
+	}
+
+	profiles := compv1alpha1.ProfileList{}
+	if err := client.List(context.TODO(), &profiles, runtimeclient.InNamespace(scan.Namespace)); err != nil {
+		return "", fmt.Errorf("Unable to list the Profiles: %w", err)
+	}
+	for _, profile := range profiles.Items {
+		if profile.ID == scan.Spec.Profile && scan.Name == utils.GetScanNameFromProfile(profile.Name, scan.Spec.NodeSelector) {
+			return profile.Labels[compv1alpha1.ProfileBundleOwnerLabel], nil
+		}
+	}
+	return "", fmt.Errorf("no Profile %s matches the scan", scan.Spec.Profile)
+}
+
+// getRuleExternalRefs maps the IDs of the rules of the ProfileBundle the
+// scan uses to the external references set on them
+func getRuleExternalRefs(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan) (map[string]string, error) {
+	refs := make(map[string]string)
+	pbName, err := getScanProfileBundleName(crClient.getClient(), scan)
+	if err != nil {
+		return nil, err
+	}
+	if pbName == "" {
+		return refs, nil
+	}
+	rules := compv1alpha1.RuleList{}
+	err = crClient.getClient().List(context.TODO(), &rules, runtimeclient.InNamespace(scan.Namespace),
+		runtimeclient.MatchingLabels{compv1alpha1.ProfileBundleOwnerLabel: pbName})
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the Rules of ProfileBundle %s: %w", pbName, err)
+	}
+	for _, rule := range rules.Items {
+		if ref := rule.Annotations[compv1alpha1.RuleExternalRefAnnotationKey]; ref != "" {
+			refs[rule.ID] = ref
+		}
+	}
+	return refs, nil
+}
+
+// addExternalRef copies the external reference of the rule onto the labels
+// and annotations of its check result
+func addExternalRef(ref string, labels, annotations map[string]string) {
+	annotations[compv1alpha1.RuleExternalRefAnnotationKey] = ref
+	if len(validation.IsValidLabelValue(ref)) == 0 {
+		labels[compv1alpha1.ComplianceCheckResultExternalRefLabel] = ref
+	}
+}
+
 func createResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan, consistentResults []*utils.ParseResultContextItem) error {
 	cmdLog.Info("Will create result objects", "objects", len(consistentResults))
 	if len(consistentResults) == 0 {
@@ -567,6 +739,9 @@ func createResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceSca
 	// these up during the aggregation phase, it will look like they're
 	// still valid, even though they're from an old scan.
 	staleComplianceCheckResults := make(map[string]compv1alpha1.ComplianceCheckResult)
+	// The same list also tells us which results already exist, so we don't
+	// need to look each of them up separately.
+	existingComplianceCheckResults := make(map[string]*compv1alpha1.ComplianceCheckResult)
 	complianceCheckResults := compv1alpha1.ComplianceCheckResultList{}
 	withLabel := map[string]string{
 		compv1alpha1.ComplianceScanLabel: scan.Name,
@@ -579,13 +754,23 @@ func createResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceSca
 	if err != nil {
 		return fmt.Errorf("Unable to fetch existing ComplianceCheckResultList: %w", err)
 	}
-	for _, r := range complianceCheckResults.Items {
+	for i := range complianceCheckResults.Items {
+		r := &complianceCheckResults.Items[i]
 		// Use a map so that we can find specific
 		// ComplianceCheckResults without iterating over the list for
 		// every new result from the latest scan.
-		staleComplianceCheckResults[r.Name] = r
+		staleComplianceCheckResults[r.Name] = *r
+		existingComplianceCheckResults[r.Name] = r
 	}
 
+	externalRefs, err := getRuleExternalRefs(crClient, scan)
+	if err != nil {
+		// The results are still worth creating without the references
+		cmdLog.Info("Not copying the external references of the rules onto the results", "error", err.Error())
+	}
+
+	writes := make([]checkResultWrite, 0, len(consistentResults))
+	skippedPassing := 0
 	for _, pr := range consistentResults {
 		if pr == nil || pr.CheckResult == nil {
 			cmdLog.Info("nil result or result.check, this shouldn't happen")
@@ -598,16 +783,16 @@ func createResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceSca
 			continue
 		}
 
-		checkResultLabels := getCheckResultLabels(&pr.ParseResult, pr.Labels, scan)
-		checkResultAnnotations := getCheckResultAnnotations(pr.CheckResult, pr.Annotations)
+		write := checkResultWrite{
+			pr:          pr,
+			labels:      getCheckResultLabels(&pr.ParseResult, pr.Labels, scan),
+			annotations: getCheckResultAnnotations(pr.CheckResult, pr.Annotations),
+		}
+		if ref, ok := externalRefs[pr.CheckResult.ID]; ok {
+			addExternalRef(ref, write.labels, write.annotations)
+		}
 
-		crkey := getObjKey(pr.CheckResult.GetName(), pr.CheckResult.GetNamespace())
-		foundCheckResult := &compv1alpha1.ComplianceCheckResult{}
-		// Copy type metadata so dynamic client copies data correctly
-		foundCheckResult.TypeMeta = pr.CheckResult.TypeMeta
-		cmdLog.Info("Getting ComplianceCheckResult", "ComplianceCheckResult.Name", crkey.Name,
-			"ComplianceCheckResult.Namespace", crkey.Namespace)
-		checkResultExists := getObjectIfFound(crClient, crkey, foundCheckResult)
+		foundCheckResult, checkResultExists := existingComplianceCheckResults[pr.CheckResult.GetName()]
 		if checkResultExists {
 			// Copy resource version and other metadata needed for update
 			foundCheckResult.ObjectMeta.DeepCopyInto(&pr.CheckResult.ObjectMeta)
@@ -617,18 +802,37 @@ func createResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceSca
 			// work in order to get older deployments to keep working.
 			continue
 		}
-		// check is owned by the scan
-		if err := createOrUpdateOneResult(crClient, scan, checkResultLabels, checkResultAnnotations, checkResultExists, pr.CheckResult); err != nil {
-			return fmt.Errorf("cannot create or update checkResult %s: %v", pr.CheckResult.Name, err)
+		if scan.Spec.SkipPassingResults && pr.CheckResult.Status == compv1alpha1.CheckResultPass {
+			// Passing results are only counted. If one exists from
+			// before the setting was enabled, it's left in the list of
+			// stale results and removed below.
+			skippedPassing++
+			continue
 		}
+		write.exists = checkResultExists
+		writes = append(writes, write)
 
 		// Remove the ComplianceCheckResult from the list of stale
 		// results so we don't delete it later.
-		_, ok := staleComplianceCheckResults[foundCheckResult.Name]
-		if ok {
-			delete(staleComplianceCheckResults, foundCheckResult.Name)
+		delete(staleComplianceCheckResults, pr.CheckResult.GetName())
+	}
+
+	// check is owned by the scan
+	err = forEachConcurrently(len(writes), aggregatorWorkers, func(i int) error {
+		w := writes[i]
+		if err := createOrUpdateOneResult(crClient, scan, w.labels, w.annotations, w.exists, w.pr.CheckResult); err != nil {
+			return fmt.Errorf("cannot create or update checkResult %s: %v", w.pr.CheckResult.Name, err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
+	// Remediations are owned by the checks, so they can only be handled
+	// once all the checks were created.
+	for _, w := range writes {
+		pr := w.pr
 		// Handle forwarding.
 		f.SendComplianceCheckResult(pr.CheckResult)
 
@@ -657,14 +861,87 @@ func createResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceSca
 	// should delete them. Otherwise, we give users the impression changes
 	// they've made to their scans, profiles, or settings haven't taken
 	// effect.
+	staleResults := make([]compv1alpha1.ComplianceCheckResult, 0, len(staleComplianceCheckResults))
 	for _, result := range staleComplianceCheckResults {
-		err := crClient.getClient().Delete(context.TODO(), &result)
-		if err != nil {
+		staleResults = append(staleResults, result)
+	}
+	err = forEachConcurrently(len(staleResults), aggregatorWorkers, func(i int) error {
+		result := &staleResults[i]
+		if err := crClient.getClient().Delete(context.TODO(), result); err != nil {
 			return fmt.Errorf("Unable to delete stale ComplianceCheckResult %s: %w", result.Name, err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return nil
+	if !scan.Spec.SkipPassingResults && scan.Status.SkippedPassingResults == 0 {
+		return nil
+	}
+	return updateSkippedPassingResults(crClient, scan, skippedPassing)
+}
+
+// updateSkippedPassingResults records the number of passing checks that
+// didn't get a ComplianceCheckResult in the status of the scan
+func updateSkippedPassingResults(crClient aggregatorCrClient, scan *compv1alpha1.ComplianceScan, skipped int) error {
+	scanKey := getObjKey(scan.GetName(), scan.GetNamespace())
+	cmdLog.Info("Recording skipped passing results", "ComplianceScan.Name", scanKey.Name, "skipped", skipped)
+
+	return backoff.Retry(func() error {
+		foundScan := &compv1alpha1.ComplianceScan{}
+		if err := crClient.getClient().Get(context.TODO(), scanKey, foundScan); err != nil {
+			return fmt.Errorf("cannot get scan %s: %v", scanKey.Name, err)
+		}
+		if foundScan.Status.SkippedPassingResults == skipped {
+			return nil
+		}
+		foundScan.Status.SkippedPassingResults = skipped
+		if err := crClient.getClient().Status().Update(context.TODO(), foundScan); err != nil {
+			return fmt.Errorf("cannot update status of scan %s: %v", scanKey.Name, err)
+		}
+		return nil
+	}, backoff.WithMaxRetries(backoff.NewExponentialBackOff(), maxRetries))
+}
+
+// checkResultWrite is a pending create or update of a single check result
+type checkResultWrite struct {
+	pr          *utils.ParseResultContextItem
+	labels      map[string]string
+	annotations map[string]string
+	exists      bool
+}
+
+// forEachConcurrently calls fn for every index in [0, n) using at most
+// workers goroutines. All the calls are made even if some of them fail; the
+// first error encountered is returned.
+func forEachConcurrently(n, workers int, fn func(i int) error) error {
+	if n < workers {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(i); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	close(errs)
+
+	return <-errs
 }
 
 func handleRemediation(crClient aggregatorCrClient, rem *compv1alpha1.ComplianceRemediation, cr *compv1alpha1.ComplianceCheckResult, scan *compv1alpha1.ComplianceScan) error {
@@ -842,20 +1119,38 @@ func aggregator(cmd *cobra.Command, args []string) {
 	// #nosec
 	defer contentFile.Close()
 	bufContentFile := bufio.NewReader(contentFile)
-	contentDom, err := utils.ParseContent(bufContentFile)
+	contentDom, err := utils.ParseDataStream(bufContentFile)
 	if err != nil {
 		cmdLog.Error(err, "Cannot parse the content")
 		os.Exit(1)
 	}
+	if duplicates := utils.FindDuplicateRuleIDs(contentDom); len(duplicates) > 0 {
+		cmdLog.Info(utils.DuplicateRuleIDsMessage, "ids", duplicates)
+	}
+
+	var selectedRules map[string]bool
+	var severityOverrides map[string]compv1alpha1.ComplianceCheckResultSeverity
+	tailoring, err := getScanTailoring(crclient.getClient(), scan)
+	if err != nil {
+		cmdLog.Info("Parsing the results of all the rules with the severities of the content, couldn't get the tailoring of the scan", "error", err.Error())
+	} else {
+		selectedRules, err = getSelectedRules(scan, contentDom, tailoring)
+		if err != nil {
+			cmdLog.Info("Parsing the results of all the rules, couldn't find out which ones the scan selects", "error", err.Error())
+			selectedRules = nil
+		}
+		severityOverrides = utils.GetRuleSeverityOverrides(tailoring, scan.Spec.Profile)
+	}
 
 	prCtx := utils.NewParseResultContext()
+	absentResourceTypeRules := getAbsentResourceTypeRules(scan)
 
 	// For each configmap, create a list of remediations
 	for i := range configMaps {
 		cm := &configMaps[i]
 		cmdLog.Info("processing ConfigMap", "ConfigMap.Name", cm.Name)
 
-		cmParsedResults, source, err := parseResultRemediations(crclient.getClient(), crclient.getScheme(), aggregatorConf.ScanName, aggregatorConf.Namespace, contentDom, cm)
+		cmParsedResults, source, err := parseResultRemediations(crclient.getClient(), crclient.getScheme(), aggregatorConf.ScanName, aggregatorConf.Namespace, contentDom, selectedRules, cm)
 		if err != nil {
 			cmdLog.Error(err, "Cannot parse ConfigMap into remediations", "ConfigMap.Name", cm.Name)
 		} else if cmParsedResults == nil {
@@ -864,6 +1159,8 @@ func aggregator(cmd *cobra.Command, args []string) {
 		}
 		cmdLog.Info("ConfigMap contained parsed results", "ConfigMap.Name", cm.Name, "results", len(cmParsedResults))
 
+		utils.OverrideCheckSeverities(cmParsedResults, severityOverrides)
+		annotateAbsentResourceTypeRules(cmParsedResults, absentResourceTypeRules)
 		prCtx.AddResults(source, cmParsedResults)
 		// If the CM was processed, annotate it with the result
 		annotateCMWithScanResult(&configMaps[i], cmParsedResults)
@@ -3,14 +3,19 @@ package manager
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync/atomic"
 
+	"github.com/antchfx/xmlquery"
 	backoff "github.com/cenkalti/backoff/v4"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	ocpcfgv1 "github.com/openshift/api/config/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
@@ -21,6 +26,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
 )
 
 type aggregatorCrClientFake struct {
@@ -74,6 +81,27 @@ func (vg *fakeversionget) ServerVersion() (*version.Info, error) {
 	return nil, backoff.Permanent(fmt.Errorf("Some error"))
 }
 
+// countingClient counts the calls made for ComplianceCheckResults
+type countingClient struct {
+	runtimeclient.Client
+	checkGets  int32
+	checkLists int32
+}
+
+func (c *countingClient) Get(ctx context.Context, key runtimeclient.ObjectKey, obj runtimeclient.Object, opts ...runtimeclient.GetOption) error {
+	if _, ok := obj.(*compv1alpha1.ComplianceCheckResult); ok {
+		atomic.AddInt32(&c.checkGets, 1)
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *countingClient) List(ctx context.Context, list runtimeclient.ObjectList, opts ...runtimeclient.ListOption) error {
+	if _, ok := list.(*compv1alpha1.ComplianceCheckResultList); ok {
+		atomic.AddInt32(&c.checkLists, 1)
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
 var _ = Describe("Aggregator Tests", func() {
 	Context("Empty Remediations", func() {
 		It("Creates an empty remediation", func() {
@@ -296,4 +324,376 @@ var _ = Describe("Aggregator Tests", func() {
 			})
 		})
 	})
+
+	Context("Creating check results", func() {
+		var scan *compv1alpha1.ComplianceScan
+		var client *countingClient
+		var crClient *aggregatorCrClientFake
+
+		newCheck := func(name string, status compv1alpha1.ComplianceCheckStatus) *compv1alpha1.ComplianceCheckResult {
+			return &compv1alpha1.ComplianceCheckResult{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "ComplianceCheckResult",
+					APIVersion: compv1alpha1.SchemeGroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: scan.Namespace,
+				},
+				ID:     "xccdf_org.ssgproject.content_rule_" + name,
+				Status: status,
+			}
+		}
+
+		BeforeEach(func() {
+			scheme := getScheme()
+			scan = &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+			}
+
+			existing := newCheck("existing", compv1alpha1.CheckResultFail)
+			existing.Labels = map[string]string{compv1alpha1.ComplianceScanLabel: scan.Name}
+			stale := newCheck("stale", compv1alpha1.CheckResultFail)
+			stale.Labels = map[string]string{compv1alpha1.ComplianceScanLabel: scan.Name}
+
+			client = &countingClient{
+				Client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithRuntimeObjects(scan, existing, stale).
+					WithStatusSubresource(scan).
+					Build(),
+			}
+			crClient = &aggregatorCrClientFake{
+				scheme:      scheme,
+				client:      client,
+				recorder:    fakerec.NewFakeRecorder(10),
+				fakevgetter: &fakeversionget{},
+			}
+		})
+
+		It("lists the existing results once and deletes the stale ones", func() {
+			results := []*utils.ParseResultContextItem{
+				{ParseResult: utils.ParseResult{CheckResult: newCheck("existing", compv1alpha1.CheckResultPass)}},
+			}
+			for i := 0; i < 25; i++ {
+				results = append(results, &utils.ParseResultContextItem{
+					ParseResult: utils.ParseResult{CheckResult: newCheck(fmt.Sprintf("new-%d", i), compv1alpha1.CheckResultFail)},
+				})
+			}
+
+			err := createResults(crClient, scan, results)
+			Expect(err).To(BeNil())
+			Expect(client.checkLists).To(BeEquivalentTo(1))
+			Expect(client.checkGets).To(BeEquivalentTo(0))
+
+			checks := &compv1alpha1.ComplianceCheckResultList{}
+			Expect(client.Client.List(context.TODO(), checks)).To(Succeed())
+			Expect(checks.Items).To(HaveLen(26))
+			for _, check := range checks.Items {
+				Expect(check.Name).ToNot(Equal("stale"))
+				if check.Name == "existing" {
+					Expect(check.Status).To(Equal(compv1alpha1.CheckResultPass))
+				}
+			}
+		})
+
+		It("only creates the non-passing results when passing results are skipped", func() {
+			scan.Spec.SkipPassingResults = true
+			results := []*utils.ParseResultContextItem{
+				{ParseResult: utils.ParseResult{CheckResult: newCheck("existing", compv1alpha1.CheckResultPass)}},
+				{ParseResult: utils.ParseResult{CheckResult: newCheck("new-pass", compv1alpha1.CheckResultPass)}},
+				{ParseResult: utils.ParseResult{CheckResult: newCheck("new-fail", compv1alpha1.CheckResultFail)}},
+				{ParseResult: utils.ParseResult{CheckResult: newCheck("new-manual", compv1alpha1.CheckResultInfo)}},
+			}
+
+			Expect(createResults(crClient, scan, results)).To(Succeed())
+
+			checks := &compv1alpha1.ComplianceCheckResultList{}
+			Expect(client.Client.List(context.TODO(), checks)).To(Succeed())
+			names := []string{}
+			for _, check := range checks.Items {
+				Expect(check.Status).ToNot(Equal(compv1alpha1.CheckResultPass))
+				names = append(names, check.Name)
+			}
+			Expect(names).To(ConsistOf("new-fail", "new-manual"))
+
+			foundScan := &compv1alpha1.ComplianceScan{}
+			Expect(client.Get(context.TODO(), types.NamespacedName{Name: scan.Name, Namespace: scan.Namespace}, foundScan)).To(Succeed())
+			Expect(foundScan.Status.SkippedPassingResults).To(Equal(2))
+		})
+
+		It("copies the external reference of the rule onto the check result", func() {
+			scan.Spec.Content = "ssg-ocp4-ds.xml"
+			scan.Spec.Profile = "xccdf_org.ssgproject.content_profile_cis"
+			// Both bundles ship the same content file, only the profile of
+			// the scan tells them apart
+			profile := &compv1alpha1.Profile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      scan.Name,
+					Namespace: scan.Namespace,
+					Labels:    map[string]string{compv1alpha1.ProfileBundleOwnerLabel: "ocp4"},
+				},
+				ProfilePayload: compv1alpha1.ProfilePayload{ID: scan.Spec.Profile},
+			}
+			otherProfile := &compv1alpha1.Profile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-" + scan.Name,
+					Namespace: scan.Namespace,
+					Labels:    map[string]string{compv1alpha1.ProfileBundleOwnerLabel: "ocp4-copy"},
+				},
+				ProfilePayload: compv1alpha1.ProfilePayload{ID: scan.Spec.Profile},
+			}
+			newRule := func(bundleName, name, ref string) *compv1alpha1.Rule {
+				return &compv1alpha1.Rule{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        bundleName + "-" + name,
+						Namespace:   scan.Namespace,
+						Labels:      map[string]string{compv1alpha1.ProfileBundleOwnerLabel: bundleName},
+						Annotations: map[string]string{compv1alpha1.RuleExternalRefAnnotationKey: ref},
+					},
+					RulePayload: compv1alpha1.RulePayload{ID: "xccdf_org.ssgproject.content_rule_" + name},
+				}
+			}
+			for _, obj := range []runtimeclient.Object{
+				profile, otherProfile,
+				newRule("ocp4", "tracked", "SEC-1234"),
+				newRule("ocp4", "spaces", "not a label value"),
+				newRule("ocp4-copy", "other", "SEC-9999"),
+			} {
+				Expect(client.Create(context.TODO(), obj)).To(Succeed())
+			}
+
+			results := []*utils.ParseResultContextItem{
+				{ParseResult: utils.ParseResult{CheckResult: newCheck("tracked", compv1alpha1.CheckResultFail)}},
+				{ParseResult: utils.ParseResult{CheckResult: newCheck("spaces", compv1alpha1.CheckResultFail)}},
+				{ParseResult: utils.ParseResult{CheckResult: newCheck("other", compv1alpha1.CheckResultFail)}},
+			}
+			Expect(createResults(crClient, scan, results)).To(Succeed())
+
+			getCheck := func(name string) *compv1alpha1.ComplianceCheckResult {
+				check := &compv1alpha1.ComplianceCheckResult{}
+				Expect(client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: scan.Namespace}, check)).To(Succeed())
+				return check
+			}
+			tracked := getCheck("tracked")
+			Expect(tracked.Labels).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultExternalRefLabel, "SEC-1234"))
+			Expect(tracked.Annotations).To(HaveKeyWithValue(compv1alpha1.RuleExternalRefAnnotationKey, "SEC-1234"))
+
+			spaces := getCheck("spaces")
+			Expect(spaces.Labels).ToNot(HaveKey(compv1alpha1.ComplianceCheckResultExternalRefLabel))
+			Expect(spaces.Annotations).To(HaveKeyWithValue(compv1alpha1.RuleExternalRefAnnotationKey, "not a label value"))
+
+			other := getCheck("other")
+			Expect(other.Annotations).ToNot(HaveKey(compv1alpha1.RuleExternalRefAnnotationKey))
+
+			selected := &compv1alpha1.ComplianceCheckResultList{}
+			Expect(client.List(context.TODO(), selected, runtimeclient.MatchingLabels{
+				compv1alpha1.ComplianceCheckResultExternalRefLabel: "SEC-1234",
+			})).To(Succeed())
+			Expect(selected.Items).To(HaveLen(1))
+		})
+	})
+
+	Context("Finding the ProfileBundle of a tailored scan", func() {
+		var scan *compv1alpha1.ComplianceScan
+
+		BeforeEach(func() {
+			scan = &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{Name: "scan", Namespace: "test-ns"},
+				Spec: compv1alpha1.ComplianceScanSpec{
+					TailoringConfigMap: &compv1alpha1.TailoringConfigMapRef{Name: "tailored" + tailoredProfileSuffix},
+				},
+			}
+		})
+
+		newTailoredProfile := func(ownerKind, ownerName string) *compv1alpha1.TailoredProfile {
+			isController := true
+			return &compv1alpha1.TailoredProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tailored",
+					Namespace: scan.Namespace,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: compv1alpha1.SchemeGroupVersion.String(),
+						Kind:       ownerKind,
+						Name:       ownerName,
+						Controller: &isController,
+					}},
+				},
+			}
+		}
+
+		It("follows the Profile the TailoredProfile extends", func() {
+			profile := &compv1alpha1.Profile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ocp4-cis",
+					Namespace: scan.Namespace,
+					Labels:    map[string]string{compv1alpha1.ProfileBundleOwnerLabel: "ocp4"},
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(getScheme()).
+				WithObjects(profile, newTailoredProfile("Profile", profile.Name)).Build()
+			Expect(getScanProfileBundleName(client, scan)).To(Equal("ocp4"))
+		})
+
+		It("uses the ProfileBundle that owns a TailoredProfile extending nothing", func() {
+			client := fake.NewClientBuilder().WithScheme(getScheme()).
+				WithObjects(newTailoredProfile("ProfileBundle", "ocp4")).Build()
+			Expect(getScanProfileBundleName(client, scan)).To(Equal("ocp4"))
+		})
+	})
+
+	Context("Annotating the scan result", func() {
+		newResult := func(name string, status compv1alpha1.ComplianceCheckStatus, unscored bool) *utils.ParseResult {
+			check := &compv1alpha1.ComplianceCheckResult{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Status:     status,
+			}
+			if unscored {
+				check.Annotations = map[string]string{compv1alpha1.ComplianceCheckResultUnscoredAnnotation: "true"}
+			}
+			return &utils.ParseResult{Id: name, CheckResult: check}
+		}
+
+		nonCompliantCM := func() *v1.ConfigMap {
+			return &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "result"},
+				Data:       map[string]string{"exit-code": common.OpenSCAPExitCodeNonCompliant},
+			}
+		}
+
+		scanWithResultOf := func(cm *v1.ConfigMap) compv1alpha1.ComplianceScan {
+			scan := compv1alpha1.ComplianceScan{}
+			scan.Status.Result = compv1alpha1.ComplianceScanStatusResult(cm.Annotations[compv1alpha1.CmScanResultAnnotation])
+			return scan
+		}
+
+		It("doesn't make the suite non-compliant when only unscored rules failed", func() {
+			cm := annotateCMWithScanResult(nonCompliantCM(), []*utils.ParseResult{
+				newResult("passing", compv1alpha1.CheckResultPass, false),
+				newResult("unscored", compv1alpha1.CheckResultInfo, true),
+			})
+			Expect(cm.Annotations).To(HaveKeyWithValue(compv1alpha1.CmScanResultAnnotation, string(compv1alpha1.ResultCompliant)))
+
+			compliantScan := compv1alpha1.ComplianceScan{}
+			compliantScan.Status.Result = compv1alpha1.ResultCompliant
+			suiteResult := utils.AggregateSuiteResult([]compv1alpha1.ComplianceScan{scanWithResultOf(cm), compliantScan})
+			Expect(suiteResult).To(Equal(compv1alpha1.ResultCompliant))
+		})
+
+		It("keeps the scan non-compliant when a scored rule failed too", func() {
+			cm := annotateCMWithScanResult(nonCompliantCM(), []*utils.ParseResult{
+				newResult("failing", compv1alpha1.CheckResultFail, false),
+				newResult("unscored", compv1alpha1.CheckResultInfo, true),
+			})
+			Expect(cm.Annotations).To(HaveKeyWithValue(compv1alpha1.CmScanResultAnnotation, string(compv1alpha1.ResultNonCompliant)))
+		})
+
+		It("keeps the scan non-compliant without unscored rules", func() {
+			cm := annotateCMWithScanResult(nonCompliantCM(), []*utils.ParseResult{
+				newResult("passing", compv1alpha1.CheckResultPass, false),
+			})
+			Expect(cm.Annotations).To(HaveKeyWithValue(compv1alpha1.CmScanResultAnnotation, string(compv1alpha1.ResultNonCompliant)))
+		})
+
+		Context("with rules checking absent resource types", func() {
+			var results []*utils.ParseResult
+
+			BeforeEach(func() {
+				results = []*utils.ParseResult{
+					newResult("passing", compv1alpha1.CheckResultPass, false),
+					newResult("absent", compv1alpha1.CheckResultFail, false),
+				}
+				for _, r := range results {
+					r.CheckResult.ID = "xccdf_org.ssgproject.content_rule_" + r.Id
+				}
+				scan := &compv1alpha1.ComplianceScan{}
+				scan.Status.AbsentResourceTypeRules = []string{"xccdf_org.ssgproject.content_rule_absent"}
+				annotateAbsentResourceTypeRules(results, getAbsentResourceTypeRules(scan))
+			})
+
+			It("annotates their results and leaves them alone otherwise", func() {
+				Expect(results[0].CheckResult.Annotations).ToNot(HaveKey(compv1alpha1.ComplianceCheckResultAbsentResourceTypeAnnotation))
+				Expect(results[1].CheckResult.Annotations).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultAbsentResourceTypeAnnotation, "true"))
+				Expect(results[1].CheckResult.Status).To(Equal(compv1alpha1.CheckResultFail))
+				By("keeping the annotation on the ComplianceCheckResult")
+				Expect(getCheckResultAnnotations(results[1].CheckResult, nil)).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultAbsentResourceTypeAnnotation, "true"))
+			})
+
+			It("keeps the scan non-compliant", func() {
+				cm := annotateCMWithScanResult(nonCompliantCM(), results)
+				Expect(cm.Annotations).To(HaveKeyWithValue(compv1alpha1.CmScanResultAnnotation, string(compv1alpha1.ResultNonCompliant)))
+			})
+		})
+
+		It("doesn't annotate any rule without them in the scan status", func() {
+			results := []*utils.ParseResult{newResult("failing", compv1alpha1.CheckResultFail, false)}
+			results[0].CheckResult.ID = "failing"
+			annotateAbsentResourceTypeRules(results, getAbsentResourceTypeRules(&compv1alpha1.ComplianceScan{}))
+			Expect(results[0].CheckResult.Annotations).To(BeEmpty())
+		})
+	})
+
+	Context("Finding the rules the scan selects", func() {
+		var content *xmlquery.Node
+		var scan *compv1alpha1.ComplianceScan
+
+		BeforeEach(func() {
+			var err error
+			content, err = utils.ParseContent(strings.NewReader(`<xccdf-1.2:Benchmark xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2">
+				<xccdf-1.2:Profile id="profile_base">
+					<xccdf-1.2:select idref="rule_a" selected="true"/>
+					<xccdf-1.2:select idref="rule_b" selected="true"/>
+				</xccdf-1.2:Profile>
+			</xccdf-1.2:Benchmark>`))
+			Expect(err).To(BeNil())
+			scan = &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{Name: "scan", Namespace: "test-ns"},
+				Spec:       compv1alpha1.ComplianceScanSpec{Profile: "profile_base"},
+			}
+		})
+
+		It("selects the rules of the profile", func() {
+			client := fake.NewClientBuilder().WithScheme(getScheme()).Build()
+			tailoring, err := getScanTailoring(client, scan)
+			Expect(err).To(BeNil())
+			Expect(tailoring).To(BeNil())
+			selected, err := getSelectedRules(scan, content, tailoring)
+			Expect(err).To(BeNil())
+			Expect(selected).To(Equal(map[string]bool{"rule_a": true, "rule_b": true}))
+		})
+
+		It("selects the rules of the tailored profile", func() {
+			scan.Spec.Profile = "profile_tailored"
+			scan.Spec.TailoringConfigMap = &compv1alpha1.TailoringConfigMapRef{Name: "tailoring"}
+			cm := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "tailoring", Namespace: "test-ns"},
+				Data: map[string]string{
+					"tailoring.xml": `<xccdf-1.2:Tailoring xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2">
+						<xccdf-1.2:Profile id="profile_tailored" extends="profile_base">
+							<xccdf-1.2:select idref="rule_b" selected="false"/>
+							<xccdf-1.2:refine-rule idref="rule_a" severity="low"/>
+						</xccdf-1.2:Profile>
+					</xccdf-1.2:Tailoring>`,
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(getScheme()).WithObjects(cm).Build()
+			tailoring, err := getScanTailoring(client, scan)
+			Expect(err).To(BeNil())
+			selected, err := getSelectedRules(scan, content, tailoring)
+			Expect(err).To(BeNil())
+			Expect(selected).To(Equal(map[string]bool{"rule_a": true}))
+			Expect(utils.GetRuleSeverityOverrides(tailoring, scan.Spec.Profile)).To(Equal(
+				map[string]compv1alpha1.ComplianceCheckResultSeverity{"rule_a": compv1alpha1.CheckResultSeverityLow}))
+		})
+
+		It("fails when the tailoring ConfigMap is missing", func() {
+			scan.Spec.TailoringConfigMap = &compv1alpha1.TailoringConfigMapRef{Name: "tailoring"}
+			client := fake.NewClientBuilder().WithScheme(getScheme()).Build()
+			_, err := getScanTailoring(client, scan)
+			Expect(err).ToNot(BeNil())
+		})
+	})
 })
@@ -17,6 +17,8 @@ package manager
 
 import (
 	"flag"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -50,6 +52,9 @@ type ResourceFetcher interface {
 	FigureResources(profile string) error
 	// Fetch the resources.
 	FetchResources() ([]string, error)
+	// Check which resources couldn't be fetched because of missing
+	// permissions, without fetching them.
+	PreflightResources() ([]string, error)
 	// Save warnings
 	SaveWarningsIfAny([]string, string) error
 	// Save the resources.
@@ -63,6 +68,26 @@ type fetcherConfig struct {
 	Profile            string
 	ExitCodeFile       string
 	WarningsOutputFile string
+	// How long to wait for the content and tailoring files to appear
+	ContentFileTimeout time.Duration
+	// How often to check whether the content files have appeared
+	ContentFilePollInterval time.Duration
+	// Either "nested" or "flat", see saveResources and saveResourcesFlat
+	ResultLayout string
+	// API paths to fetch in addition to the ones the profile needs
+	ExtraResourcePaths []string
+	// Whether to remove whatever is in ResultDir before fetching
+	ClearResultDir bool
+	// Only report the resources that would be forbidden, don't fetch
+	Preflight bool
+	// How many resources to fetch at the same time
+	FetchConcurrency int
+	// How many MachineConfigs to list at a time
+	McPageSize int
+	// How many pages of MachineConfigs to filter at the same time
+	McConcurrency int
+	// The scan to report the fetch progress in, if any
+	ScanName string
 }
 
 func defineAPIResourceCollectorFlags(cmd *cobra.Command) {
@@ -73,6 +98,16 @@ func defineAPIResourceCollectorFlags(cmd *cobra.Command) {
 	cmd.Flags().String("warnings-output-file", "", "A file containing the warnings output.")
 	cmd.Flags().Bool("debug", false, "Print debug messages.")
 	cmd.Flags().String("platform", "", "The platform flag used by CPE detection.")
+	cmd.Flags().Duration("content-timeout", defaultContentFileTimeout, "How long to wait for the content and tailoring files.")
+	cmd.Flags().Duration("content-poll-interval", defaultContentFilePollInterval, "How often to check whether the content and tailoring files are available.")
+	cmd.Flags().String("result-layout", resultLayoutNested, "How to lay out the collected object files, either 'nested' or 'flat'.")
+	cmd.Flags().StringArray("extra-resource-path", nil, "An API path to fetch in addition to the ones the profile needs. Can be given several times.")
+	cmd.Flags().Bool("preflight", false, "Only report the resources the collector isn't allowed to fetch, without fetching anything.")
+	cmd.Flags().Bool("clear-resultdir", false, "Remove any files left in the result directory by a previous run before fetching.")
+	cmd.Flags().Int("fetch-concurrency", defaultFetchConcurrency, "How many resources to fetch at the same time.")
+	cmd.Flags().Int("mc-page-size", defaultMcPageSize, "How many MachineConfigs to list at a time.")
+	cmd.Flags().Int("mc-concurrency", defaultMcConcurrency, "How many pages of MachineConfigs to filter at the same time.")
+	cmd.Flags().String("scan-name", "", "The ComplianceScan to report the progress of fetching the resources in.")
 
 	flags := cmd.Flags()
 
@@ -89,6 +124,33 @@ func parseAPIResourceCollectorConfig(cmd *cobra.Command) *fetcherConfig {
 	conf.WarningsOutputFile = getValidStringArg(cmd, "warnings-output-file")
 	debugLog, _ = cmd.Flags().GetBool("debug")
 	conf.Tailoring, _ = cmd.Flags().GetString("tailoring")
+	conf.ContentFileTimeout, _ = cmd.Flags().GetDuration("content-timeout")
+	conf.ContentFilePollInterval, _ = cmd.Flags().GetDuration("content-poll-interval")
+	conf.ResultLayout, _ = cmd.Flags().GetString("result-layout")
+	if conf.ResultLayout != resultLayoutNested && conf.ResultLayout != resultLayoutFlat {
+		FATAL("Unknown result layout: %s", conf.ResultLayout)
+	}
+	conf.ClearResultDir, _ = cmd.Flags().GetBool("clear-resultdir")
+	conf.Preflight, _ = cmd.Flags().GetBool("preflight")
+	conf.FetchConcurrency, _ = cmd.Flags().GetInt("fetch-concurrency")
+	if conf.FetchConcurrency < 1 {
+		FATAL("The fetch concurrency must be at least 1: %d", conf.FetchConcurrency)
+	}
+	conf.McPageSize, _ = cmd.Flags().GetInt("mc-page-size")
+	if conf.McPageSize < 1 {
+		FATAL("The MachineConfig page size must be at least 1: %d", conf.McPageSize)
+	}
+	conf.McConcurrency, _ = cmd.Flags().GetInt("mc-concurrency")
+	if conf.McConcurrency < 1 {
+		FATAL("The MachineConfig concurrency must be at least 1: %d", conf.McConcurrency)
+	}
+	conf.ScanName, _ = cmd.Flags().GetString("scan-name")
+	conf.ExtraResourcePaths, _ = cmd.Flags().GetStringArray("extra-resource-path")
+	for _, resourcePath := range conf.ExtraResourcePaths {
+		if !strings.HasPrefix(resourcePath, "/") {
+			FATAL("Extra resource paths must be absolute API paths: %s", resourcePath)
+		}
+	}
 	return &conf
 }
 
@@ -125,7 +187,13 @@ func runAPIResourceCollector(cmd *cobra.Command, args []string) {
 		FATAL("Error building kubeClientSet: %v", err)
 	}
 
-	fetcher := NewDataStreamResourceFetcher(scheme, client, kubeClientSet)
+	if fetcherConf.ClearResultDir {
+		if err := clearResultDir(fetcherConf.ResultDir); err != nil {
+			FATAL("Error clearing the result directory: %v", err)
+		}
+	}
+
+	fetcher := NewDataStreamResourceFetcher(scheme, client, kubeClientSet, fetcherConf)
 
 	if err := fetcher.LoadSource(fetcherConf.Content); err != nil {
 		FATAL("Error loading source data: %v", err)
@@ -138,6 +206,24 @@ func runAPIResourceCollector(cmd *cobra.Command, args []string) {
 	if err := fetcher.FigureResources(fetcherConf.Profile); err != nil {
 		FATAL("Error finding resources: %v", err)
 	}
+	if fetcherConf.Preflight {
+		forbidden, err := fetcher.PreflightResources()
+		if err != nil {
+			FATAL("Error checking access to the resources: %v", err)
+		}
+		if warnErr := fetcher.SaveWarningsIfAny(forbidden, fetcherConf.WarningsOutputFile); warnErr != nil {
+			FATAL("Error writing warnings output file: %v", warnErr)
+		}
+		for _, msg := range forbidden {
+			LOG("%s", msg)
+		}
+		if len(forbidden) > 0 {
+			FATAL("%d resources can't be fetched", len(forbidden))
+		}
+		LOG("All the resources can be fetched")
+		return
+	}
+
 	warnings, err := fetcher.FetchResources()
 	if warnErr := fetcher.SaveWarningsIfAny(warnings, fetcherConf.WarningsOutputFile); warnErr != nil {
 		FATAL("Error writing warnings output file: %v", warnErr)
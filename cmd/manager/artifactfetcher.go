@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var ArtifactFetcherCmd = &cobra.Command{
+	Use:   "fetch-artifact",
+	Short: "Downloads a content file from an OCI artifact",
+	Long:  `Downloads the layer of an OCI artifact that holds a content file, so that the profileparser can parse it.`,
+	Run:   runArtifactFetcher,
+}
+
+func init() {
+	defineArtifactFetcherFlags(ArtifactFetcherCmd)
+}
+
+func defineArtifactFetcherFlags(cmd *cobra.Command) {
+	cmd.Flags().String("artifact", "", "Reference of the OCI artifact")
+	cmd.Flags().String("file", "", "Name of the content file in the artifact")
+	cmd.Flags().String("output", "", "Path to write the content file to")
+	cmd.Flags().Duration("timeout", 5*time.Minute, "How long to wait for the download to finish")
+}
+
+func runArtifactFetcher(cmd *cobra.Command, args []string) {
+	artifact := getValidStringArg(cmd, "artifact")
+	fileName := getValidStringArg(cmd, "file")
+	output := filepath.Clean(getValidStringArg(cmd, "output"))
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		FATAL("Couldn't create the directory for %s: %v", output, err)
+	}
+	// #nosec G304
+	f, err := os.Create(output)
+	if err != nil {
+		FATAL("Couldn't create %s: %v", output, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	client := &http.Client{}
+	err = utils.PullArtifactFile(ctx, client, artifact, fileName, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+		FATAL("Couldn't fetch %s from %s: %v", fileName, artifact, err)
+	}
+	LOG("Fetched %s from %s", fileName, artifact)
+}
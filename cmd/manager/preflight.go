@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// accessReviewer tells whether the caller is allowed to do what the spec
+// describes
+type accessReviewer interface {
+	review(ctx context.Context, spec authorizationv1.SelfSubjectAccessReviewSpec) (bool, string, error)
+}
+
+// selfSubjectAccessReviewer asks the API server using SelfSubjectAccessReviews,
+// so the answer is for the service account the collector runs as
+type selfSubjectAccessReviewer struct {
+	clientset kubernetes.Interface
+}
+
+func (r *selfSubjectAccessReviewer) review(ctx context.Context, spec authorizationv1.SelfSubjectAccessReviewSpec) (bool, string, error) {
+	ssar := &authorizationv1.SelfSubjectAccessReview{Spec: spec}
+	result, err := r.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", err
+	}
+	return result.Status.Allowed, result.Status.Reason, nil
+}
+
+// accessReviewSpecForPath returns the access review that matches reading the
+// API path the way the resource fetcher does
+func accessReviewSpecForPath(apiPath string) (authorizationv1.SelfSubjectAccessReviewSpec, error) {
+	spec := authorizationv1.SelfSubjectAccessReviewSpec{}
+	parsed, err := url.Parse(apiPath)
+	if err != nil {
+		return spec, fmt.Errorf("bad object path %s: %w", apiPath, err)
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	attrs := &authorizationv1.ResourceAttributes{}
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		attrs.Version = segments[1]
+		segments = segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		attrs.Group = segments[1]
+		attrs.Version = segments[2]
+		segments = segments[3:]
+	default:
+		// Something like /version, which isn't a resource
+		spec.NonResourceAttributes = &authorizationv1.NonResourceAttributes{
+			Path: parsed.Path,
+			Verb: "get",
+		}
+		return spec, nil
+	}
+
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		attrs.Namespace = segments[1]
+		segments = segments[2:]
+	}
+	attrs.Resource = segments[0]
+	attrs.Verb = "list"
+	if len(segments) >= 2 {
+		attrs.Name = segments[1]
+		attrs.Verb = "get"
+	}
+	if len(segments) >= 3 {
+		attrs.Subresource = segments[2]
+	}
+	spec.ResourceAttributes = attrs
+	return spec, nil
+}
+
+// findForbiddenResources reviews the access to every resource and returns a
+// message for each one the reviewer wouldn't be allowed to read
+func findForbiddenResources(ctx context.Context, reviewer accessReviewer, resources []utils.ResourcePath) ([]string, error) {
+	var forbidden []string
+	reviewed := make(map[string]bool)
+	for _, resource := range resources {
+		if reviewed[resource.ObjPath] {
+			continue
+		}
+		reviewed[resource.ObjPath] = true
+
+		spec, err := accessReviewSpecForPath(resource.ObjPath)
+		if err != nil {
+			return nil, err
+		}
+		allowed, reason, err := reviewer.review(ctx, spec)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't review the access to %s: %w", resource.ObjPath, err)
+		}
+		if allowed {
+			continue
+		}
+		msg := fmt.Sprintf("fetching %s would be forbidden", resource.ObjPath)
+		if reason != "" {
+			msg += ": " + reason
+		}
+		forbidden = append(forbidden, msg)
+	}
+	return forbidden, nil
+}
+
+func (c *scapContentDataStream) PreflightResources() ([]string, error) {
+	reviewer := &selfSubjectAccessReviewer{clientset: c.clientset}
+	return findForbiddenResources(context.Background(), reviewer, c.resources)
+}
@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// fakeReviewer allows access to the resources in allowed and denies the rest
+type fakeReviewer struct {
+	allowed  map[string]bool
+	reviewed int
+}
+
+func (f *fakeReviewer) review(_ context.Context, spec authorizationv1.SelfSubjectAccessReviewSpec) (bool, string, error) {
+	f.reviewed++
+	if spec.NonResourceAttributes != nil {
+		return f.allowed[spec.NonResourceAttributes.Path], "", nil
+	}
+	attrs := spec.ResourceAttributes
+	key := fmt.Sprintf("%s %s/%s", attrs.Verb, attrs.Group, attrs.Resource)
+	if f.allowed[key] {
+		return true, "", nil
+	}
+	return false, "RBAC: " + key + " not allowed", nil
+}
+
+var _ = Describe("Checking access to the resources before fetching", func() {
+	table.DescribeTable("mapping API paths to access reviews",
+		func(apiPath string, expected authorizationv1.SelfSubjectAccessReviewSpec) {
+			spec, err := accessReviewSpecForPath(apiPath)
+			Expect(err).To(BeNil())
+			Expect(spec).To(Equal(expected))
+		},
+		table.Entry("non-resource path", "/version", authorizationv1.SelfSubjectAccessReviewSpec{
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: "/version", Verb: "get"},
+		}),
+		table.Entry("core cluster-scoped list", "/api/v1/nodes", authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{Verb: "list", Version: "v1", Resource: "nodes"},
+		}),
+		table.Entry("namespace object", "/api/v1/namespaces/openshift-config", authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{Verb: "get", Version: "v1", Resource: "namespaces", Name: "openshift-config"},
+		}),
+		table.Entry("namespaced object with a query", "/api/v1/namespaces/openshift-config/configmaps/foo?labelSelector=a%3Db", authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{Verb: "get", Version: "v1", Namespace: "openshift-config", Resource: "configmaps", Name: "foo"},
+		}),
+		table.Entry("grouped subresource", "/apis/apps/v1/namespaces/foo/deployments/bar/scale", authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{Verb: "get", Group: "apps", Version: "v1", Namespace: "foo", Resource: "deployments", Name: "bar", Subresource: "scale"},
+		}),
+	)
+
+	It("reports the resources that would be forbidden", func() {
+		reviewer := &fakeReviewer{allowed: map[string]bool{
+			"/version":                           true,
+			"list /nodes":                        true,
+			"get config.openshift.io/apiservers": true,
+		}}
+		forbidden, err := findForbiddenResources(context.TODO(), reviewer, []utils.ResourcePath{
+			{ObjPath: "/version"},
+			{ObjPath: "/api/v1/nodes"},
+			{ObjPath: "/apis/config.openshift.io/v1/apiservers/cluster"},
+			{ObjPath: "/api/v1/namespaces/openshift-kube-apiserver/configmaps/config"},
+			{ObjPath: "/api/v1/namespaces/openshift-kube-apiserver/configmaps/config", DumpPath: "/duplicate"},
+			{ObjPath: "/healthz"},
+		})
+		Expect(err).To(BeNil())
+		Expect(reviewer.reviewed).To(Equal(5))
+		Expect(forbidden).To(Equal([]string{
+			"fetching /api/v1/namespaces/openshift-kube-apiserver/configmaps/config would be forbidden: RBAC: get /configmaps not allowed",
+			"fetching /healthz would be forbidden",
+		}))
+	})
+})
@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/spf13/cobra"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var ProfileDiffCmd = &cobra.Command{
+	Use:   "profile-diff",
+	Short: "Compares the profiles of two data streams",
+	Long:  `Lists the profiles added or removed between two data streams, and the rules added to or removed from each remaining profile.`,
+	Run:   runProfileDiff,
+}
+
+func init() {
+	defineProfileDiffFlags(ProfileDiffCmd)
+}
+
+func defineProfileDiffFlags(cmd *cobra.Command) {
+	cmd.Flags().String("old", "", "Path to the old datastream xml file")
+	cmd.Flags().String("new", "", "Path to the new datastream xml file")
+}
+
+func parseContentFile(path string) (*xmlquery.Node, error) {
+	f, err := readContent(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return utils.ParseContent(bufio.NewReader(f))
+}
+
+func runProfileDiff(cmd *cobra.Command, args []string) {
+	oldPath := getValidStringArg(cmd, "old")
+	newPath := getValidStringArg(cmd, "new")
+
+	oldContent, err := parseContentFile(oldPath)
+	if err != nil {
+		FATAL("Couldn't parse %s: %v", oldPath, err)
+	}
+	newContent, err := parseContentFile(newPath)
+	if err != nil {
+		FATAL("Couldn't parse %s: %v", newPath, err)
+	}
+
+	out, err := json.MarshalIndent(utils.DiffContentProfiles(oldContent, newContent), "", "  ")
+	if err != nil {
+		FATAL("Couldn't render the diff: %v", err)
+	}
+	fmt.Println(string(out))
+}
@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/spf13/cobra"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var ProfileListCmd = &cobra.Command{
+	Use:   "profile-list",
+	Short: "Lists the profiles of a data stream",
+	Long:  `Lists the profiles of a data stream or, if a profile is given, the rules it selects and the profile it extends.`,
+	Run:   runProfileList,
+}
+
+func init() {
+	defineProfileListFlags(ProfileListCmd)
+}
+
+func defineProfileListFlags(cmd *cobra.Command) {
+	cmd.Flags().String("content", "", "Path to the datastream xml file")
+	cmd.Flags().String("profile", "", "The ID of the profile to show the rules of")
+	cmd.Flags().String("output", "text", "The output format, either 'text' or 'json'")
+}
+
+func runProfileList(cmd *cobra.Command, args []string) {
+	contentPath := getValidStringArg(cmd, "content")
+	profile, _ := cmd.Flags().GetString("profile")
+	format, _ := cmd.Flags().GetString("output")
+
+	content, err := parseContentFile(contentPath)
+	if err != nil {
+		FATAL("Couldn't parse %s: %v", contentPath, err)
+	}
+	if err := writeProfileList(os.Stdout, content, profile, format); err != nil {
+		FATAL("%v", err)
+	}
+}
+
+// writeProfileList writes the profiles of the content, or the details of a
+// single profile if one is given, in the given format
+func writeProfileList(w io.Writer, content *xmlquery.Node, profile, format string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+
+	if profile == "" {
+		profiles := utils.ListContentProfiles(content)
+		if format == "json" {
+			return writeJSON(w, profiles)
+		}
+		for _, p := range profiles {
+			if _, err := fmt.Fprintf(w, "%s\t%s\n", p.ID, p.Title); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	described, err := utils.DescribeContentProfile(content, profile)
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		return writeJSON(w, described)
+	}
+	fmt.Fprintf(w, "Profile: %s\n", described.ID)
+	if described.Title != "" {
+		fmt.Fprintf(w, "Title: %s\n", described.Title)
+	}
+	if described.Extends != "" {
+		fmt.Fprintf(w, "Extends: %s\n", described.Extends)
+	}
+	fmt.Fprintf(w, "Rules (%d):\n", len(described.Rules))
+	for _, rule := range described.Rules {
+		if _, err := fmt.Fprintf(w, "  %s\n", rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
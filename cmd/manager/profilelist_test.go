@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+var _ = Describe("Listing the profiles of a data stream", func() {
+	const e8 = "xccdf_org.ssgproject.content_profile_e8"
+	var out *bytes.Buffer
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+	})
+
+	listProfiles := func(profile, format string) error {
+		content, err := parseContentFile("../../tests/data/ssg-ocp4-ds-new.xml")
+		Expect(err).To(BeNil())
+		return writeProfileList(out, content, profile, format)
+	}
+
+	It("lists the profile IDs and titles", func() {
+		Expect(listProfiles("", "text")).To(Succeed())
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Expect(lines).To(HaveLen(6))
+		Expect(lines).To(ContainElement(e8 + "\tAustralian Cyber Security Centre (ACSC) Essential Eight"))
+	})
+
+	It("shows the rules of a profile as JSON", func() {
+		Expect(listProfiles(e8, "json")).To(Succeed())
+		profile := utils.ContentProfile{}
+		Expect(json.Unmarshal(out.Bytes(), &profile)).To(Succeed())
+		Expect(profile.ID).To(Equal(e8))
+		Expect(profile.Rules).To(HaveLen(51))
+		Expect(profile.Rules).To(ContainElement("xccdf_org.ssgproject.content_rule_accounts_no_uid_except_zero"))
+	})
+
+	It("shows the rules of a profile as text", func() {
+		Expect(listProfiles(e8, "text")).To(Succeed())
+		Expect(out.String()).To(HavePrefix("Profile: " + e8 + "\n"))
+		Expect(out.String()).To(ContainSubstring("Rules (51):\n  xccdf_org.ssgproject.content_rule_accounts_no_uid_except_zero\n"))
+	})
+
+	It("fails for unknown profiles and formats", func() {
+		Expect(listProfiles("xccdf_org.ssgproject.content_profile_unknown", "text")).ToNot(Succeed())
+		Expect(listProfiles("", "yaml")).ToNot(Succeed())
+	})
+})
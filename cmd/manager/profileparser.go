@@ -8,7 +8,6 @@ import (
 	"os"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
-	"github.com/antchfx/xmlquery"
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -17,6 +16,7 @@ import (
 
 	cmpv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/profileparser"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
 )
 
 var ProfileparserCmd = &cobra.Command{
@@ -34,6 +34,7 @@ func defineProfileParserFlags(cmd *cobra.Command) {
 	cmd.Flags().String("ds-path", "/content/ssg-ocp4-ds.xml", "Path to the datastream xml file")
 	cmd.Flags().String("name", "", "Name of the ProfileBundle object")
 	cmd.Flags().String("namespace", "", "Namespace of the ProfileBundle object")
+	cmd.Flags().Bool("debug", false, "Log debug information about the parsed profiles, rules and variables")
 
 	flags := cmd.Flags()
 
@@ -52,7 +53,8 @@ func newParserConfig(cmd *cobra.Command) *profileparser.ParserConfig {
 	pcfg.ProfileBundleKey.Name = getValidStringArg(cmd, "name")
 	pcfg.ProfileBundleKey.Namespace = getValidStringArg(cmd, "namespace")
 
-	logf.SetLogger(zap.New())
+	debug, _ := cmd.Flags().GetBool("debug")
+	logf.SetLogger(zap.New(zap.UseDevMode(debug)))
 
 	printVersion()
 
@@ -87,14 +89,15 @@ func getProfileBundle(pcfg *profileparser.ParserConfig) (*cmpv1alpha1.ProfileBun
 }
 
 // updateProfileBundleStatus updates the status of the given ProfileBundle. If
-// the given error is nil, the status will be valid, else it'll be invalid
-func updateProfileBundleStatus(pcfg *profileparser.ParserConfig, pb *cmpv1alpha1.ProfileBundle, err error) {
+// the given error is nil, the status will be valid, else it'll be invalid.
+// The duplicates are the rule IDs that several rules of the content share.
+func updateProfileBundleStatus(pcfg *profileparser.ParserConfig, pb *cmpv1alpha1.ProfileBundle, duplicates []string, err error) {
 	if err != nil {
 		// Never update a fetched object, always just a copy
 		pbCopy := pb.DeepCopy()
 		pbCopy.Status.DataStreamStatus = cmpv1alpha1.DataStreamInvalid
 		pbCopy.Status.ErrorMessage = err.Error()
-		pbCopy.Status.SetConditionInvalid()
+		pbCopy.Status.SetConditionInvalid(cmpv1alpha1.ProfileBundleReasonParseFailed, pbCopy.Status.ErrorMessage)
 		err = pcfg.Client.Status().Update(context.TODO(), pbCopy)
 		if err != nil {
 			cmdLog.Error(err, "Couldn't update ProfileBundle status")
@@ -105,6 +108,7 @@ func updateProfileBundleStatus(pcfg *profileparser.ParserConfig, pb *cmpv1alpha1
 		pbCopy := pb.DeepCopy()
 		pbCopy.Status.DataStreamStatus = cmpv1alpha1.DataStreamValid
 		pbCopy.Status.SetConditionReady()
+		pbCopy.Status.SetConditionDuplicateRuleIDs(duplicates)
 		err = pcfg.Client.Status().Update(context.TODO(), pbCopy)
 		if err != nil {
 			cmdLog.Error(err, "Couldn't update ProfileBundle status")
@@ -126,14 +130,14 @@ func runProfileParser(cmd *cobra.Command, args []string) {
 	contentFile, err := readContent(pcfg.DataStreamPath)
 	if err != nil {
 		cmdLog.Error(err, "Couldn't read the content")
-		updateProfileBundleStatus(pcfg, pb, fmt.Errorf("Couldn't read content file: %s", err))
+		updateProfileBundleStatus(pcfg, pb, nil, fmt.Errorf("Couldn't read content file: %s", err))
 		os.Exit(1)
 	}
 	bufContentFile := bufio.NewReader(contentFile)
-	contentDom, err := xmlquery.Parse(bufContentFile)
+	contentDom, err := utils.ParseDataStream(bufContentFile)
 	if err != nil {
 		cmdLog.Error(err, "Couldn't read the content XML")
-		updateProfileBundleStatus(pcfg, pb, fmt.Errorf("Couldn't read content XML: %s", err))
+		updateProfileBundleStatus(pcfg, pb, nil, fmt.Errorf("Couldn't read content XML: %s", err))
 		if closeErr := contentFile.Close(); closeErr != nil {
 			cmdLog.Error(err, "Couldn't close the content file")
 		}
@@ -144,7 +148,7 @@ func runProfileParser(cmd *cobra.Command, args []string) {
 
 	// The err variable might be nil, this is fine, it'll just update the status
 	// to valid
-	updateProfileBundleStatus(pcfg, pb, err)
+	updateProfileBundleStatus(pcfg, pb, utils.FindDuplicateRuleIDs(contentDom), err)
 
 	if err != nil {
 		cmdLog.Error(err, "Parsing the bundle failed, will restart the container")
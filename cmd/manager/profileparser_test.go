@@ -0,0 +1,81 @@
+package manager
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/profileparser"
+)
+
+var _ = Describe("Updating the ProfileBundle status after parsing", func() {
+	var pcfg *profileparser.ParserConfig
+	var pb *compv1alpha1.ProfileBundle
+
+	BeforeEach(func() {
+		pb = &compv1alpha1.ProfileBundle{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ocp4",
+				Namespace: "openshift-compliance",
+			},
+		}
+		pcfg = &profileparser.ParserConfig{
+			ProfileBundleKey: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			Client: fake.NewClientBuilder().
+				WithScheme(getScheme()).
+				WithRuntimeObjects(pb).
+				WithStatusSubresource(pb).
+				Build(),
+		}
+	})
+
+	getBundle := func() *compv1alpha1.ProfileBundle {
+		updated := &compv1alpha1.ProfileBundle{}
+		Expect(pcfg.Client.Get(context.TODO(), pcfg.ProfileBundleKey, updated)).To(Succeed())
+		return updated
+	}
+
+	It("marks the bundle as degraded when the content couldn't be parsed", func() {
+		updateProfileBundleStatus(pcfg, getBundle(), nil, errors.New("no profiles in the data stream"))
+
+		updated := getBundle()
+		Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamInvalid))
+		Expect(updated.Status.ErrorMessage).To(Equal("no profiles in the data stream"))
+		cond := updated.Status.Conditions.GetCondition(compv1alpha1.ProfileBundleConditionDegraded)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.IsTrue()).To(BeTrue())
+		Expect(cond.Reason).To(Equal(compv1alpha1.ProfileBundleReasonParseFailed))
+		Expect(cond.Message).To(Equal("no profiles in the data stream"))
+	})
+
+	It("clears the degraded condition when the content was parsed", func() {
+		updateProfileBundleStatus(pcfg, getBundle(), nil, nil)
+
+		updated := getBundle()
+		Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamValid))
+		Expect(updated.Status.Conditions.IsFalseFor(compv1alpha1.ProfileBundleConditionDegraded)).To(BeTrue())
+		Expect(updated.Status.Conditions.IsTrueFor("Ready")).To(BeTrue())
+		Expect(updated.Status.Conditions.GetCondition(compv1alpha1.ProfileBundleConditionDuplicateRuleIDs)).To(BeNil())
+	})
+
+	It("reports the rule IDs that several rules of the content share", func() {
+		updateProfileBundleStatus(pcfg, getBundle(), []string{"rule_a", "rule_b"}, nil)
+
+		updated := getBundle()
+		Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamValid))
+		cond := updated.Status.Conditions.GetCondition(compv1alpha1.ProfileBundleConditionDuplicateRuleIDs)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.IsTrue()).To(BeTrue())
+		Expect(cond.Message).To(ContainSubstring("rule_a, rule_b"))
+
+		// Fixing the content clears the condition on the next parse
+		updateProfileBundleStatus(pcfg, updated, nil, nil)
+		Expect(getBundle().Status.Conditions.GetCondition(compv1alpha1.ProfileBundleConditionDuplicateRuleIDs)).To(BeNil())
+	})
+})
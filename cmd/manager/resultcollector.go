@@ -16,6 +16,7 @@ limitations under the License.
 package manager
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
 	"context"
@@ -25,6 +26,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httputil"
 	"os"
@@ -78,6 +80,9 @@ type scapresultsConfig struct {
 	Cert               string
 	Key                string
 	CA                 string
+	// Where the api-resource-collector saved the fetched resources. Only
+	// set if they should be stored alongside the raw results.
+	FetchedResourcesDir string
 }
 
 func defineResultcollectorFlags(cmd *cobra.Command) {
@@ -95,6 +100,7 @@ func defineResultcollectorFlags(cmd *cobra.Command) {
 	cmd.Flags().String("tls-client-cert", "", "The path to the client and CA PEM cert bundle.")
 	cmd.Flags().String("tls-client-key", "", "The path to the client PEM key.")
 	cmd.Flags().String("tls-ca", "", "The path to the CA certificate.")
+	cmd.Flags().String("fetched-resources-dir", "", "The directory with the fetched API resources to upload along with the results.")
 
 	flags := cmd.Flags()
 
@@ -122,6 +128,7 @@ func parseConfig(cmd *cobra.Command) *scapresultsConfig {
 		conf.ResultServerURI = "http://" + conf.ScanName + "-rs:8080/"
 	}
 	conf.WarningsOutputFile, _ = cmd.Flags().GetString("warnings-output-file")
+	conf.FetchedResourcesDir, _ = cmd.Flags().GetString("fetched-resources-dir")
 
 	// platform scans have no node name
 	conf.NodeName, _ = cmd.Flags().GetString("node-name")
@@ -296,6 +303,10 @@ func readWarningsFile(filename string) string {
 }
 
 func uploadToResultServer(arfContents *resultFileContents, scapresultsconf *scapresultsConfig) error {
+	return uploadFileToResultServer(arfContents, scapresultsconf.ConfigMapName, "application/xml", scapresultsconf)
+}
+
+func uploadFileToResultServer(contents *resultFileContents, reportName, contentType string, scapresultsconf *scapresultsConfig) error {
 	return backoff.Retry(func() error {
 		url := scapresultsconf.ResultServerURI
 		cmdLog.Info("Trying to upload to resultserver", "url", url)
@@ -305,10 +316,10 @@ func uploadToResultServer(arfContents *resultFileContents, scapresultsconf *scap
 			return err
 		}
 		client := &http.Client{Transport: transport}
-		req, _ := http.NewRequest("POST", url, arfContents.contents)
-		req.Header.Add("Content-Type", "application/xml")
-		req.Header.Add("X-Report-Name", scapresultsconf.ConfigMapName)
-		if arfContents.compressed {
+		req, _ := http.NewRequest("POST", url, contents.contents)
+		req.Header.Add("Content-Type", contentType)
+		req.Header.Add("X-Report-Name", reportName)
+		if contents.compressed {
 			req.Header.Add("Content-Encoding", "bzip2")
 		}
 		resp, err := client.Do(req)
@@ -406,6 +417,75 @@ func handleCompleteSCAPResults(exitcode string, scapresultsconf *scapresultsConf
 		wg.Done()
 	}()
 	wg.Wait()
+
+	if scapresultsconf.FetchedResourcesDir != "" {
+		// The fetched resources are only a debugging aid, failing to
+		// store them shouldn't fail the scan.
+		if err := uploadFetchedResources(scapresultsconf); err != nil {
+			cmdLog.Error(err, "Failed to upload the fetched resources to resultserver")
+		} else {
+			cmdLog.Info("Uploaded the fetched resources to resultserver")
+		}
+	}
+}
+
+// uploadFetchedResources uploads a compressed tarball of the resources the
+// scan was evaluated against to the resultserver
+func uploadFetchedResources(scapresultsconf *scapresultsConfig) error {
+	archive, err := archiveDirectory(scapresultsconf.FetchedResourcesDir)
+	if err != nil {
+		return err
+	}
+	compressed, err := compressResults(archive)
+	if err != nil {
+		return err
+	}
+	contents := &resultFileContents{contents: compressed, compressed: true}
+	return uploadFileToResultServer(contents, scapresultsconf.ConfigMapName+"-resources", "application/x-tar", scapresultsconf)
+}
+
+// archiveDirectory returns a tarball of all the regular files under rootDir,
+// named relative to it
+func archiveDirectory(rootDir string) (io.Reader, error) {
+	var buffer bytes.Buffer
+	tw := tar.NewWriter(&buffer)
+	err := filepath.WalkDir(rootDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(rootDir, filePath)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(relPath)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		contents, err := os.ReadFile(filepath.Clean(filePath))
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(contents)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return &buffer, nil
 }
 
 func handleErrorInOscapRun(exitcode string, scapresultsconf *scapresultsConfig, client *complianceCrClient) {
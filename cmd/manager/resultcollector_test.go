@@ -1,9 +1,13 @@
 package manager
 
 import (
+	"archive/tar"
+	"io"
 	"os"
 	"time"
 
+	"github.com/dsnet/compress/bzip2"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -57,4 +61,46 @@ var _ = Describe("Resultcollector", func() {
 			Expect(err).To(BeEquivalentTo(timeoutErr))
 		})
 	})
+
+	Context("Archiving the fetched resources", func() {
+		var rootDir string
+		BeforeEach(func() {
+			var err error
+			rootDir, err = os.MkdirTemp("", "resources")
+			Expect(err).To(BeNil())
+			Expect(saveResources(rootDir, map[string][]byte{
+				"/apis/foo/bar": []byte("bar"),
+				"/version":      []byte("1.0"),
+			})).To(Succeed())
+		})
+		AfterEach(func() {
+			os.RemoveAll(rootDir)
+		})
+
+		It("archives every file relative to the root directory", func() {
+			archive, err := archiveDirectory(rootDir)
+			Expect(err).To(BeNil())
+			compressed, err := compressResults(archive)
+			Expect(err).To(BeNil())
+			decompressed, err := bzip2.NewReader(compressed, nil)
+			Expect(err).To(BeNil())
+
+			files := map[string]string{}
+			tr := tar.NewReader(decompressed)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				Expect(err).To(BeNil())
+				contents, err := io.ReadAll(tr)
+				Expect(err).To(BeNil())
+				files[hdr.Name] = string(contents)
+			}
+			Expect(files).To(Equal(map[string]string{
+				"apis/foo/bar": "bar",
+				"version":      "1.0",
+			}))
+		})
+	})
 })
@@ -63,6 +63,7 @@ func defineResultServerFlags(cmd *cobra.Command) {
 	cmd.Flags().String("tls-server-key", "", "Path to the server key")
 	cmd.Flags().String("tls-ca", "", "Path to the CA certificate")
 	cmd.Flags().Uint16("rotation", 3, "Amount of raw result directories to keep")
+	cmd.Flags().Duration("max-age", 0, "How long to keep raw result directories. Takes precedence over the rotation when set.")
 
 	flags := cmd.Flags()
 
@@ -80,12 +81,14 @@ type resultServerConfig struct {
 	Key      string
 	CA       string
 	Rotation uint16
+	MaxAge   time.Duration
 }
 
 func parseResultServerConfig(cmd *cobra.Command) *resultServerConfig {
 	basePath := getValidStringArg(cmd, "path")
 	index := getValidStringArg(cmd, "scan-index")
 	rotation, _ := cmd.Flags().GetUint16("rotation")
+	maxAge, _ := cmd.Flags().GetDuration("max-age")
 	conf := &resultServerConfig{
 		Address:  getValidStringArg(cmd, "address"),
 		Port:     getValidStringArg(cmd, "port"),
@@ -95,6 +98,7 @@ func parseResultServerConfig(cmd *cobra.Command) *resultServerConfig {
 		Key:      getValidStringArg(cmd, "tls-server-key"),
 		CA:       getValidStringArg(cmd, "tls-ca"),
 		Rotation: rotation,
+		MaxAge:   maxAge,
 	}
 
 	logf.SetLogger(zap.New())
@@ -112,12 +116,9 @@ func ensureDir(path string) error {
 	return nil
 }
 
-func rotateResultDirectories(rootPath string, rotation uint16) error {
-	// If rotation is a negative number, we don't rotate
-	if rotation == 0 {
-		cmdLog.Info("Rotation policy set to '0'. No need to rotate.")
-		return nil
-	}
+// listResultDirectories returns the raw result directories under rootPath,
+// newest first
+func listResultDirectories(rootPath string) ([]utils.Directory, error) {
 	dirs := []utils.Directory{}
 	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -140,17 +141,16 @@ func rotateResultDirectories(rootPath string, rotation uint16) error {
 		return nil
 	})
 	if err != nil {
-		cmdLog.Error(err, "Couldn't rotate directories")
-		return err
+		return nil, err
 	}
 	sort.Slice(dirs, func(i, j int) bool { return dirs[i].CreationTime.After(dirs[j].CreationTime) })
+	return dirs, nil
+}
+
+func removeResultDirectories(dirs []utils.Directory, reason string) error {
 	var lastError error
-	// No need to rotate, we're whithin the policy
-	if len(dirs) <= int(rotation) {
-		return nil
-	}
-	for _, dir := range dirs[rotation:] {
-		cmdLog.Info("Removing directory because of rotation policy", "directory", dir.Path)
+	for _, dir := range dirs {
+		cmdLog.Info("Removing directory because of "+reason, "directory", dir.Path)
 		err := os.RemoveAll(dir.Path)
 		if err != nil {
 			lastError = err
@@ -159,6 +159,45 @@ func rotateResultDirectories(rootPath string, rotation uint16) error {
 	return lastError
 }
 
+func rotateResultDirectories(rootPath string, rotation uint16) error {
+	// If rotation is a negative number, we don't rotate
+	if rotation == 0 {
+		cmdLog.Info("Rotation policy set to '0'. No need to rotate.")
+		return nil
+	}
+	dirs, err := listResultDirectories(rootPath)
+	if err != nil {
+		cmdLog.Error(err, "Couldn't rotate directories")
+		return err
+	}
+	// No need to rotate, we're whithin the policy
+	if len(dirs) <= int(rotation) {
+		return nil
+	}
+	return removeResultDirectories(dirs[rotation:], "rotation policy")
+}
+
+// expireResultDirectories removes the result directories that are older than
+// maxAge at the given time. The newest directory is always kept, as it holds
+// the results of the scan that's running.
+func expireResultDirectories(rootPath string, maxAge time.Duration, now time.Time) error {
+	dirs, err := listResultDirectories(rootPath)
+	if err != nil {
+		cmdLog.Error(err, "Couldn't expire directories")
+		return err
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+	expired := []utils.Directory{}
+	for _, dir := range dirs[1:] {
+		if now.Sub(dir.CreationTime) > maxAge {
+			expired = append(expired, dir)
+		}
+	}
+	return removeResultDirectories(expired, "maximum age")
+}
+
 func server(c *resultServerConfig) {
 	exit := make(chan os.Signal, 1)
 	signal.Notify(exit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -169,7 +208,11 @@ func server(c *resultServerConfig) {
 		os.Exit(1)
 	}
 
-	rotateResultDirectories(c.BasePath, c.Rotation)
+	if c.MaxAge > 0 {
+		expireResultDirectories(c.BasePath, c.MaxAge, time.Now())
+	} else {
+		rotateResultDirectories(c.BasePath, c.Rotation)
+	}
 
 	caCert, err := os.ReadFile(c.CA)
 	if err != nil {
@@ -210,7 +253,12 @@ func server(c *resultServerConfig) {
 			extraExtension = "." + extraExtension
 		}
 		// TODO(jaosorior): Check that content-type is application/xml
-		filePath := path.Join(c.Path, filename+".xml"+extraExtension)
+		extension := ".xml"
+		if r.Header.Get("Content-Type") == "application/x-tar" {
+			// The API resources a platform scan was evaluated against
+			extension = ".tar"
+		}
+		filePath := path.Join(c.Path, filename+extension+extraExtension)
 		cleanPath := filepath.Clean(filePath)
 		f, err := os.Create(cleanPath)
 		if err != nil {
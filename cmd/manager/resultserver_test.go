@@ -176,4 +176,67 @@ var _ = Describe("Resultserver testing", func() {
 			})
 		}
 	})
+
+	Context("Raw result directory expiry", func() {
+		var rootDir string
+		var now time.Time
+		var oldDir, recentDir, newestDir, lostFoundDir string
+
+		mkdirWithAge := func(name string, age time.Duration) string {
+			dir := path.Join(rootDir, name)
+			Expect(os.Mkdir(dir, 0750)).To(Succeed())
+			mtime := now.Add(-age)
+			Expect(os.Chtimes(dir, mtime, mtime)).To(Succeed())
+			return dir
+		}
+
+		BeforeEach(func() {
+			var err error
+			rootDir, err = os.MkdirTemp("", "expire-root")
+			Expect(err).To(BeNil())
+			now = time.Now()
+
+			lostFoundDir = mkdirWithAge("lost+found", 90*24*time.Hour)
+			oldDir = mkdirWithAge("0", 40*24*time.Hour)
+			recentDir = mkdirWithAge("1", 10*24*time.Hour)
+			newestDir = mkdirWithAge("2", time.Minute)
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(rootDir)
+		})
+
+		It("Removes the directories older than the maximum age", func() {
+			err := expireResultDirectories(rootDir, 30*24*time.Hour, now)
+			Expect(err).To(BeNil())
+
+			files := _readDirNames(rootDir)
+			Expect(files).To(ConsistOf(path.Base(recentDir), path.Base(newestDir), path.Base(lostFoundDir)))
+			Expect(oldDir).NotTo(BeADirectory())
+		})
+
+		It("Keeps every directory that is within the maximum age", func() {
+			err := expireResultDirectories(rootDir, 60*24*time.Hour, now)
+			Expect(err).To(BeNil())
+
+			files := _readDirNames(rootDir)
+			Expect(files).To(HaveLen(4))
+		})
+
+		It("Always keeps the newest directory", func() {
+			err := expireResultDirectories(rootDir, time.Second, now)
+			Expect(err).To(BeNil())
+
+			files := _readDirNames(rootDir)
+			Expect(files).To(ConsistOf(path.Base(newestDir), path.Base(lostFoundDir)))
+		})
+
+		It("Ignores the ages when rotating by count", func() {
+			err := rotateResultDirectories(rootDir, 2)
+			Expect(err).To(BeNil())
+
+			files := _readDirNames(rootDir)
+			Expect(files).To(ConsistOf(path.Base(recentDir), path.Base(newestDir), path.Base(lostFoundDir)))
+		})
+	})
 })
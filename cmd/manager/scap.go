@@ -24,10 +24,13 @@ import (
 	"fmt"
 	"html"
 	"io"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
@@ -37,24 +40,152 @@ import (
 	runtimejson "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
 	"github.com/antchfx/xmlquery"
 	"github.com/itchyny/gojq"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 )
 
 const (
-	contentFileTimeout = 3600
-	valuePrefix        = "xccdf_org.ssgproject.content_value_"
+	valuePrefix = "xccdf_org.ssgproject.content_value_"
+
+	defaultContentFileTimeout      = 3600 * time.Second
+	defaultContentFilePollInterval = 1 * time.Second
+	// Stays within the default client-side rate limit burst
+	defaultFetchConcurrency = 5
+	// How often the fetch progress is reported in the scan
+	defaultFetchProgressInterval = 5 * time.Second
+	// How many MachineConfigs to list at a time and how many of the listed
+	// pages to filter at the same time
+	defaultMcPageSize    = 5
+	defaultMcConcurrency = 1
+
+	// resultLayoutNested saves every resource under a directory tree
+	// mirroring its API path. This is what OpenSCAP expects.
+	resultLayoutNested = "nested"
+	// resultLayoutFlat saves every resource directly in the result directory
+	// under its URL-encoded API path, along with a manifest mapping the file
+	// names back to the API paths.
+	resultLayoutFlat = "flat"
+	// flatLayoutManifestName is the name of the manifest written in the flat
+	// layout
+	flatLayoutManifestName = "manifest.json"
 )
 
 var (
 	MoreThanOneObjErr = errors.New("more than one object returned from the filter")
 	NullValErr        = errors.New("no value was returned from the filter")
+	// ContentFileTimeoutErr is returned when the content or tailoring file
+	// didn't show up before the configured timeout.
+	ContentFileTimeoutErr = errors.New("timed out waiting for content file")
+	// ErrSourceNotLoaded is returned when resources are figured out before
+	// the source data stream, which holds the rule definitions, was loaded.
+	ErrSourceNotLoaded = errors.New("the source data stream wasn't loaded")
+	// ErrResourceTypeAbsent marks resources that couldn't be fetched because
+	// their type doesn't exist on this cluster, such as the CRD of an
+	// operator that isn't installed. The results of the checks depending on
+	// them point that out.
+	ErrResourceTypeAbsent = errors.New("resource type is absent from the cluster")
+	// ErrResourceNotFound marks objects that couldn't be fetched because they
+	// don't exist, even though their type does. Checks may well expect that,
+	// so their results don't point it out.
+	ErrResourceNotFound = errors.New("resource was not found")
+	// ErrResourceForbidden marks resources that couldn't be fetched because
+	// the api-resource-collector lacks the RBAC permissions to read them.
+	ErrResourceForbidden = errors.New("access to resource is forbidden")
+	// ErrResourceUnavailable marks resources that couldn't be fetched because
+	// the API server kept failing with transient errors, even after retrying.
+	ErrResourceUnavailable = errors.New("resource is temporarily unavailable")
+
+	// fetchRetryBackoff is how fetching a resource is retried after a
+	// transient error
+	fetchRetryBackoff = wait.Backoff{
+		Steps:    4,
+		Duration: 1 * time.Second,
+		Factor:   2.0,
+		Jitter:   0.1,
+	}
 )
 
+// resourceFetchError records a non-fatal failure to fetch a resource along
+// with its classification, so that callers can use errors.Is to tell an
+// absent resource from a forbidden one.
+type resourceFetchError struct {
+	uri   string
+	class error
+	err   error
+}
+
+func (e *resourceFetchError) Error() string {
+	return fmt.Sprintf("could not fetch %s: %s", e.uri, e.err)
+}
+
+func (e *resourceFetchError) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+// classifyFetchError maps the API errors that are not fatal for a fetch to
+// ErrResourceTypeAbsent, ErrResourceNotFound, ErrResourceForbidden or
+// ErrResourceUnavailable. Any other error, including nil, yields nil.
+func classifyFetchError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case meta.IsNoMatchError(err), kerrors.IsNotFound(err) && !isObjectNotFound(err):
+		return ErrResourceTypeAbsent
+	case kerrors.IsNotFound(err):
+		return ErrResourceNotFound
+	case kerrors.IsForbidden(err):
+		return ErrResourceForbidden
+	case isTransientFetchError(err):
+		return ErrResourceUnavailable
+	}
+	return nil
+}
+
+// fetchWarningReason returns why a resource couldn't be fetched, for errors
+// that classifyFetchError classifies
+func fetchWarningReason(err error) string {
+	switch {
+	case meta.IsNoMatchError(err):
+		return fetchWarningNoMatch
+	case kerrors.IsNotFound(err):
+		return fetchWarningNotFound
+	case kerrors.IsForbidden(err):
+		return fetchWarningForbidden
+	case isTransientFetchError(err):
+		return fetchWarningTimeout
+	}
+	return ""
+}
+
+// isObjectNotFound returns whether a 404 is about a single object that
+// doesn't exist. The API server names the object in the details of the
+// error, while a 404 for an unknown type, such as a CRD that isn't
+// installed, has no name.
+func isObjectNotFound(err error) bool {
+	var status kerrors.APIStatus
+	if !errors.As(err, &status) {
+		return false
+	}
+	details := status.Status().Details
+	return details != nil && details.Name != ""
+}
+
+// isTransientFetchError returns whether the error is likely to go away when
+// the fetch is retried, such as when the API server is throttling us or is
+// temporarily unavailable.
+func isTransientFetchError(err error) bool {
+	return kerrors.IsServiceUnavailable(err) || kerrors.IsTooManyRequests(err) ||
+		kerrors.IsTimeout(err) || kerrors.IsServerTimeout(err)
+}
+
 // resourceFetcherClients just gathers several needed structs together so we can
 // pass them on easily to functions
 type resourceFetcherClients struct {
@@ -73,15 +204,50 @@ type scapContentDataStream struct {
 	tailoring  *xmlquery.Node
 	resources  []utils.ResourcePath
 	found      map[string][]byte
+	// How long to wait for the content files and how often to check for them
+	contentFileTimeout      time.Duration
+	contentFilePollInterval time.Duration
+	// How to lay out the fetched resources in the result directory
+	resultLayout string
+	// API paths to fetch regardless of the profile
+	extraResourcePaths []string
+	// How many resources to fetch at the same time
+	fetchConcurrency int
+	// How many MachineConfigs to list at a time and how many pages of them
+	// to filter at the same time
+	mcPageSize    int
+	mcConcurrency int
+	// Reports the progress of fetching the resources, may be nil
+	fetchProgress fetchProgressFunc
+	// Counts the resources that couldn't be fetched by reason, may be nil
+	fetchWarnings *scanFetchWarningReporter
+	// The IDs of the selected rules that check each API path
+	resourceRules map[string][]string
 }
 
-func NewDataStreamResourceFetcher(scheme *runtime.Scheme, client runtimeclient.Client, clientSet *kubernetes.Clientset) ResourceFetcher {
+func NewDataStreamResourceFetcher(scheme *runtime.Scheme, client runtimeclient.Client, clientSet *kubernetes.Clientset, conf *fetcherConfig) ResourceFetcher {
+	var fetchProgress fetchProgressFunc
+	var fetchWarnings *scanFetchWarningReporter
+	if conf.ScanName != "" {
+		reporter := newScanFetchProgressReporter(client, os.Getenv("POD_NAMESPACE"), conf.ScanName, defaultFetchProgressInterval)
+		fetchProgress = reporter.report
+		fetchWarnings = newScanFetchWarningReporter(client, os.Getenv("POD_NAMESPACE"), conf.ScanName)
+	}
 	return &scapContentDataStream{
 		resourceFetcherClients: resourceFetcherClients{
 			clientset: clientSet,
 			client:    client,
 			scheme:    scheme,
 		},
+		contentFileTimeout:      conf.ContentFileTimeout,
+		contentFilePollInterval: conf.ContentFilePollInterval,
+		resultLayout:            conf.ResultLayout,
+		extraResourcePaths:      conf.ExtraResourcePaths,
+		fetchConcurrency:        conf.FetchConcurrency,
+		mcPageSize:              conf.McPageSize,
+		mcConcurrency:           conf.McConcurrency,
+		fetchProgress:           fetchProgress,
+		fetchWarnings:           fetchWarnings,
 	}
 }
 
@@ -90,6 +256,9 @@ func (c *scapContentDataStream) LoadSource(path string) error {
 	if err != nil {
 		return err
 	}
+	if err := utils.ValidateDataStream(xml); err != nil {
+		return err
+	}
 	c.dataStream = xml
 	return nil
 }
@@ -104,7 +273,7 @@ func (c *scapContentDataStream) LoadTailoring(path string) error {
 }
 
 func (c *scapContentDataStream) loadContent(path string) (*xmlquery.Node, error) {
-	f, err := openNonEmptyFile(path)
+	f, err := openNonEmptyFile(path, c.contentFileTimeout, c.contentFilePollInterval)
 	if err != nil {
 		return nil, err
 	}
@@ -118,47 +287,45 @@ func parseContent(f *os.File) (*xmlquery.Node, error) {
 }
 
 // Returns the file, but only after it has been created by the other init container.
-// This avoids a race.
-func openNonEmptyFile(filename string) (*os.File, error) {
-	readFileTimeoutChan := make(chan *os.File, 1)
-
+// This avoids a race. The file is polled every pollInterval until it has contents
+// or the timeout expires.
+func openNonEmptyFile(filename string, timeout, pollInterval time.Duration) (*os.File, error) {
 	// gosec complains that the file is passed through an evironment variable. But
 	// this is not a security issue because none of the files are user-provided
 	cleanFileName := filepath.Clean(filename)
+	deadline := time.After(timeout)
 
-	go func() {
-		for {
-			// Note that we're cleaning the filename path above.
-			// #nosec
-			file, err := os.Open(cleanFileName)
-			if err == nil {
-				fileinfo, err := file.Stat()
-				// Only try to use the file if it already has contents.
-				if err == nil && fileinfo.Size() > 0 {
-					readFileTimeoutChan <- file
-				}
-			} else if !os.IsNotExist(err) {
-				fmt.Println(err)
-				os.Exit(1)
+	for {
+		// Note that we're cleaning the filename path above.
+		// #nosec
+		file, err := os.Open(cleanFileName)
+		if err == nil {
+			fileinfo, err := file.Stat()
+			// Only try to use the file if it already has contents.
+			if err == nil && fileinfo.Size() > 0 {
+				fmt.Printf("File '%s' found, using.\n", filename)
+				return file, nil
 			}
-			time.Sleep(1 * time.Second)
+			file.Close()
+		} else if !os.IsNotExist(err) {
+			return nil, err
 		}
-	}()
 
-	select {
-	case file := <-readFileTimeoutChan:
-		fmt.Printf("File '%s' found, using.\n", filename)
-		return file, nil
-	case <-time.After(time.Duration(contentFileTimeout) * time.Second):
-		fmt.Println("Timeout. Aborting.")
-		os.Exit(1)
+		select {
+		case <-deadline:
+			return nil, fmt.Errorf("%w: %s", ContentFileTimeoutErr, filename)
+		case <-time.After(pollInterval):
+		}
 	}
-
-	// We shouldn't get here.
-	return nil, nil
 }
 
 func (c *scapContentDataStream) FigureResources(profile string) error {
+	// Even a tailoring that extends no profile selects the rules of the
+	// source data stream
+	if c.dataStream == nil {
+		return ErrSourceNotLoaded
+	}
+
 	// Always stage the clusteroperators/openshift-apiserver object for version detection.
 	namespace := os.Getenv("POD_NAMESPACE")
 	podName := os.Getenv("POD_NAME")
@@ -189,16 +356,21 @@ func (c *scapContentDataStream) FigureResources(profile string) error {
 		},
 	}
 
+	found = appendExtraResourcePaths(found, c.extraResourcePaths)
+
 	effectiveProfile := profile
 	var valuesList map[string]string
+	c.resourceRules = map[string][]string{}
 
 	if c.tailoring != nil {
 		var selected []utils.ResourcePath
-		selected, valuesList = getResourcePaths(c.tailoring, c.dataStream, profile, nil)
+		var rules map[string][]string
+		selected, valuesList, rules = getResourcePathsByRule(c.tailoring, c.dataStream, profile, nil)
 		if len(selected) == 0 {
 			fmt.Printf("no valid checks found in tailoring\n")
 		}
 		found = append(found, selected...)
+		addResourceRules(c.resourceRules, rules)
 		// Overwrite profile so the next search uses the extended profile
 		effectiveProfile = c.getExtendedProfileFromTailoring(c.tailoring, profile)
 		// No profile is being extended
@@ -208,16 +380,47 @@ func (c *scapContentDataStream) FigureResources(profile string) error {
 		}
 	}
 
-	selected, _ := getResourcePaths(c.dataStream, c.dataStream, effectiveProfile, valuesList)
+	selected, _, rules := getResourcePathsByRule(c.dataStream, c.dataStream, effectiveProfile, valuesList)
 	if len(selected) == 0 {
 		fmt.Printf("no valid checks found in profile\n")
 	}
 	found = append(found, selected...)
+	addResourceRules(c.resourceRules, rules)
 	c.resources = found
 	DBG("c.resources: %v\n", c.resources)
 	return nil
 }
 
+// addResourceRules adds the rule IDs of each API path in from to the ones
+// already in to
+func addResourceRules(to, from map[string][]string) {
+	for path, ruleIDs := range from {
+		to[path] = append(to[path], ruleIDs...)
+	}
+}
+
+// appendExtraResourcePaths adds the paths that were explicitly asked for to
+// the ones that are always fetched, skipping the ones that are already there
+func appendExtraResourcePaths(found []utils.ResourcePath, extraPaths []string) []utils.ResourcePath {
+	for _, extraPath := range extraPaths {
+		alreadyFound := false
+		for _, rp := range found {
+			if rp.ObjPath == extraPath && rp.Filter == "" {
+				alreadyFound = true
+				break
+			}
+		}
+		if alreadyFound {
+			continue
+		}
+		found = append(found, utils.ResourcePath{
+			ObjPath:  extraPath,
+			DumpPath: extraPath,
+		})
+	}
+	return found
+}
+
 // getPathsFromRuleWarning finds the API endpoint from in. The expected structure is:
 //
 //	<warning category="general" lang="en-US"><code class="ocp-api-endpoint">/apis/config.openshift.io/v1/oauths/cluster
@@ -232,10 +435,28 @@ func getPathFromWarningXML(in *xmlquery.Node, valueList map[string]string) []uti
 	return path
 }
 
+// getComplexValue joins the items of a complex-value or set-complex-value
+// element with commas, so templates can split them with toArrayByComma
+func getComplexValue(in *xmlquery.Node) string {
+	items := []string{}
+	for _, item := range in.SelectElements("xccdf-1.2:item") {
+		items = append(items, strings.TrimSpace(item.InnerText()))
+	}
+	return strings.Join(items, ",")
+}
+
 // Collect the resource paths for objects that this scan needs to obtain.
 // The profile will have a series of "selected" checks that we grab all of the path info from.
 func getResourcePaths(profileDefs *xmlquery.Node, ruleDefs *xmlquery.Node, profile string, overrideValueList map[string]string) ([]utils.ResourcePath, map[string]string) {
+	out, valuesList, _ := getResourcePathsByRule(profileDefs, ruleDefs, profile, overrideValueList)
+	return out, valuesList
+}
+
+// getResourcePathsByRule is like getResourcePaths, but also returns the IDs
+// of the rules that check each path
+func getResourcePathsByRule(profileDefs *xmlquery.Node, ruleDefs *xmlquery.Node, profile string, overrideValueList map[string]string) ([]utils.ResourcePath, map[string]string, map[string][]string) {
 	out := []utils.ResourcePath{}
+	resourceRules := map[string][]string{}
 	selectedChecks := []string{}
 
 	// Before staring process, collect all of the variables in definitions.
@@ -257,6 +478,12 @@ func getResourcePaths(profileDefs *xmlquery.Node, ruleDefs *xmlquery.Node, profi
 					}
 				}
 			}
+			// List variables have a complex-value with one item per element
+			for _, val := range variable.SelectElements("xccdf-1.2:complex-value") {
+				if val.SelectAttr("selector") == "" && strings.HasPrefix(variable.SelectAttr("id"), valuePrefix) {
+					valuesList[strings.TrimPrefix(variable.SelectAttr("id"), valuePrefix)] = getComplexValue(val)
+				}
+			}
 		}
 		allSetValues := xmlquery.Find(def, "//xccdf-1.2:set-value")
 		for _, variable := range allSetValues {
@@ -264,6 +491,12 @@ func getResourcePaths(profileDefs *xmlquery.Node, ruleDefs *xmlquery.Node, profi
 				valuesList[strings.TrimPrefix(variable.SelectAttr("idref"), valuePrefix)] = html.UnescapeString(variable.OutputXML(false))
 			}
 		}
+		allSetComplexValues := xmlquery.Find(def, "//xccdf-1.2:set-complex-value")
+		for _, variable := range allSetComplexValues {
+			if strings.HasPrefix(variable.SelectAttr("idref"), valuePrefix) {
+				valuesList[strings.TrimPrefix(variable.SelectAttr("idref"), valuePrefix)] = getComplexValue(variable)
+			}
+		}
 	}
 
 	// override variables which is defined in tailored profile
@@ -303,7 +536,10 @@ func getResourcePaths(profileDefs *xmlquery.Node, ruleDefs *xmlquery.Node, profi
 	checkDefinitions := ruleDefs.SelectElements("//xccdf-1.2:Rule")
 	if len(checkDefinitions) == 0 {
 		DBG("WARNING: No rules to query (invalid datastream)")
-		return out, valuesList
+		return out, valuesList, resourceRules
+	}
+	if duplicates := utils.FindDuplicateRuleIDs(ruleDefs); len(duplicates) > 0 {
+		LOG("%s: %s", utils.DuplicateRuleIDsMessage, strings.Join(duplicates, ", "))
 	}
 
 	// For each of our selected checks, collect the required path info.
@@ -334,6 +570,9 @@ func getResourcePaths(profileDefs *xmlquery.Node, ruleDefs *xmlquery.Node, profi
 			}
 			// We only care for the first occurrence that works
 			out = append(out, apiPaths...)
+			for _, apiPath := range apiPaths {
+				resourceRules[apiPath.ObjPath] = append(resourceRules[apiPath.ObjPath], checkID)
+			}
 			warningFound = true
 			break
 		}
@@ -344,7 +583,7 @@ func getResourcePaths(profileDefs *xmlquery.Node, ruleDefs *xmlquery.Node, profi
 		}
 
 	}
-	return out, valuesList
+	return out, valuesList, resourceRules
 }
 
 func (c *scapContentDataStream) getExtendedProfileFromTailoring(ds *xmlquery.Node, tailoredProfile string) string {
@@ -364,12 +603,40 @@ func (c *scapContentDataStream) getExtendedProfileFromTailoring(ds *xmlquery.Nod
 }
 
 func (c *scapContentDataStream) FetchResources() ([]string, error) {
-	found, warnings, err := fetch(context.Background(), getStreamerFn, c.resourceFetcherClients, c.resources)
+	streamerFn := newStreamerDispatcher(c.mcPageSize, c.mcConcurrency)
+	var recordWarning fetchWarningFunc
+	if c.fetchWarnings != nil {
+		recordWarning = c.fetchWarnings.record
+	}
+	found, warnings, err := fetch(context.Background(), streamerFn, c.resourceFetcherClients, c.resources, c.fetchConcurrency, c.fetchProgress, recordWarning)
+	if c.fetchWarnings != nil {
+		c.fetchWarnings.report(c.absentResourceTypeRules(warnings))
+	}
 	if err != nil {
-		return warnings, err
+		return warningMessages(warnings), err
 	}
 	c.found = found
-	return warnings, nil
+	return warningMessages(warnings), nil
+}
+
+// absentResourceTypeRules returns the IDs of the selected rules that check
+// resources whose type doesn't exist on this cluster, sorted
+func (c *scapContentDataStream) absentResourceTypeRules(warnings []fetchWarning) []string {
+	ruleSet := map[string]bool{}
+	for _, w := range warnings {
+		if !errors.Is(w.class, ErrResourceTypeAbsent) {
+			continue
+		}
+		for _, ruleID := range c.resourceRules[w.uri] {
+			ruleSet[ruleID] = true
+		}
+	}
+	rules := make([]string, 0, len(ruleSet))
+	for ruleID := range ruleSet {
+		rules = append(rules, ruleID)
+	}
+	sort.Strings(rules)
+	return rules
 }
 
 // resourceStreamer is an interface capable of streaming a particular URI
@@ -382,12 +649,24 @@ type streamerDispatcherFn func(string) resourceStreamer
 // getStreamerFn returns a structure implementing resourceStreamer interface based on the
 // uri passed to it
 func getStreamerFn(uri string) resourceStreamer {
-	if uri == "/apis/machineconfiguration.openshift.io/v1/machineconfigs" {
-		return &mcStreamer{}
-	}
+	return newStreamerDispatcher(defaultMcPageSize, defaultMcConcurrency)(uri)
+}
 
-	return &uriStreamer{
-		uri: uri,
+// newStreamerDispatcher returns a streamerDispatcherFn whose MachineConfig
+// streamer lists mcPageSize MachineConfigs at a time and filters up to
+// mcConcurrency pages of them at the same time
+func newStreamerDispatcher(mcPageSize, mcConcurrency int) streamerDispatcherFn {
+	return func(uri string) resourceStreamer {
+		if uri == "/apis/machineconfiguration.openshift.io/v1/machineconfigs" {
+			return &mcStreamer{
+				pageSize:    mcPageSize,
+				concurrency: mcConcurrency,
+			}
+		}
+
+		return &uriStreamer{
+			uri: uri,
+		}
 	}
 }
 
@@ -401,7 +680,12 @@ func (us *uriStreamer) Stream(ctx context.Context, rfClients resourceFetcherClie
 }
 
 // mcStreamer implements resourceStreamer for fetching a list of MachineConfigs
-type mcStreamer struct{}
+type mcStreamer struct {
+	// How many MachineConfigs to list at a time
+	pageSize int
+	// How many listed pages to filter at the same time
+	concurrency int
+}
 
 // bufCloser is a kludge so that mcStreamer's Stream() method can return an io.ReadCloser
 type bufCloser struct {
@@ -415,36 +699,64 @@ func (bc *bufCloser) Close() error {
 }
 
 // Stream fetches MachineConfigs in batches of pageSize, removes the file contents from each MC in the batch,
-// adds each batch to a resulting list which is finally returned as JSON
+// adds each batch to a resulting list which is finally returned as JSON. The pages have to be listed one
+// after the other, but up to concurrency of them are filtered while the next ones are being listed.
 func (ms *mcStreamer) Stream(ctx context.Context, rfClients resourceFetcherClients) (io.ReadCloser, error) {
-	mcfgListNoFiles := mcfgv1.MachineConfigList{}
-	const pageSize = 5
+	pageSize := ms.pageSize
+	if pageSize < 1 {
+		pageSize = defaultMcPageSize
+	}
+	concurrency := ms.concurrency
+	if concurrency < 1 {
+		concurrency = defaultMcConcurrency
+	}
 
+	batches := []*mcfgv1.MachineConfigList{}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var listErr error
 	continueToken := ""
 	for {
-		mcfgList := mcfgv1.MachineConfigList{}
+		mcfgList := &mcfgv1.MachineConfigList{}
 		listOpts := runtimeclient.ListOptions{
 			Limit: int64(pageSize),
 		}
 		if continueToken != "" {
 			listOpts.Continue = continueToken
 		}
-		if err := rfClients.client.List(ctx, &mcfgList, &listOpts); err != nil {
-			return nil, fmt.Errorf("failed to list MachineConfigs: %w", err)
-		}
-
-		mcfgListNoFilesBatch, err := filterMcList(&mcfgList)
-		if err != nil {
-			return nil, fmt.Errorf("failed to filter machine configs: %w", err)
+		if err := rfClients.client.List(ctx, mcfgList, &listOpts); err != nil {
+			listErr = fmt.Errorf("failed to list MachineConfigs: %w", err)
+			break
 		}
 
-		mcfgListNoFiles.Items = append(mcfgListNoFiles.Items, mcfgListNoFilesBatch.Items...)
+		// Each goroutine fills in its own batch, the slice itself is only
+		// touched by this loop
+		batch := &mcfgv1.MachineConfigList{}
+		batches = append(batches, batch)
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(mcfgList, batch *mcfgv1.MachineConfigList) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			*batch = *filterMcList(mcfgList)
+		}(mcfgList, batch)
 
 		continueToken = mcfgList.ListMeta.Continue
 		if continueToken == "" {
 			break
 		}
 	}
+	wg.Wait()
+	if listErr != nil {
+		return nil, listErr
+	}
+
+	mcfgListNoFiles := mcfgv1.MachineConfigList{}
+	for _, batch := range batches {
+		mcfgListNoFiles.Items = append(mcfgListNoFiles.Items, batch.Items...)
+	}
 
 	jsonSerializer := runtimejson.NewSerializerWithOptions(runtimejson.DefaultMetaFactory,
 		rfClients.scheme,
@@ -457,7 +769,11 @@ func (ms *mcStreamer) Stream(ctx context.Context, rfClients resourceFetcherClien
 	return buf, nil
 }
 
-func filterMcList(mcListIn *mcfgv1.MachineConfigList) (*mcfgv1.MachineConfigList, error) {
+// filterMcList removes the files from the Ignition config of the
+// MachineConfigs. A MachineConfig whose Ignition config can't be handled is
+// left out of the list with a warning, so that a single bad MachineConfig
+// doesn't prevent the others from being checked.
+func filterMcList(mcListIn *mcfgv1.MachineConfigList) *mcfgv1.MachineConfigList {
 	mcfgListNoFiles := mcfgv1.MachineConfigList{}
 	mcfgListNoFiles.TypeMeta = mcListIn.TypeMeta
 	mcfgListNoFiles.ListMeta = mcListIn.ListMeta
@@ -468,84 +784,371 @@ func filterMcList(mcListIn *mcfgv1.MachineConfigList) (*mcfgv1.MachineConfigList
 			// if Ignition exists, filter out all the potentially large files
 			ign, err := mcfgcommon.ParseAndConvertConfig(mc.Spec.Config.Raw)
 			if err != nil {
-				return nil, fmt.Errorf("cannot parse MC %s: %w", mc.Name, err)
+				LOG("WARNING: Skipping MachineConfig %s, cannot parse its Ignition config: %s", mc.Name, err)
+				continue
 			}
 			ign.Storage.Files = nil // just get rid of the files the easy way
 			rawOutIgn, err := json.Marshal(ign)
 			if err != nil {
-				return nil, fmt.Errorf("failed to marshal Ignition object back to a a raw object: %w", err)
+				LOG("WARNING: Skipping MachineConfig %s, failed to marshal its Ignition config: %s", mc.Name, err)
+				continue
 			}
 			mc.Spec.Config.Raw = rawOutIgn
 		}
 		mcfgListNoFiles.Items = append(mcfgListNoFiles.Items, mc)
 	}
 
-	return &mcfgListNoFiles, nil
+	return &mcfgListNoFiles
 }
 
-func fetch(ctx context.Context, streamDispatcher streamerDispatcherFn, rfClients resourceFetcherClients, objects []utils.ResourcePath) (map[string][]byte, []string, error) {
-	var warnings []string
-	results := map[string][]byte{}
+// fetchWarning is a problem with a resource that didn't fail the fetch
+type fetchWarning struct {
+	uri     string
+	message string
+	// why the resource couldn't be fetched, as classified by
+	// classifyFetchError, or nil if it was fetched
+	class error
+	// whether the content asked not to report the warning. Such warnings
+	// aren't saved, but their class is still acted upon.
+	suppressed bool
+}
 
-	for _, rpath := range objects {
-		err := func() error {
-			uri := rpath.ObjPath
-			LOG("Fetching URI: '%s'", uri)
-			streamer := streamDispatcher(uri)
-			stream, err := streamer.Stream(ctx, rfClients)
-			if meta.IsNoMatchError(err) || kerrors.IsForbidden(err) || kerrors.IsNotFound(err) {
-				DBG("Encountered non-fatal error to be persisted in the scan: %s", err)
-				objerr := fmt.Errorf("could not fetch %s: %w", uri, err)
-				if !rpath.SuppressWarning {
-					warnings = append(warnings, objerr.Error())
-				}
-				// for 404s we'll add a warning comment in the object so openSCAP can read and process it
-				if kerrors.IsNotFound(err) {
-					results[rpath.DumpPath] = []byte("# kube-api-error=" + kerrors.ReasonForError(err))
+// warningMessages returns the messages of the warnings that weren't
+// suppressed
+func warningMessages(warnings []fetchWarning) []string {
+	var messages []string
+	for _, w := range warnings {
+		if !w.suppressed {
+			messages = append(messages, w.message)
+		}
+	}
+	return messages
+}
+
+// fetchOutcome is what fetching a single resource produced
+type fetchOutcome struct {
+	body     []byte
+	hasBody  bool
+	warnings []fetchWarning
+	// why the resource couldn't be fetched, if that was reported as a
+	// warning
+	warningReason string
+	err           error
+}
+
+// fetchProgressFunc is told how many of the total objects were fetched so
+// far. It's never called concurrently.
+type fetchProgressFunc func(fetched, total int)
+
+// Reasons for which a resource couldn't be fetched, as counted by
+// fetchWarningFunc
+const (
+	fetchWarningForbidden = "forbidden"
+	fetchWarningNotFound  = "notfound"
+	fetchWarningNoMatch   = "nomatch"
+	fetchWarningTimeout   = "timeout"
+)
+
+// fetchWarningFunc is told the reason of every warning about a resource that
+// couldn't be fetched. It's never called concurrently.
+type fetchWarningFunc func(reason string)
+
+// fetch retrieves the objects, fetching up to concurrency of them at the
+// same time. The warnings, including the suppressed ones, are returned in
+// the order of the objects, and if several objects share a dump path, the last one wins, like when they're
+// fetched one after the other. If progress isn't nil, it's called before
+// fetching anything and then once per fetched object. If recordWarning isn't
+// nil, it's called for every object that couldn't be fetched and was warned
+// about.
+func fetch(ctx context.Context, streamDispatcher streamerDispatcherFn, rfClients resourceFetcherClients, objects []utils.ResourcePath, concurrency int, progress fetchProgressFunc, recordWarning fetchWarningFunc) (map[string][]byte, []fetchWarning, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var progressMu sync.Mutex
+	fetched := 0
+	if progress != nil {
+		progress(fetched, len(objects))
+	}
+
+	outcomes := make([]fetchOutcome, len(objects))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	// The first fatal error cancels the fetches still in flight, which then
+	// fail with context.Canceled. Remember the error that caused it so that
+	// it's the one reported.
+	var firstErr error
+	var firstErrOnce sync.Once
+	for i := range objects {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			// An earlier fetch failed, don't bother with the rest
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			outcomes[i] = fetchObject(ctx, streamDispatcher, rfClients, objects[i])
+			if err := outcomes[i].err; err != nil {
+				if !errors.Is(err, context.Canceled) {
+					firstErrOnce.Do(func() { firstErr = err })
 				}
-				return nil
-			} else if err != nil {
-				return fmt.Errorf("streaming URIs failed: %w", err)
+				cancel()
+				return
 			}
-			defer stream.Close()
-			body, err := io.ReadAll(stream)
-			if err != nil {
-				return err
-			}
-			if len(body) == 0 {
-				DBG("no data in request body")
-				return nil
+			if progress != nil {
+				progressMu.Lock()
+				fetched++
+				progress(fetched, len(objects))
+				progressMu.Unlock()
 			}
-			if rpath.Filter != "" {
-				DBG("Applying filter '%s' to path '%s'", rpath.Filter, rpath.ObjPath)
-				filteredBody, filterErr := filter(ctx, body, rpath.Filter)
-				if errors.Is(filterErr, MoreThanOneObjErr) {
-					warnings = append(warnings, filterErr.Error())
-				} else if errors.Is(filterErr, NullValErr) {
-					warnings = append(warnings, fmt.Sprintf("couldn't filter '%s': %s", body, filterErr.Error()))
-				} else if filterErr != nil {
-					return fmt.Errorf("couldn't filter '%s': %w", body, filterErr)
-				}
-				results[rpath.DumpPath] = filteredBody
-			} else {
-				results[rpath.DumpPath] = body
+		}(i)
+	}
+	wg.Wait()
+
+	var warnings []fetchWarning
+	results := map[string][]byte{}
+	for i, rpath := range objects {
+		outcome := outcomes[i]
+		warnings = append(warnings, outcome.warnings...)
+		if recordWarning != nil && outcome.warningReason != "" {
+			recordWarning(outcome.warningReason)
+		}
+		if outcome.err != nil {
+			if firstErr != nil {
+				return nil, warnings, firstErr
 			}
-			return nil
-		}()
-		if err != nil {
-			return nil, warnings, err
+			return nil, warnings, outcome.err
+		}
+		if outcome.hasBody {
+			results[rpath.DumpPath] = outcome.body
 		}
 	}
 	return results, warnings, nil
 }
 
+// scanFetchProgressReporter records the progress of fetching the resources
+// in the status of the scan. Updates are sent at most once per interval,
+// except for the final one, so that many quick fetches don't flood the API
+// server with patches.
+type scanFetchProgressReporter struct {
+	client     runtimeclient.Client
+	scanKey    types.NamespacedName
+	interval   time.Duration
+	lastReport time.Time
+}
+
+func newScanFetchProgressReporter(client runtimeclient.Client, namespace, scanName string, interval time.Duration) *scanFetchProgressReporter {
+	return &scanFetchProgressReporter{
+		client:   client,
+		scanKey:  types.NamespacedName{Name: scanName, Namespace: namespace},
+		interval: interval,
+	}
+}
+
+func (r *scanFetchProgressReporter) report(fetched, total int) {
+	now := time.Now()
+	if fetched > 0 && fetched < total && now.Sub(r.lastReport) < r.interval {
+		return
+	}
+	r.lastReport = now
+
+	progress := fmt.Sprintf("%d/%d", fetched, total)
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"fetchProgress": progress,
+		},
+	})
+	if err != nil {
+		LOG("Couldn't create the fetch progress patch: %s", err)
+		return
+	}
+	scan := &compv1alpha1.ComplianceScan{}
+	scan.Name = r.scanKey.Name
+	scan.Namespace = r.scanKey.Namespace
+	// The progress is only informative, so failing to report it doesn't
+	// fail the scan
+	if err := r.client.Status().Patch(context.TODO(), scan, runtimeclient.RawPatch(types.MergePatchType, patch)); err != nil {
+		LOG("Couldn't report the fetch progress %s in scan %s: %s", progress, r.scanKey.Name, err)
+		return
+	}
+	DBG("Fetched %s resources", progress)
+}
+
+// scanFetchWarningReporter counts the resources that couldn't be fetched by
+// reason and records the counts in the status of the scan, so that the
+// operator can expose them as metrics. It also records the rules that check
+// resources whose type doesn't exist, so that the aggregator can point them
+// out on their results.
+type scanFetchWarningReporter struct {
+	client  runtimeclient.Client
+	scanKey types.NamespacedName
+	counts  map[string]int
+}
+
+func newScanFetchWarningReporter(client runtimeclient.Client, namespace, scanName string) *scanFetchWarningReporter {
+	return &scanFetchWarningReporter{
+		client:  client,
+		scanKey: types.NamespacedName{Name: scanName, Namespace: namespace},
+		counts:  map[string]int{},
+	}
+}
+
+func (r *scanFetchWarningReporter) record(reason string) {
+	r.counts[reason]++
+}
+
+// report records the counts and the rules checking absent resource types in
+// the status of the scan, or clears them if there are none so that the ones
+// of a previous run don't linger
+func (r *scanFetchWarningReporter) report(absentResourceTypeRules []string) {
+	var value, rulesValue interface{}
+	if len(r.counts) > 0 {
+		reasons := make([]string, 0, len(r.counts))
+		for reason := range r.counts {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for i, reason := range reasons {
+			reasons[i] = fmt.Sprintf("%s=%d", reason, r.counts[reason])
+		}
+		value = strings.Join(reasons, ",")
+	}
+	if len(absentResourceTypeRules) > 0 {
+		rulesValue = absentResourceTypeRules
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"fetchWarnings":           value,
+			"absentResourceTypeRules": rulesValue,
+		},
+	})
+	if err != nil {
+		LOG("Couldn't create the fetch warnings patch: %s", err)
+		return
+	}
+	scan := &compv1alpha1.ComplianceScan{}
+	scan.Name = r.scanKey.Name
+	scan.Namespace = r.scanKey.Namespace
+	// Failing to report doesn't fail the scan, the counts are only used for
+	// metrics and the rules are then evaluated like any other
+	if err := r.client.Status().Patch(context.TODO(), scan, runtimeclient.RawPatch(types.MergePatchType, patch)); err != nil {
+		LOG("Couldn't report the fetch warnings in scan %s: %s", r.scanKey.Name, err)
+	}
+}
+
+// fetchObject retrieves a single object and applies its filter
+func fetchObject(ctx context.Context, streamDispatcher streamerDispatcherFn, rfClients resourceFetcherClients, rpath utils.ResourcePath) fetchOutcome {
+	var outcome fetchOutcome
+	uri := rpath.ObjPath
+	LOG("Fetching URI: '%s'", uri)
+	streamer := streamDispatcher(uri)
+	var stream io.ReadCloser
+	err := retry.OnError(fetchRetryBackoff, func(err error) bool {
+		return isTransientFetchError(err) && ctx.Err() == nil
+	}, func() error {
+		var streamErr error
+		stream, streamErr = streamer.Stream(ctx, rfClients)
+		if isTransientFetchError(streamErr) {
+			DBG("Transient error fetching URI '%s': %s", uri, streamErr)
+		}
+		return streamErr
+	})
+	if class := classifyFetchError(err); class != nil {
+		DBG("Encountered non-fatal error to be persisted in the scan: %s", err)
+		objerr := &resourceFetchError{uri: uri, class: class, err: err}
+		// An unavailable resource means the scan lacks data, so it's
+		// always reported even if the content asked to suppress the
+		// warning.
+		suppressed := rpath.SuppressWarning && !errors.Is(objerr, ErrResourceUnavailable)
+		outcome.warnings = append(outcome.warnings, fetchWarning{
+			uri:        uri,
+			message:    objerr.Error(),
+			class:      class,
+			suppressed: suppressed,
+		})
+		if !suppressed {
+			outcome.warningReason = fetchWarningReason(err)
+		}
+		// for 404s we'll save an error marker in place of the object so openSCAP can read and process it
+		if kerrors.IsNotFound(err) {
+			outcome.body = utils.NewKubeAPIErrorMarker(string(kerrors.ReasonForError(err)))
+			outcome.hasBody = true
+		}
+		return outcome
+	} else if err != nil {
+		outcome.err = fmt.Errorf("streaming URIs failed: %w", err)
+		return outcome
+	}
+	defer stream.Close()
+	body, err := io.ReadAll(stream)
+	if err != nil {
+		outcome.err = err
+		return outcome
+	}
+	if len(body) == 0 {
+		DBG("no data in request body")
+		return outcome
+	}
+	if rpath.Filter != "" {
+		DBG("Applying filter '%s' to path '%s'", rpath.Filter, rpath.ObjPath)
+		filteredBody, filterErr := filter(ctx, body, rpath.Filter)
+		if errors.Is(filterErr, MoreThanOneObjErr) {
+			outcome.warnings = append(outcome.warnings, fetchWarning{uri: uri, message: filterErr.Error()})
+		} else if errors.Is(filterErr, NullValErr) {
+			outcome.warnings = append(outcome.warnings, fetchWarning{uri: uri, message: fmt.Sprintf("couldn't filter '%s': %s", body, filterErr.Error())})
+		} else if filterErr != nil {
+			outcome.err = fmt.Errorf("couldn't filter '%s': %w", body, filterErr)
+			return outcome
+		}
+		outcome.body = filteredBody
+	} else {
+		outcome.body = body
+	}
+	outcome.hasBody = true
+	return outcome
+}
+
+// decodeFilterInput decodes the body of an API response for filtering. The
+// body may be an object, a list or any other JSON value. If it's a stream of
+// several values, such as NDJSON, they're all put into an array, the same way
+// jq's --slurp does.
+func decodeFilterInput(rawobj []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(rawobj))
+	values := []interface{}{}
+	for {
+		var value interface{}
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	switch len(values) {
+	case 0:
+		return nil, fmt.Errorf("no JSON value in the response")
+	case 1:
+		return values[0], nil
+	default:
+		return values, nil
+	}
+}
+
 func filter(ctx context.Context, rawobj []byte, filter string) ([]byte, error) {
 	fltr, fltrErr := gojq.Parse(filter)
 	if fltrErr != nil {
 		return nil, fmt.Errorf("could not create filter '%s': %w", filter, fltrErr)
 	}
-	obj := map[string]interface{}{}
-	unmarshallErr := json.Unmarshal(rawobj, &obj)
+	obj, unmarshallErr := decodeFilterInput(rawobj)
 	if unmarshallErr != nil {
 		return nil, fmt.Errorf("Error unmarshalling json: %w", unmarshallErr)
 	}
@@ -608,9 +1211,32 @@ func (c *scapContentDataStream) SaveWarningsIfAny(warnings []string, outputFile
 }
 
 func (c *scapContentDataStream) SaveResources(to string) error {
+	if c.resultLayout == resultLayoutFlat {
+		return saveResourcesFlat(to, c.found)
+	}
 	return saveResources(to, c.found)
 }
 
+// clearResultDir removes everything inside rootDir, so that resources saved
+// by a previous run can't be mistaken for freshly fetched ones. The directory
+// itself is kept since it's usually a volume mount.
+func clearResultDir(rootDir string) error {
+	entries, err := os.ReadDir(rootDir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		stalePath := path.Join(rootDir, entry.Name())
+		LOG("Removing stale resource: '%s'", stalePath)
+		if err := os.RemoveAll(stalePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func saveResources(rootDir string, data map[string][]byte) error {
 	for apiPath, fileContents := range data {
 		saveDir, saveFile, err := getSaveDirectoryAndFileName(rootDir, apiPath)
@@ -631,6 +1257,45 @@ func saveResources(rootDir string, data map[string][]byte) error {
 	return nil
 }
 
+// saveResourcesFlat saves all the resources directly in rootDir, using the
+// escaped API path as the file name. A manifest mapping each file name to its
+// API path is saved alongside them.
+func saveResourcesFlat(rootDir string, data map[string][]byte) error {
+	if err := os.MkdirAll(rootDir, 0700); err != nil {
+		return err
+	}
+
+	manifest := make(map[string]string, len(data))
+	for apiPath, fileContents := range data {
+		saveFile, err := getFlatFileName(apiPath)
+		if err != nil {
+			return err
+		}
+		savePath := path.Join(rootDir, saveFile)
+		LOG("Saving fetched resource to: '%s'", savePath)
+		if err := os.WriteFile(savePath, fileContents, 0600); err != nil {
+			return err
+		}
+		manifest[saveFile] = apiPath
+	}
+
+	manifestContents, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path.Join(rootDir, flatLayoutManifestName), manifestContents, 0600)
+}
+
+// getFlatFileName returns the file name used for apiPath in the flat layout.
+// The path is escaped so that it's a single, safe path segment.
+func getFlatFileName(apiPath string) (string, error) {
+	trimmed := strings.Trim(apiPath, "/")
+	if trimmed == "" || trimmed == "." || trimmed == ".." {
+		return "", fmt.Errorf("bad object path: %s", apiPath)
+	}
+	return url.PathEscape(trimmed), nil
+}
+
 // Returns the absolute directory path (including rootDir) and filename for the given apiPath.
 func getSaveDirectoryAndFileName(rootDir string, apiPath string) (string, string, error) {
 	base := path.Base(apiPath)
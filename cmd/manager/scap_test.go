@@ -3,9 +3,15 @@ package manager
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
 	"github.com/antchfx/xmlquery"
@@ -14,10 +20,14 @@ import (
 	. "github.com/onsi/gomega"
 	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/scheme"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -60,6 +70,52 @@ var _ = Describe("Testing SCAP parsing and storage", func() {
 		})
 	})
 
+	Context("Fetching extra resources", func() {
+		const extraPath = "/api/v1/namespaces/openshift-config/configmaps/my-config"
+
+		It("appends the extra paths to the derived ones and fetches them", func() {
+			dataStreamFile, err := os.Open("../../tests/data/ssg-ocp4-ds-new.xml")
+			Expect(err).To(BeNil())
+			defer dataStreamFile.Close()
+			contentDS, err := parseContent(dataStreamFile)
+			Expect(err).To(BeNil())
+
+			c := &scapContentDataStream{
+				dataStream: contentDS,
+				extraResourcePaths: []string{
+					extraPath,
+					// Already fetched, shouldn't be duplicated
+					"/version",
+				},
+			}
+			err = c.FigureResources("xccdf_org.ssgproject.content_profile_platform-moderate")
+			Expect(err).To(BeNil())
+
+			Expect(c.resources).To(ContainElement(utils.ResourcePath{ObjPath: extraPath, DumpPath: extraPath}))
+			Expect(c.resources).To(ContainElement(utils.ResourcePath{
+				ObjPath:  "/apis/config.openshift.io/v1/oauths/cluster",
+				DumpPath: "/apis/config.openshift.io/v1/oauths/cluster",
+			}))
+			versionPaths := 0
+			for _, rp := range c.resources {
+				if rp.ObjPath == "/version" {
+					versionPaths++
+				}
+			}
+			Expect(versionPaths).To(Equal(1))
+
+			fakeDispatcher := func(uri string) resourceStreamer {
+				if uri == extraPath {
+					return &staticFetcher{contents: `{"kind": "ConfigMap"}`}
+				}
+				return &notFoundFetcher{}
+			}
+			files, _, err := fetch(context.TODO(), fakeDispatcher, resourceFetcherClients{}, c.resources, 1, nil, nil)
+			Expect(err).To(BeNil())
+			Expect(string(files[extraPath])).To(Equal(`{"kind": "ConfigMap"}`))
+		})
+	})
+
 	Context("Parsing SCAP Content with runtime customization for OCP API resource", func() {
 		var dataStreamFile *os.File
 		var contentDS *xmlquery.Node
@@ -199,6 +255,156 @@ var _ = Describe("Testing SCAP parsing and storage", func() {
 		})
 	})
 
+	Context("Parsing SCAP Content with list variables", func() {
+		const listContent = `<?xml version="1.0" encoding="UTF-8"?>
+<xccdf-1.2:Benchmark xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2" xmlns:html="http://www.w3.org/1999/xhtml" id="xccdf_org.ssgproject.content_benchmark_OCP-4">
+  <xccdf-1.2:Profile id="xccdf_org.ssgproject.content_profile_lists">
+    <xccdf-1.2:select idref="xccdf_org.ssgproject.content_rule_list_rule" selected="true"/>
+  </xccdf-1.2:Profile>
+  <xccdf-1.2:Value id="xccdf_org.ssgproject.content_value_var_namespaces" type="string">
+    <xccdf-1.2:complex-value>
+      <xccdf-1.2:item>default-ns1</xccdf-1.2:item>
+      <xccdf-1.2:item>default-ns2</xccdf-1.2:item>
+    </xccdf-1.2:complex-value>
+  </xccdf-1.2:Value>
+  <xccdf-1.2:Rule id="xccdf_org.ssgproject.content_rule_list_rule" selected="true">
+    <xccdf-1.2:warning category="general" lang="en-US"><html:code class="ocp-api-endpoint">/api/v1/namespaces/{{index (.var_namespaces|toArrayByComma) 1}}/configmaps/cm</html:code></xccdf-1.2:warning>
+  </xccdf-1.2:Rule>
+</xccdf-1.2:Benchmark>
+`
+		const listTailoring = `<?xml version="1.0" encoding="UTF-8"?>
+<xccdf-1.2:Tailoring xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2" id="xccdf_compliance.openshift.io_tailoring_lists">
+  <xccdf-1.2:Profile id="xccdf_compliance.openshift.io_profile_lists" extends="xccdf_org.ssgproject.content_profile_lists">
+    <xccdf-1.2:set-complex-value idref="xccdf_org.ssgproject.content_value_var_namespaces">
+      <xccdf-1.2:item>tailored-ns1</xccdf-1.2:item>
+      <xccdf-1.2:item>tailored-ns2</xccdf-1.2:item>
+    </xccdf-1.2:set-complex-value>
+  </xccdf-1.2:Profile>
+</xccdf-1.2:Tailoring>
+`
+		var contentDS *xmlquery.Node
+
+		BeforeEach(func() {
+			var err error
+			contentDS, err = utils.ParseContent(strings.NewReader(listContent))
+			Expect(err).To(BeNil())
+		})
+
+		It("Uses the default items of the variable", func() {
+			got, valuesList := getResourcePaths(contentDS, contentDS, "xccdf_org.ssgproject.content_profile_lists", nil)
+			Expect(valuesList).To(HaveKeyWithValue("var_namespaces", "default-ns1,default-ns2"))
+			Expect(got).To(Equal([]utils.ResourcePath{
+				{
+					ObjPath:  "/api/v1/namespaces/default-ns2/configmaps/cm",
+					DumpPath: "/api/v1/namespaces/default-ns2/configmaps/cm",
+				},
+			}))
+		})
+
+		It("Prefers the items the tailoring sets", func() {
+			tpContentDS, err := utils.ParseContent(strings.NewReader(listTailoring))
+			Expect(err).To(BeNil())
+
+			_, valuesList := getResourcePaths(tpContentDS, contentDS, "xccdf_compliance.openshift.io_profile_lists", nil)
+			Expect(valuesList).To(HaveKeyWithValue("var_namespaces", "tailored-ns1,tailored-ns2"))
+			got, _ := getResourcePaths(contentDS, contentDS, "xccdf_org.ssgproject.content_profile_lists", valuesList)
+			Expect(got[0].ObjPath).To(Equal("/api/v1/namespaces/tailored-ns2/configmaps/cm"))
+		})
+	})
+
+	Context("Figuring resources from a tailoring", func() {
+		const content = `<?xml version="1.0" encoding="UTF-8"?>
+<xccdf-1.2:Benchmark xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2" xmlns:html="http://www.w3.org/1999/xhtml" id="xccdf_org.ssgproject.content_benchmark_OCP-4">
+  <xccdf-1.2:Profile id="xccdf_org.ssgproject.content_profile_base">
+    <xccdf-1.2:select idref="xccdf_org.ssgproject.content_rule_base_rule" selected="true"/>
+  </xccdf-1.2:Profile>
+  <xccdf-1.2:Rule id="xccdf_org.ssgproject.content_rule_base_rule" selected="true">
+    <xccdf-1.2:warning category="general" lang="en-US"><html:code class="ocp-api-endpoint">/apis/config.openshift.io/v1/oauths/cluster</html:code></xccdf-1.2:warning>
+  </xccdf-1.2:Rule>
+  <xccdf-1.2:Rule id="xccdf_org.ssgproject.content_rule_tailored_rule" selected="true">
+    <xccdf-1.2:warning category="general" lang="en-US"><html:code class="ocp-api-endpoint">/api/v1/namespaces/openshift-config/configmaps/tailored</html:code></xccdf-1.2:warning>
+  </xccdf-1.2:Rule>
+</xccdf-1.2:Benchmark>
+`
+		const tailoring = `<?xml version="1.0" encoding="UTF-8"?>
+<xccdf-1.2:Tailoring xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2" id="xccdf_compliance.openshift.io_tailoring_standalone">
+  <xccdf-1.2:Profile id="xccdf_compliance.openshift.io_profile_standalone">
+    <xccdf-1.2:select idref="xccdf_org.ssgproject.content_rule_tailored_rule" selected="true"/>
+  </xccdf-1.2:Profile>
+</xccdf-1.2:Tailoring>
+`
+		var c *scapContentDataStream
+
+		BeforeEach(func() {
+			tailoringDS, err := utils.ParseContent(strings.NewReader(tailoring))
+			Expect(err).To(BeNil())
+			c = &scapContentDataStream{tailoring: tailoringDS}
+		})
+
+		It("only fetches what the tailoring selects when it extends no profile", func() {
+			var err error
+			c.dataStream, err = utils.ParseContent(strings.NewReader(content))
+			Expect(err).To(BeNil())
+
+			Expect(c.FigureResources("xccdf_compliance.openshift.io_profile_standalone")).To(Succeed())
+			Expect(c.resources).To(ContainElement(utils.ResourcePath{
+				ObjPath:  "/api/v1/namespaces/openshift-config/configmaps/tailored",
+				DumpPath: "/api/v1/namespaces/openshift-config/configmaps/tailored",
+			}))
+			Expect(c.resources).NotTo(ContainElement(utils.ResourcePath{
+				ObjPath:  "/apis/config.openshift.io/v1/oauths/cluster",
+				DumpPath: "/apis/config.openshift.io/v1/oauths/cluster",
+			}))
+		})
+
+		It("fails when the source data stream wasn't loaded", func() {
+			err := c.FigureResources("xccdf_compliance.openshift.io_profile_standalone")
+			Expect(err).To(MatchError(ErrSourceNotLoaded))
+			Expect(c.resources).To(BeEmpty())
+		})
+	})
+
+	Context("Waiting for the content file", func() {
+		var tmpDir, fileName string
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = os.MkdirTemp("", "content")
+			Expect(err).To(BeNil())
+			fileName = filepath.Join(tmpDir, "content.xml")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		It("picks up a file that is written after a delay", func() {
+			go func() {
+				defer GinkgoRecover()
+				time.Sleep(200 * time.Millisecond)
+				err := os.WriteFile(fileName, []byte("<xml/>"), 0600)
+				Expect(err).To(BeNil())
+			}()
+			f, err := openNonEmptyFile(fileName, 5*time.Second, 50*time.Millisecond)
+			Expect(err).To(BeNil())
+			defer f.Close()
+			Expect(f.Name()).To(Equal(fileName))
+		})
+
+		It("waits until the file has contents", func() {
+			err := os.WriteFile(fileName, []byte{}, 0600)
+			Expect(err).To(BeNil())
+			_, err = openNonEmptyFile(fileName, 200*time.Millisecond, 50*time.Millisecond)
+			Expect(err).To(MatchError(ContentFileTimeoutErr))
+		})
+
+		It("returns an error if the file doesn't show up before the timeout", func() {
+			f, err := openNonEmptyFile(fileName, 200*time.Millisecond, 50*time.Millisecond)
+			Expect(f).To(BeNil())
+			Expect(err).To(MatchError(ContentFileTimeoutErr))
+		})
+	})
+
 	Context("Parses the save path appropriately", func() {
 		It("Parses correctly with the root being '/tmp'", func() {
 			root := "/tmp"
@@ -236,6 +442,84 @@ var _ = Describe("Testing SCAP parsing and storage", func() {
 			Expect(file).To(Equal(expectedFile))
 		})
 	})
+
+	Context("Saving resources", func() {
+		var rootDir string
+		data := map[string][]byte{
+			"/apis/foo/bar":                          []byte("bar"),
+			"/api/v1/namespaces?labelSelector=a%3Db": []byte("selected"),
+		}
+
+		BeforeEach(func() {
+			var err error
+			rootDir, err = os.MkdirTemp("", "resultdir")
+			Expect(err).To(BeNil())
+		})
+		AfterEach(func() {
+			os.RemoveAll(rootDir)
+		})
+
+		It("Nests the resources by their API path", func() {
+			Expect(saveResources(rootDir, data)).To(Succeed())
+
+			contents, err := os.ReadFile(filepath.Join(rootDir, "apis", "foo", "bar"))
+			Expect(err).To(BeNil())
+			Expect(string(contents)).To(Equal("bar"))
+			contents, err = os.ReadFile(filepath.Join(rootDir, "api", "v1", "namespaces?labelSelector=a%3Db"))
+			Expect(err).To(BeNil())
+			Expect(string(contents)).To(Equal("selected"))
+		})
+
+		It("Saves the resources in a single directory with a manifest", func() {
+			Expect(saveResourcesFlat(rootDir, data)).To(Succeed())
+
+			entries, err := os.ReadDir(rootDir)
+			Expect(err).To(BeNil())
+			Expect(entries).To(HaveLen(len(data) + 1))
+			for _, entry := range entries {
+				Expect(entry.IsDir()).To(BeFalse())
+			}
+
+			rawManifest, err := os.ReadFile(filepath.Join(rootDir, flatLayoutManifestName))
+			Expect(err).To(BeNil())
+			manifest := map[string]string{}
+			Expect(json.Unmarshal(rawManifest, &manifest)).To(Succeed())
+			Expect(manifest).To(HaveLen(len(data)))
+			for fileName, apiPath := range manifest {
+				contents, err := os.ReadFile(filepath.Join(rootDir, fileName))
+				Expect(err).To(BeNil())
+				Expect(contents).To(Equal(data[apiPath]))
+			}
+			Expect(manifest).To(HaveKeyWithValue("apis%2Ffoo%2Fbar", "/apis/foo/bar"))
+		})
+
+		It("Rejects paths without a file name in the flat layout", func() {
+			_, err := getFlatFileName("/")
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("Removes stale resources before saving fresh ones", func() {
+			Expect(saveResources(rootDir, map[string][]byte{
+				"/apis/foo/stale": []byte("stale"),
+			})).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(rootDir, flatLayoutManifestName), []byte("{}"), 0600)).To(Succeed())
+
+			Expect(clearResultDir(rootDir)).To(Succeed())
+			Expect(saveResources(rootDir, data)).To(Succeed())
+
+			_, err := os.Stat(filepath.Join(rootDir, "apis", "foo", "stale"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			_, err = os.Stat(filepath.Join(rootDir, flatLayoutManifestName))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			contents, err := os.ReadFile(filepath.Join(rootDir, "apis", "foo", "bar"))
+			Expect(err).To(BeNil())
+			Expect(string(contents)).To(Equal("bar"))
+		})
+
+		It("Doesn't fail clearing a result directory that doesn't exist yet", func() {
+			Expect(clearResultDir(filepath.Join(rootDir, "missing"))).To(Succeed())
+		})
+	})
 })
 
 var _ = Describe("Testing filtering", func() {
@@ -295,6 +579,39 @@ var _ = Describe("Testing filtering", func() {
 		})
 	})
 
+	Context("Filtering bodies that aren't objects", func() {
+		It("filters an object", func() {
+			filteredOut, filterErr := filter(context.TODO(), []byte(`{"kind":"List","items":[{"name":"a"},{"name":"b"}]}`),
+				`[.items[].name]`)
+			Expect(filterErr).To(BeNil())
+			Expect(string(filteredOut)).To(Equal(`["a","b"]`))
+		})
+
+		It("filters an array", func() {
+			filteredOut, filterErr := filter(context.TODO(), []byte(`[{"name":"a","enabled":true},{"name":"b","enabled":false}]`),
+				`map(select(.enabled)) | map(.name)`)
+			Expect(filterErr).To(BeNil())
+			Expect(string(filteredOut)).To(Equal(`["a"]`))
+		})
+
+		It("filters a stream of objects as an array", func() {
+			filteredOut, filterErr := filter(context.TODO(), []byte("{\"name\":\"a\"}\n{\"name\":\"b\"}\n"),
+				`map(.name)`)
+			Expect(filterErr).To(BeNil())
+			Expect(string(filteredOut)).To(Equal(`["a","b"]`))
+		})
+
+		It("fails on an empty body", func() {
+			_, filterErr := filter(context.TODO(), []byte("  \n"), `.`)
+			Expect(filterErr).ToNot(BeNil())
+		})
+
+		It("fails on trailing garbage", func() {
+			_, filterErr := filter(context.TODO(), []byte(`{"name":"a"} garbage`), `.name`)
+			Expect(filterErr).ToNot(BeNil())
+		})
+	})
+
 	Context("Testing errors", func() {
 		It("outputs error if it can't create filter", func() {
 			_, filterErr := filter(context.TODO(), []byte{},
@@ -342,6 +659,104 @@ func (ff *notFoundFetcher) Stream(_ context.Context, _ resourceFetcherClients) (
 	}, "some name")
 }
 
+type staticFetcher struct {
+	contents string
+}
+
+func (sf *staticFetcher) Stream(_ context.Context, _ resourceFetcherClients) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(sf.contents)), nil
+}
+
+// slowFetcher returns its contents, or the error of its fallback, after a delay
+type slowFetcher struct {
+	delay    time.Duration
+	contents string
+	fallback resourceStreamer
+}
+
+func (sf *slowFetcher) Stream(ctx context.Context, rfClients resourceFetcherClients) (io.ReadCloser, error) {
+	time.Sleep(sf.delay)
+	if sf.fallback != nil {
+		return sf.fallback.Stream(ctx, rfClients)
+	}
+	return io.NopCloser(strings.NewReader(sf.contents)), nil
+}
+
+// blockingFetcher doesn't return until its context is cancelled
+type blockingFetcher struct{}
+
+func (bf *blockingFetcher) Stream(ctx context.Context, _ resourceFetcherClients) (io.ReadCloser, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type forbiddenFetcher struct{}
+
+func (ff *forbiddenFetcher) Stream(_ context.Context, _ resourceFetcherClients) (io.ReadCloser, error) {
+	return nil, errors.NewForbidden(schema.GroupResource{
+		Group:    "some group",
+		Resource: "some resource",
+	}, "some name", fmt.Errorf("no RBAC"))
+}
+
+// unavailableFetcher fails with a 503 the first failures times it's asked to
+// stream, and returns its contents afterwards. A negative number of failures
+// makes it fail forever.
+type unavailableFetcher struct {
+	failures int
+	calls    int
+	contents string
+}
+
+func (uf *unavailableFetcher) Stream(_ context.Context, _ resourceFetcherClients) (io.ReadCloser, error) {
+	uf.calls++
+	if uf.failures < 0 || uf.calls <= uf.failures {
+		return nil, errors.NewServiceUnavailable("the server is currently unable to handle the request")
+	}
+	return io.NopCloser(strings.NewReader(uf.contents)), nil
+}
+
+// noMatchFetcher fails like the API server does when the resource type of
+// the path isn't served by the cluster
+type noMatchFetcher struct{}
+
+func (nf *noMatchFetcher) Stream(_ context.Context, _ resourceFetcherClients) (io.ReadCloser, error) {
+	return nil, &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "some group", Kind: "Some"}}
+}
+
+// pagingClient paginates the MachineConfig lists of the fake client, which
+// otherwise ignores the limit and always returns everything
+type pagingClient struct {
+	runtimeclient.Client
+	limits []int64
+}
+
+func (pc *pagingClient) List(ctx context.Context, list runtimeclient.ObjectList, opts ...runtimeclient.ListOption) error {
+	mcList, ok := list.(*mcfgv1.MachineConfigList)
+	if !ok {
+		return pc.Client.List(ctx, list, opts...)
+	}
+	listOpts := &runtimeclient.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	pc.limits = append(pc.limits, listOpts.Limit)
+
+	all := &mcfgv1.MachineConfigList{}
+	if err := pc.Client.List(ctx, all); err != nil {
+		return err
+	}
+	start := 0
+	if listOpts.Continue != "" {
+		start, _ = strconv.Atoi(listOpts.Continue)
+	}
+	end := len(all.Items)
+	if listOpts.Limit > 0 && start+int(listOpts.Limit) < end {
+		end = start + int(listOpts.Limit)
+		mcList.Continue = strconv.Itoa(end)
+	}
+	mcList.Items = all.Items[start:end]
+	return nil
+}
+
 var _ = Describe("Testing fetching", func() {
 	var (
 		fakeClients resourceFetcherClients
@@ -358,10 +773,12 @@ var _ = Describe("Testing fetching", func() {
 				return &notFoundFetcher{}
 			}
 
-			files, warnings, err := fetch(context.TODO(),
+			files, allWarnings, err := fetch(context.TODO(),
 				fakeDispatcher,
 				resourceFetcherClients{},
-				[]utils.ResourcePath{{DumpPath: "key"}})
+				[]utils.ResourcePath{{DumpPath: "key"}},
+				1, nil, nil)
+			warnings := warningMessages(allWarnings)
 
 			Expect(err).To(BeNil())
 			Expect(files).To(HaveLen(1))
@@ -377,10 +794,12 @@ var _ = Describe("Testing fetching", func() {
 				return &notFoundFetcher{}
 			}
 
-			files, warnings, err := fetch(context.TODO(),
+			files, allWarnings, err := fetch(context.TODO(),
 				fakeDispatcher,
 				resourceFetcherClients{},
-				[]utils.ResourcePath{{DumpPath: "key", SuppressWarning: true}})
+				[]utils.ResourcePath{{DumpPath: "key", SuppressWarning: true}},
+				1, nil, nil)
+			warnings := warningMessages(allWarnings)
 
 			Expect(err).To(BeNil())
 			Expect(files).To(HaveLen(1))
@@ -388,6 +807,439 @@ var _ = Describe("Testing fetching", func() {
 			Expect(warnings).To(HaveLen(0))
 		})
 	})
+	Context("classifying fetch failures", func() {
+		gr := schema.GroupResource{Group: "some group", Resource: "some resource"}
+
+		It("classifies missing resource types as absent", func() {
+			err := &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "some group", Kind: "Some"}}
+			Expect(classifyFetchError(err)).To(Equal(ErrResourceTypeAbsent))
+		})
+		It("classifies missing objects as not found", func() {
+			Expect(classifyFetchError(errors.NewNotFound(gr, "some name"))).To(Equal(ErrResourceNotFound))
+		})
+		It("classifies 404s without an object name as absent", func() {
+			err := errors.NewGenericServerResponse(404, "GET", schema.GroupResource{}, "", "404 page not found", 0, true)
+			Expect(classifyFetchError(err)).To(Equal(ErrResourceTypeAbsent))
+		})
+		It("classifies forbidden access as forbidden", func() {
+			err := errors.NewForbidden(gr, "some name", fmt.Errorf("no RBAC"))
+			Expect(classifyFetchError(err)).To(Equal(ErrResourceForbidden))
+		})
+		It("classifies transient errors as unavailable", func() {
+			Expect(classifyFetchError(errors.NewServiceUnavailable("busy"))).To(Equal(ErrResourceUnavailable))
+			Expect(classifyFetchError(errors.NewTooManyRequests("slow down", 1))).To(Equal(ErrResourceUnavailable))
+			Expect(classifyFetchError(errors.NewTimeoutError("timed out", 1))).To(Equal(ErrResourceUnavailable))
+		})
+		It("doesn't classify other errors", func() {
+			Expect(classifyFetchError(errors.NewInternalError(fmt.Errorf("boom")))).To(BeNil())
+			Expect(classifyFetchError(nil)).To(BeNil())
+		})
+	})
+
+	Context("handle forbidden fetches", func() {
+		It("keeps the class of suppressed forbidden fetches", func() {
+			fakeDispatcher := func(uri string) resourceStreamer {
+				return &forbiddenFetcher{}
+			}
+
+			files, allWarnings, err := fetch(context.TODO(),
+				fakeDispatcher,
+				resourceFetcherClients{},
+				[]utils.ResourcePath{{ObjPath: "/some/path", DumpPath: "key", SuppressWarning: true}},
+				1, nil, nil)
+
+			Expect(err).To(BeNil())
+			Expect(files).To(BeEmpty())
+			Expect(warningMessages(allWarnings)).To(BeEmpty())
+			Expect(allWarnings).To(HaveLen(1))
+			Expect(allWarnings[0].uri).To(Equal("/some/path"))
+			Expect(allWarnings[0].class).To(Equal(ErrResourceForbidden))
+			Expect(allWarnings[0].suppressed).To(BeTrue())
+		})
+	})
+
+	Context("handle transient fetch failures", func() {
+		var origBackoff wait.Backoff
+
+		BeforeEach(func() {
+			origBackoff = fetchRetryBackoff
+			fetchRetryBackoff = wait.Backoff{Steps: 3, Duration: time.Millisecond}
+		})
+
+		AfterEach(func() {
+			fetchRetryBackoff = origBackoff
+		})
+
+		It("retries until the fetch succeeds", func() {
+			fetcher := &unavailableFetcher{failures: 2, contents: `{"key": "value"}`}
+			fakeDispatcher := func(uri string) resourceStreamer {
+				return fetcher
+			}
+
+			files, allWarnings, err := fetch(context.TODO(),
+				fakeDispatcher,
+				resourceFetcherClients{},
+				[]utils.ResourcePath{{ObjPath: "/some/path", DumpPath: "key"}},
+				1, nil, nil)
+			warnings := warningMessages(allWarnings)
+
+			Expect(err).To(BeNil())
+			Expect(fetcher.calls).To(Equal(3))
+			Expect(warnings).To(BeEmpty())
+			Expect(string(files["key"])).To(Equal(`{"key": "value"}`))
+		})
+
+		It("warns and carries on when the retries are exhausted", func() {
+			fetcher := &unavailableFetcher{failures: -1}
+			fakeDispatcher := func(uri string) resourceStreamer {
+				if uri == "/some/path" {
+					return fetcher
+				}
+				return &staticFetcher{contents: `{"key": "value"}`}
+			}
+
+			files, allWarnings, err := fetch(context.TODO(),
+				fakeDispatcher,
+				resourceFetcherClients{},
+				[]utils.ResourcePath{
+					{ObjPath: "/some/path", DumpPath: "key", SuppressWarning: true},
+					{ObjPath: "/other/path", DumpPath: "other"},
+				},
+				1, nil, nil)
+			warnings := warningMessages(allWarnings)
+
+			Expect(err).To(BeNil())
+			Expect(fetcher.calls).To(Equal(3))
+			Expect(files).To(HaveLen(1))
+			Expect(files).To(HaveKey("other"))
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(HavePrefix("could not fetch /some/path: "))
+		})
+	})
+
+	Context("fetching concurrently", func() {
+		const delay = 300 * time.Millisecond
+		slowDispatcher := func(uri string) resourceStreamer {
+			switch {
+			case strings.HasPrefix(uri, "/missing"):
+				return &slowFetcher{delay: delay, fallback: &notFoundFetcher{}}
+			case strings.HasPrefix(uri, "/forbidden"):
+				return &slowFetcher{delay: delay, fallback: &forbiddenFetcher{}}
+			}
+			return &slowFetcher{delay: delay, contents: fmt.Sprintf(`{"uri": "%s"}`, uri)}
+		}
+		objects := []utils.ResourcePath{
+			{ObjPath: "/one", DumpPath: "/one"},
+			{ObjPath: "/forbidden/one", DumpPath: "/forbidden/one"},
+			{ObjPath: "/two", DumpPath: "/two", Filter: ".uri"},
+			{ObjPath: "/missing", DumpPath: "/missing"},
+			{ObjPath: "/forbidden/two", DumpPath: "/forbidden/two"},
+			{ObjPath: "/three", DumpPath: "/three"},
+		}
+
+		It("takes as long as the slowest fetch, not the sum of them", func() {
+			start := time.Now()
+			files, allWarnings, err := fetch(context.TODO(), slowDispatcher, resourceFetcherClients{}, objects, len(objects), nil, nil)
+			warnings := warningMessages(allWarnings)
+			Expect(time.Since(start)).To(BeNumerically("<", 3*delay))
+
+			Expect(err).To(BeNil())
+			Expect(files).To(HaveLen(4))
+			Expect(string(files["/one"])).To(Equal(`{"uri": "/one"}`))
+			Expect(string(files["/two"])).To(Equal("/two"))
+			Expect(string(files["/three"])).To(Equal(`{"uri": "/three"}`))
+			_, isMarker := utils.ParseKubeAPIErrorMarker(files["/missing"])
+			Expect(isMarker).To(BeTrue())
+
+			By("reporting the warnings in the order of the objects")
+			Expect(warnings).To(HaveLen(3))
+			Expect(warnings[0]).To(HavePrefix("could not fetch /forbidden/one: "))
+			Expect(warnings[1]).To(HavePrefix("could not fetch /missing: "))
+			Expect(warnings[2]).To(HavePrefix("could not fetch /forbidden/two: "))
+		})
+
+		It("returns the same results as fetching one object at a time", func() {
+			files, warnings, err := fetch(context.TODO(), slowDispatcher, resourceFetcherClients{}, objects, 2, nil, nil)
+			Expect(err).To(BeNil())
+			serialFiles, serialWarnings, err := fetch(context.TODO(), slowDispatcher, resourceFetcherClients{}, objects, 1, nil, nil)
+			Expect(err).To(BeNil())
+			Expect(files).To(Equal(serialFiles))
+			Expect(warnings).To(Equal(serialWarnings))
+		})
+
+		It("fails if any of the fetches fails", func() {
+			failing := append([]utils.ResourcePath{}, objects...)
+			failing = append(failing, utils.ResourcePath{ObjPath: "/broken", DumpPath: "/broken", Filter: ".["})
+			_, _, err := fetch(context.TODO(), slowDispatcher, resourceFetcherClients{}, failing, len(failing), nil, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reports the failure that cancelled the fetches in flight", func() {
+			dispatcher := func(uri string) resourceStreamer {
+				if uri == "/slow" {
+					return &blockingFetcher{}
+				}
+				return &staticFetcher{contents: `{"key": "value"}`}
+			}
+			_, _, err := fetch(context.TODO(), dispatcher, resourceFetcherClients{}, []utils.ResourcePath{
+				{ObjPath: "/slow", DumpPath: "/slow"},
+				{ObjPath: "/broken", DumpPath: "/broken", Filter: ".["},
+			}, 2, nil, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("couldn't filter"))
+			Expect(err).ToNot(MatchError(context.Canceled))
+		})
+
+		It("reports the progress for each fetched object", func() {
+			reported := []string{}
+			progress := func(fetched, total int) {
+				reported = append(reported, fmt.Sprintf("%d/%d", fetched, total))
+			}
+			_, _, err := fetch(context.TODO(), slowDispatcher, resourceFetcherClients{}, objects, 2, progress, nil)
+			Expect(err).To(BeNil())
+			Expect(reported).To(Equal([]string{"0/6", "1/6", "2/6", "3/6", "4/6", "5/6", "6/6"}))
+		})
+	})
+
+	Context("counting the fetch warnings by reason", func() {
+		var origBackoff wait.Backoff
+
+		BeforeEach(func() {
+			origBackoff = fetchRetryBackoff
+			fetchRetryBackoff = wait.Backoff{Steps: 1, Duration: time.Millisecond}
+		})
+
+		AfterEach(func() {
+			fetchRetryBackoff = origBackoff
+		})
+
+		It("records the reason of every warning", func() {
+			fakeDispatcher := func(uri string) resourceStreamer {
+				switch uri {
+				case "/forbidden", "/forbidden-suppressed":
+					return &forbiddenFetcher{}
+				case "/notfound", "/notfound-suppressed":
+					return &notFoundFetcher{}
+				case "/nomatch":
+					return &noMatchFetcher{}
+				case "/unavailable":
+					return &unavailableFetcher{failures: -1}
+				}
+				return &staticFetcher{contents: `{"key": "value"}`}
+			}
+
+			reasons := []string{}
+			_, allWarnings, err := fetch(context.TODO(), fakeDispatcher, resourceFetcherClients{}, []utils.ResourcePath{
+				{ObjPath: "/forbidden", DumpPath: "forbidden"},
+				{ObjPath: "/forbidden-suppressed", DumpPath: "forbidden-suppressed", SuppressWarning: true},
+				{ObjPath: "/notfound", DumpPath: "notfound"},
+				{ObjPath: "/notfound-suppressed", DumpPath: "notfound-suppressed", SuppressWarning: true},
+				{ObjPath: "/nomatch", DumpPath: "nomatch"},
+				{ObjPath: "/unavailable", DumpPath: "unavailable"},
+				{ObjPath: "/fine", DumpPath: "fine"},
+			}, 2, nil, func(reason string) {
+				reasons = append(reasons, reason)
+			})
+			warnings := warningMessages(allWarnings)
+
+			Expect(err).To(BeNil())
+			Expect(warnings).To(HaveLen(4))
+			// Suppressed warnings aren't reported, so they aren't counted
+			Expect(reasons).To(Equal([]string{"forbidden", "notfound", "nomatch", "timeout"}))
+		})
+
+		It("records the counts in the scan status", func() {
+			scan := &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "platform-scan",
+					Namespace: "openshift-compliance",
+				},
+				Status: compv1alpha1.ComplianceScanStatus{
+					FetchWarnings: "timeout=1",
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(getScheme()).WithRuntimeObjects(scan).WithStatusSubresource(scan).Build()
+			getCounts := func() string {
+				scan := &compv1alpha1.ComplianceScan{}
+				key := types.NamespacedName{Name: "platform-scan", Namespace: "openshift-compliance"}
+				Expect(client.Get(context.TODO(), key, scan)).To(Succeed())
+				return scan.Status.FetchWarnings
+			}
+
+			reporter := newScanFetchWarningReporter(client, "openshift-compliance", "platform-scan")
+			reporter.record(fetchWarningNotFound)
+			reporter.record(fetchWarningForbidden)
+			reporter.record(fetchWarningForbidden)
+			reporter.report(nil)
+			Expect(getCounts()).To(Equal("forbidden=2,notfound=1"))
+
+			By("removing the counts of a previous run without warnings")
+			newScanFetchWarningReporter(client, "openshift-compliance", "platform-scan").report(nil)
+			Expect(getCounts()).To(BeEmpty())
+		})
+
+		It("records the rules checking absent resource types in the scan status", func() {
+			scan := &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "platform-scan",
+					Namespace: "openshift-compliance",
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(getScheme()).WithRuntimeObjects(scan).WithStatusSubresource(scan).Build()
+			getRules := func() []string {
+				scan := &compv1alpha1.ComplianceScan{}
+				key := types.NamespacedName{Name: "platform-scan", Namespace: "openshift-compliance"}
+				Expect(client.Get(context.TODO(), key, scan)).To(Succeed())
+				return scan.Status.AbsentResourceTypeRules
+			}
+
+			newScanFetchWarningReporter(client, "openshift-compliance", "platform-scan").report([]string{"rule_a", "rule_b"})
+			Expect(getRules()).To(Equal([]string{"rule_a", "rule_b"}))
+
+			By("removing the rules of a previous run")
+			newScanFetchWarningReporter(client, "openshift-compliance", "platform-scan").report(nil)
+			Expect(getRules()).To(BeEmpty())
+		})
+	})
+
+	Context("finding the rules that check absent resource types", func() {
+		It("only returns the rules of resources whose type is absent", func() {
+			c := &scapContentDataStream{
+				resourceRules: map[string][]string{
+					"/apis/foo.io/v1/foos":              {"rule_foo", "rule_shared"},
+					"/apis/bar.io/v1/bars":              {"rule_shared", "rule_bar"},
+					"/api/v1/namespaces/ns/secrets/s":   {"rule_secret"},
+					"/apis/config.openshift.io/v1/fine": {"rule_fine"},
+				},
+			}
+			rules := c.absentResourceTypeRules([]fetchWarning{
+				{uri: "/apis/foo.io/v1/foos", class: ErrResourceTypeAbsent, suppressed: true},
+				{uri: "/apis/bar.io/v1/bars", class: ErrResourceTypeAbsent},
+				{uri: "/api/v1/namespaces/ns/secrets/s", class: ErrResourceNotFound},
+				{uri: "/apis/config.openshift.io/v1/fine", message: "more than one object"},
+			})
+			Expect(rules).To(Equal([]string{"rule_bar", "rule_foo", "rule_shared"}))
+		})
+	})
+
+	Context("reporting the fetch progress in the scan", func() {
+		var client runtimeclient.Client
+
+		BeforeEach(func() {
+			scan := &compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform-scan", Namespace: "openshift-compliance"},
+			}
+			client = fake.NewClientBuilder().WithScheme(getScheme()).WithRuntimeObjects(scan).WithStatusSubresource(scan).Build()
+		})
+
+		getProgress := func() string {
+			scan := &compv1alpha1.ComplianceScan{}
+			key := types.NamespacedName{Name: "platform-scan", Namespace: "openshift-compliance"}
+			Expect(client.Get(context.TODO(), key, scan)).To(Succeed())
+			return scan.Status.FetchProgress
+		}
+
+		It("annotates the scan at most once per interval until all objects are fetched", func() {
+			reporter := newScanFetchProgressReporter(client, "openshift-compliance", "platform-scan", time.Hour)
+			reporter.report(0, 3)
+			Expect(getProgress()).To(Equal("0/3"))
+			reporter.report(1, 3)
+			Expect(getProgress()).To(Equal("0/3"))
+			reporter.report(3, 3)
+			Expect(getProgress()).To(Equal("3/3"))
+		})
+
+		It("reports every object without an interval", func() {
+			reporter := newScanFetchProgressReporter(client, "openshift-compliance", "platform-scan", 0)
+			for fetched := 0; fetched <= 3; fetched++ {
+				reporter.report(fetched, 3)
+				Expect(getProgress()).To(Equal(fmt.Sprintf("%d/3", fetched)))
+			}
+		})
+	})
+
+	Context("paginating Machine Config fetching", func() {
+		var client *pagingClient
+
+		newMc := func(name string, raw []byte) *mcfgv1.MachineConfig {
+			return &mcfgv1.MachineConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: mcfgv1.MachineConfigSpec{
+					Config: runtime.RawExtension{Raw: raw},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			rawIgn, err := json.Marshal(igntypes.Config{
+				Ignition: igntypes.Ignition{Version: "3.2.0"},
+				Storage: igntypes.Storage{
+					Files: []igntypes.File{{Node: igntypes.Node{Path: "/etc/foo"}}},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			objs := []runtime.Object{}
+			for i := 0; i < 7; i++ {
+				objs = append(objs, newMc(fmt.Sprintf("%02d-worker", i), rawIgn))
+			}
+			objs = append(objs, newMc("99-broken", []byte(`{"ignition": "not a config"}`)))
+
+			scheme := runtime.NewScheme()
+			Expect(mcfgv1.Install(scheme)).To(Succeed())
+			client = &pagingClient{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build(),
+			}
+			fakeClients = resourceFetcherClients{client: client, scheme: scheme}
+		})
+
+		streamNames := func(streamer resourceStreamer) []string {
+			stream, err := streamer.Stream(context.TODO(), fakeClients)
+			Expect(err).To(BeNil())
+			defer stream.Close()
+			mcList := &mcfgv1.MachineConfigList{}
+			Expect(json.NewDecoder(stream).Decode(mcList)).To(Succeed())
+			names := []string{}
+			for _, mc := range mcList.Items {
+				Expect(string(mc.Spec.Config.Raw)).ToNot(ContainSubstring("/etc/foo"))
+				names = append(names, mc.Name)
+			}
+			return names
+		}
+
+		It("lists the MachineConfigs with the configured page size", func() {
+			streamer := newStreamerDispatcher(3, 2)("/apis/machineconfiguration.openshift.io/v1/machineconfigs")
+			names := streamNames(streamer)
+			Expect(client.limits).To(Equal([]int64{3, 3, 3}))
+			Expect(names).To(Equal([]string{
+				"00-worker", "01-worker", "02-worker", "03-worker",
+				"04-worker", "05-worker", "06-worker",
+			}))
+		})
+
+		It("uses the default page size", func() {
+			streamNames(getStreamerFn("/apis/machineconfiguration.openshift.io/v1/machineconfigs"))
+			Expect(client.limits).To(Equal([]int64{defaultMcPageSize, defaultMcPageSize}))
+		})
+
+		It("skips a MachineConfig that can't be parsed", func() {
+			streamer := newStreamerDispatcher(8, 1)("/apis/machineconfiguration.openshift.io/v1/machineconfigs")
+			names := streamNames(streamer)
+			Expect(names).To(HaveLen(7))
+			Expect(names).ToNot(ContainElement("99-broken"))
+		})
+
+		It("keeps the page order when filtering pages concurrently", func() {
+			streamer := newStreamerDispatcher(1, 4)("/apis/machineconfiguration.openshift.io/v1/machineconfigs")
+			names := streamNames(streamer)
+			Expect(client.limits).To(HaveLen(8))
+			Expect(names).To(Equal([]string{
+				"00-worker", "01-worker", "02-worker", "03-worker",
+				"04-worker", "05-worker", "06-worker",
+			}))
+		})
+	})
+
 	Context("handle Machine Config fetching", func() {
 		var filter string
 		var files map[string][]byte
@@ -472,7 +1324,9 @@ var _ = Describe("Testing fetching", func() {
 				},
 			}
 
-			files, warnings, err = fetch(context.TODO(), getStreamerFn, fakeClients, fetchMcResources)
+			var allWarnings []fetchWarning
+			files, allWarnings, err = fetch(context.TODO(), getStreamerFn, fakeClients, fetchMcResources, 1, nil, nil)
+			warnings = warningMessages(allWarnings)
 		})
 		When("MC filters FIPS", func() {
 			BeforeEach(func() {
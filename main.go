@@ -40,6 +40,9 @@ func init() {
 	rootCmd.AddCommand(manager.ResultcollectorCmd)
 	rootCmd.AddCommand(manager.ResultServerCmd)
 	rootCmd.AddCommand(manager.RerunnerCmd)
+	rootCmd.AddCommand(manager.ProfileDiffCmd)
+	rootCmd.AddCommand(manager.ProfileListCmd)
+	rootCmd.AddCommand(manager.ArtifactFetcherCmd)
 }
 
 func main() {
@@ -13,6 +13,11 @@ const ComplianceCheckResultStatusLabel = "compliance.openshift.io/check-status"
 const ComplianceCheckResultSeverityLabel = "compliance.openshift.io/check-severity"
 const ComplianceCheckResultValueLabel = "compliance.openshift.io/check-has-value"
 
+// ComplianceCheckResultExternalRefLabel carries the external reference of the
+// rule of a ComplianceCheckResult, if the rule has one that's a valid label
+// value. The reference is always available in the annotation of the same name.
+const ComplianceCheckResultExternalRefLabel = RuleExternalRefAnnotationKey
+
 // ComplianceCheckResultLabel defines a label that will be included in the
 // ComplianceCheckResult objects. It indicates whether the result has an automated
 // remediation or not.
@@ -38,6 +43,17 @@ const ComplianceCheckResultInconsistentSourceAnnotation = "compliance.openshift.
 const ComplianceCheckResultMostCommonAnnotation = "compliance.openshift.io/most-common-status"
 const ComplianceCheckResultErrorAnnotation = "compliance.openshift.io/error-msg"
 
+// ComplianceCheckResultUnscoredAnnotation marks the result of a rule whose
+// XCCDF role is "unscored". Such a rule is only informational, so when it
+// fails, its result is INFO and it doesn't make the scan non-compliant.
+const ComplianceCheckResultUnscoredAnnotation = "compliance.openshift.io/unscored"
+
+// ComplianceCheckResultAbsentResourceTypeAnnotation marks the result of a rule
+// that checks resources whose type doesn't exist on the cluster, such as the
+// CRD of an operator that isn't installed. The result is left as the scanner
+// evaluated it, as the rule may well require the resources to exist.
+const ComplianceCheckResultAbsentResourceTypeAnnotation = "compliance.openshift.io/absent-resource-type"
+
 const (
 	// The check ran to completion and passed
 	CheckResultPass ComplianceCheckStatus = "PASS"
@@ -95,6 +111,9 @@ type ComplianceCheckResult struct {
 	Warnings []string `json:"warnings,omitempty"`
 	// It stores a list of values used by the check
 	ValuesUsed []string `json:"valuesUsed,omitempty"`
+	// Why the check was not applicable, if the scanner gave a reason
+	// (e.g. the check is specific to another architecture or platform).
+	NotApplicableReason string `json:"notApplicableReason,omitempty"`
 }
 
 // +kubebuilder:object:root=true
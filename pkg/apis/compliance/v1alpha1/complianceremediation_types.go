@@ -23,6 +23,11 @@ const (
 	RemediationNeedsReview         RemediationApplicationState = "NeedsReview"
 )
 
+// RemediationApplyFailedCondition is set on a ComplianceRemediation when the
+// API server rejected the remediation object. The condition's reason and
+// message are taken from the API server's response.
+const RemediationApplyFailedCondition ConditionType = "ApplyFailed"
+
 // +kubebuilder:validation:Enum=Configuration;Enforcement
 type RemediationType string
 
@@ -154,6 +159,8 @@ type ComplianceRemediationStatus struct {
 	// +kubebuilder:default="NotApplied"
 	ApplicationState RemediationApplicationState `json:"applicationState,omitempty"`
 	ErrorMessage     string                      `json:"errorMessage,omitempty"`
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
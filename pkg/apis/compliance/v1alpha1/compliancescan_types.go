@@ -22,6 +22,11 @@ const ComplianceScanRescanAnnotation = "compliance.openshift.io/rescan"
 // "api-checks" in the annotation.
 const ComplianceScanTimeoutAnnotation = "compliance.openshift.io/timeout"
 
+// ComplianceScanForceRefetchAnnotation makes the platform scan throw away
+// any resources a previous run left in its result directory and fetch them
+// again from the API server
+const ComplianceScanForceRefetchAnnotation = "compliance.openshift.io/force-refetch"
+
 // ComplianceScanLabel serves as an indicator for which ComplianceScan
 // owns the referenced object
 const ComplianceScanLabel = "compliance.openshift.io/scan-name"
@@ -143,6 +148,13 @@ type RawResultStorageSettings struct {
 	// policy of '0' disables rotation entirely. Defaults to 3.
 	// +kubebuilder:default=3
 	Rotation uint16 `json:"rotation,omitempty"`
+	// Specifies how long the raw results of a scan are kept, e.g. "720h" for
+	// 30 days. When set, results older than this are removed instead of
+	// rotating them by count, and rotation is ignored. The results of the
+	// latest scan are always kept.
+	// +optional
+	// +nullable
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
 	// Specifies the StorageClassName to use when creating the PersistentVolumeClaim
 	// to hold the raw results. By default this is null, which will attempt to use the
 	// default storage class configured in the cluster. If there is no default class specified
@@ -161,6 +173,11 @@ type RawResultStorageSettings struct {
 	// in case the target set of nodes have custom taints that don't allow certain
 	// workloads to run. Defaults to allowing scheduling on master nodes.
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Specifies whether platform scans also store the API resources they
+	// were evaluated against next to the raw results. This helps debugging
+	// unexpected check results. Defaults to false.
+	// +optional
+	StoreFetchedResources bool `json:"storeFetchedResources,omitempty"`
 }
 
 // ComplianceScanSettings groups together settings of a ComplianceScan
@@ -204,6 +221,12 @@ type ComplianceScanSettings struct {
 	// +kubebuilder:default=false
 	ShowNotApplicable bool `json:"showNotApplicable,omitempty"`
 
+	// Determines whether to skip creating ComplianceCheckResults for checks
+	// that pass. Only the number of passing checks is then recorded in the
+	// scan status, which reduces the number of objects large scans create.
+	// +kubebuilder:default=false
+	SkipPassingResults bool `json:"skipPassingResults,omitempty"`
+
 	// Defines the PriorityClass to use for launching scan related pods,
 	// the Name of a desired PriorityClass should be set here, this is an
 	// optional field, if PriorityClass is invalid or not found, it will be ignored.
@@ -253,6 +276,12 @@ type ComplianceScanSpec struct {
 	// tailoring file. It assumes a key called `tailoring.xml` which will
 	// have the tailoring contents.
 	TailoringConfigMap *TailoringConfigMapRef `json:"tailoringConfigMap,omitempty"`
+	// Is a list of API paths (e.g.
+	// /api/v1/namespaces/openshift-config/configmaps/my-config) to always
+	// fetch for platform scans, in addition to the ones the rules of the
+	// profile reference. Only applies to platform scans.
+	// +optional
+	ExtraResourcePaths []string `json:"extraResourcePaths,omitempty"`
 
 	ComplianceScanSettings `json:",inline"`
 }
@@ -286,6 +315,20 @@ type ComplianceScanStatus struct {
 	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
 	// Is the time when the scan was finished
 	EndTimestamp *metav1.Time `json:"endTimestamp,omitempty"`
+	// Is the number of passing checks that didn't get a
+	// ComplianceCheckResult because SkipPassingResults is set
+	SkippedPassingResults int `json:"skippedPassingResults,omitempty"`
+	// Is set by the platform scan while it fetches the resources it checks.
+	// Holds the number of resources fetched so far and the total number of
+	// resources, e.g. "12/40".
+	FetchProgress string `json:"fetchProgress,omitempty"`
+	// Is set by the platform scan once it fetched the resources it checks.
+	// Holds how many resources couldn't be fetched for each reason, e.g.
+	// "forbidden=2,notfound=1".
+	FetchWarnings string `json:"fetchWarnings,omitempty"`
+	// Are the IDs of the rules that check resources whose type doesn't
+	// exist on the cluster, as found by the platform scan.
+	AbsentResourceTypeRules []string `json:"absentResourceTypeRules,omitempty"`
 }
 
 // StorageReference stores a reference to where certain objects are being stored
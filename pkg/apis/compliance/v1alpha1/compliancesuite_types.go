@@ -4,6 +4,7 @@ import (
 	"reflect"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // SuiteLabel indicates that an object (normally the ComplianceScan
@@ -75,6 +76,10 @@ type ComplianceScanStatusWrapper struct {
 type ComplianceSuiteSettings struct {
 	// Defines whether or not the remediations should be applied automatically
 	AutoApplyRemediations bool `json:"autoApplyRemediations,omitempty"`
+	// Restricts which remediations are applied automatically. If not set,
+	// all the remediations of the suite are applied.
+	// +optional
+	AutoApplyRemediationSelector *RemediationSelector `json:"autoApplyRemediationSelector,omitempty"`
 	// Defines whether or not the remediations should be updated automatically.
 	// This is done by deleting the "outdated" object from the remediation.
 	AutoUpdateRemediations bool `json:"autoUpdateRemediations,omitempty"`
@@ -88,6 +93,53 @@ type ComplianceSuiteSettings struct {
 	Suspend bool `json:"suspend,omitempty"`
 }
 
+// RemediationSelector selects remediations by their type and annotations.
+// A remediation is selected if it matches all of the set criteria.
+type RemediationSelector struct {
+	// Only select remediations of these types
+	// +optional
+	Types []RemediationType `json:"types,omitempty"`
+	// Only select remediations whose annotations match this selector. It
+	// uses the same syntax as a label selector.
+	// +optional
+	AnnotationSelector *metav1.LabelSelector `json:"annotationSelector,omitempty"`
+}
+
+// Matches returns whether the remediation is selected. An error is returned
+// if the annotation selector is invalid.
+func (s *RemediationSelector) Matches(rem *ComplianceRemediation) (bool, error) {
+	if s == nil {
+		return true, nil
+	}
+	if len(s.Types) > 0 {
+		// Remediations without a type are configuration remediations
+		remType := rem.Spec.Type
+		if remType == "" {
+			remType = ConfigurationRemediation
+		}
+		found := false
+		for _, selectedType := range s.Types {
+			if remType == selectedType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	if s.AnnotationSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(s.AnnotationSelector)
+		if err != nil {
+			return false, err
+		}
+		if !selector.Matches(labels.Set(rem.GetAnnotations())) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // ComplianceSuiteSpec defines the desired state of ComplianceSuite
 // +k8s:openapi-gen=true
 type ComplianceSuiteSpec struct {
@@ -97,6 +149,20 @@ type ComplianceSuiteSpec struct {
 	Scans []ComplianceScanSpecWrapper `json:"scans"`
 }
 
+// SeverityCount tallies the ComplianceCheckResults of a certain severity
+// by their status
+// +k8s:openapi-gen=true
+type SeverityCount struct {
+	// The severity of the counted check results
+	Severity ComplianceCheckResultSeverity `json:"severity"`
+	// The number of passing checks
+	Pass int `json:"pass"`
+	// The number of failing checks
+	Fail int `json:"fail"`
+	// The number of checks that errored
+	Error int `json:"error"`
+}
+
 // ComplianceSuiteStatus defines the observed state of ComplianceSuite
 // +k8s:openapi-gen=true
 type ComplianceSuiteStatus struct {
@@ -105,6 +171,11 @@ type ComplianceSuiteStatus struct {
 	Phase        ComplianceScanStatusPhase     `json:"phase,omitempty"`
 	Result       ComplianceScanStatusResult    `json:"result,omitempty"`
 	ErrorMessage string                        `json:"errorMessage,omitempty"`
+	// Contains a breakdown of the suite's check results per severity.
+	// This is only filled in once all the scans are done.
+	// +optional
+	// +listType=atomic
+	SeverityCounts []SeverityCount `json:"severityCounts,omitempty"`
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
 }
@@ -178,14 +249,26 @@ func (s *ComplianceSuite) LowestCommonState() ComplianceScanStatusPhase {
 }
 
 func (s *ComplianceSuite) LowestCommonResult() ComplianceScanStatusResult {
-	if len(s.Status.ScanStatuses) == 0 {
+	results := make([]ComplianceScanStatusResult, 0, len(s.Status.ScanStatuses))
+	for _, scanStatusWrap := range s.Status.ScanStatuses {
+		results = append(results, scanStatusWrap.Result)
+	}
+	return LowestCommonResultOf(results)
+}
+
+// LowestCommonResultOf returns the result of a suite whose scans have the
+// given results: the worst of them, from not available, error, inconsistent,
+// non-compliant, not applicable to compliant. Without any results, the
+// result is not available.
+func LowestCommonResultOf(results []ComplianceScanStatusResult) ComplianceScanStatusResult {
+	if len(results) == 0 {
 		return ResultNotAvailable
 	}
 
 	lowestCommonResult := ResultCompliant
 
-	for _, scanStatusWrap := range s.Status.ScanStatuses {
-		lowestCommonResult = resultCompare(lowestCommonResult, scanStatusWrap.Result)
+	for _, result := range results {
+		lowestCommonResult = resultCompare(lowestCommonResult, result)
 	}
 
 	return lowestCommonResult
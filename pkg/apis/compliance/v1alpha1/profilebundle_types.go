@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -16,6 +18,28 @@ const ProfileBundleOwnerLabel = "compliance.openshift.io/profile-bundle"
 // ProfileImageDigestAnnotation is the parsed out digest of the content image
 const ProfileImageDigestAnnotation = "compliance.openshift.io/image-digest"
 
+// ProfileBundleTrackDigestAnnotation can be set to "true" on a ProfileBundle
+// whose content image is a mutable registry tag. The operator then
+// periodically resolves the tag to a digest and re-parses the content when
+// the digest changes.
+const ProfileBundleTrackDigestAnnotation = "compliance.openshift.io/track-image-digest"
+
+// ProfileBundleSignatureKeysAnnotation can be set on a ProfileBundle to the
+// name of a ConfigMap in the same namespace whose values are PEM encoded
+// public keys. The content image then has to carry a cosign signature made
+// with one of those keys before it's parsed.
+const ProfileBundleSignatureKeysAnnotation = "compliance.openshift.io/verify-signature-keys"
+
+// ProfileBundleDebugAnnotation can be set to "true" on a ProfileBundle to make
+// the profileparser log debug information about the profiles, rules and
+// variables it discovers in the content.
+const ProfileBundleDebugAnnotation = "compliance.openshift.io/debug"
+
+// ProfileBundleParserJobAnnotation can be set to "true" on a ProfileBundle to
+// parse its content with a Job rather than a Deployment. The pod of the Job
+// doesn't have to be kept running once the content is parsed.
+const ProfileBundleParserJobAnnotation = "compliance.openshift.io/parse-with-job"
+
 // DataStreamStatusType is the type for the data stream status
 type DataStreamStatusType string
 
@@ -29,12 +53,67 @@ const (
 	DataStreamInvalid DataStreamStatusType = "INVALID"
 )
 
+// ContentSourceType defines how the content of a ProfileBundle is obtained
+type ContentSourceType string
+
+const (
+	// ContentSourceImage copies the content file out of a container image
+	// that has it baked in
+	ContentSourceImage ContentSourceType = "Image"
+	// ContentSourceArtifact downloads the content file from a layer of an
+	// OCI artifact
+	ContentSourceArtifact ContentSourceType = "Artifact"
+)
+
+// ProfileBundleConditionDegraded is set to true when the content of the
+// ProfileBundle couldn't be parsed. Its reason tells why.
+const ProfileBundleConditionDegraded ConditionType = "Degraded"
+
+// ProfileBundleConditionDuplicateRuleIDs is set to true when several rules of
+// the content share an ID. Only the first of those rules is used, its message
+// lists the IDs.
+const ProfileBundleConditionDuplicateRuleIDs ConditionType = "DuplicateRuleIDs"
+
+const (
+	// ProfileBundleReasonInvalidImageReference means that the content image
+	// reference couldn't be resolved
+	ProfileBundleReasonInvalidImageReference ConditionReason = "InvalidImageReference"
+	// ProfileBundleReasonImagePullFailed means that the content image couldn't
+	// be pulled
+	ProfileBundleReasonImagePullFailed ConditionReason = "ImagePullFailed"
+	// ProfileBundleReasonContentFileMissing means that the content file wasn't
+	// found in the content image
+	ProfileBundleReasonContentFileMissing ConditionReason = "ContentFileMissing"
+	// ProfileBundleReasonSignatureVerificationFailed means that the signature
+	// of the content image couldn't be verified
+	ProfileBundleReasonSignatureVerificationFailed ConditionReason = "SignatureVerificationFailed"
+	// ProfileBundleReasonParseFailed means that the content file was found
+	// but couldn't be parsed
+	ProfileBundleReasonParseFailed ConditionReason = "ParseFailed"
+)
+
 // Defines the desired state of ProfileBundle
 type ProfileBundleSpec struct {
 	// Is the path for the image that contains the content for this bundle.
 	ContentImage string `json:"contentImage"`
 	// Is the path for the file in the image that contains the content for this bundle.
 	ContentFile string `json:"contentFile"`
+	// Defines what the contentImage points to. "Image" (the default) is a
+	// container image with the content file in it. "Artifact" is an OCI
+	// artifact with the content file as one of its layers, which is
+	// downloaded directly from the registry. Only registries that allow
+	// anonymous pulls are supported for artifacts.
+	// +kubebuilder:validation:Enum=Image;Artifact
+	// +kubebuilder:default=Image
+	// +optional
+	ContentSource ContentSourceType `json:"contentSource,omitempty"`
+	// Defines the pull policy of the content image. If unset, images
+	// pinned to a digest are only pulled if they're not present on the node
+	// and images referenced by a tag are always pulled. It doesn't apply to
+	// artifacts.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ContentImagePullPolicy corev1.PullPolicy `json:"contentImagePullPolicy,omitempty"`
 }
 
 // Defines the observed state of ProfileBundle
@@ -46,6 +125,11 @@ type ProfileBundleStatus struct {
 	ErrorMessage string `json:"errorMessage,omitempty"`
 	// Defines the conditions for the ProfileBundle. Valid conditions are:
 	//  - Ready: Indicates if the ProfileBundle is Ready parsing or not.
+	//  - Degraded: Indicates if the ProfileBundle couldn't be parsed. The
+	//    reason is one of InvalidImageReference, ImagePullFailed,
+	//    ContentFileMissing, SignatureVerificationFailed or ParseFailed.
+	//  - DuplicateRuleIDs: Indicates if several rules of the content share
+	//    an ID.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
 }
@@ -75,6 +159,12 @@ type ProfileBundleList struct {
 	Items           []ProfileBundle `json:"items"`
 }
 
+// UsesArtifact returns whether the content of the bundle is pulled from an
+// OCI artifact
+func (pb *ProfileBundle) UsesArtifact() bool {
+	return pb.Spec.ContentSource == ContentSourceArtifact
+}
+
 func (s *ProfileBundleStatus) SetConditionPending() {
 	s.Conditions.SetCondition(Condition{
 		Type:    "Ready",
@@ -82,15 +172,29 @@ func (s *ProfileBundleStatus) SetConditionPending() {
 		Reason:  "Pending",
 		Message: "The profile bundle is waiting to be parsed",
 	})
+	s.Conditions.SetCondition(Condition{
+		Type:    ProfileBundleConditionDegraded,
+		Status:  corev1.ConditionFalse,
+		Reason:  "Pending",
+		Message: "The profile bundle is waiting to be parsed",
+	})
 }
 
-func (s *ProfileBundleStatus) SetConditionInvalid() {
+// SetConditionInvalid marks the bundle as not ready and degraded for the given
+// reason. The message describes the failure in more detail.
+func (s *ProfileBundleStatus) SetConditionInvalid(reason ConditionReason, message string) {
 	s.Conditions.SetCondition(Condition{
 		Type:    "Ready",
 		Status:  corev1.ConditionFalse,
 		Reason:  "Invalid",
 		Message: "Couldn't parse profile bundle",
 	})
+	s.Conditions.SetCondition(Condition{
+		Type:    ProfileBundleConditionDegraded,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
 }
 
 func (s *ProfileBundleStatus) SetConditionReady() {
@@ -100,6 +204,27 @@ func (s *ProfileBundleStatus) SetConditionReady() {
 		Reason:  "Valid",
 		Message: "Profile bundle successfully parsed",
 	})
+	s.Conditions.SetCondition(Condition{
+		Type:    ProfileBundleConditionDegraded,
+		Status:  corev1.ConditionFalse,
+		Reason:  "Valid",
+		Message: "Profile bundle successfully parsed",
+	})
+}
+
+// SetConditionDuplicateRuleIDs reports the IDs that several rules of the
+// content share. Without any, the condition is removed.
+func (s *ProfileBundleStatus) SetConditionDuplicateRuleIDs(ids []string) {
+	if len(ids) == 0 {
+		s.Conditions.RemoveCondition(ProfileBundleConditionDuplicateRuleIDs)
+		return
+	}
+	s.Conditions.SetCondition(Condition{
+		Type:    ProfileBundleConditionDuplicateRuleIDs,
+		Status:  corev1.ConditionTrue,
+		Reason:  "ContentHasDuplicates",
+		Message: "Only the first rule with each of these IDs is used: " + strings.Join(ids, ", "),
+	})
 }
 
 func init() {
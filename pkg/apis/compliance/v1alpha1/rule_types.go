@@ -18,6 +18,11 @@ const RuleHideTagAnnotationKey = "compliance.openshift.io/hide-tag"
 // RuleVariableAnnotationKey store list of xccdf variables used to render the rule
 const RuleVariableAnnotationKey = "compliance.openshift.io/rule-variable"
 
+// RuleExternalRefAnnotationKey can be set on a rule to the ID the rule has in
+// an external system, such as a ticketing system. It's copied onto the
+// ComplianceCheckResults of the rule.
+const RuleExternalRefAnnotationKey = "compliance.openshift.io/external-ref"
+
 // RuleProfileAnnotationKey is the annotation used to store which profiles are using a particular rule
 const RuleProfileAnnotationKey = "compliance.openshift.io/profiles"
 
@@ -12,6 +12,10 @@ const DisableOutdatedReferenceValidation = "compliance.openshift.io/disable-outd
 // PruneOutdatedReferencesAnnotationKey is the annotation key used to indicate that the outdated references of rules or variables should be pruned
 const PruneOutdatedReferencesAnnotationKey = "compliance.openshift.io/prune-outdated-references"
 
+// AllowEmptyRulePatternsAnnotation is the annotation key used to indicate that rule name patterns
+// that don't match any rule should be ignored instead of being treated as an error
+const AllowEmptyRulePatternsAnnotation = "compliance.openshift.io/allow-empty-rule-patterns"
+
 // RuleLastCheckTypeChangedAnnotationKey is the annotation key used to indicate that the rule check type has changed, store its previous check type
 const RuleLastCheckTypeChangedAnnotationKey = "compliance.openshift.io/rule-last-check-type"
 
@@ -20,10 +24,18 @@ const ExtendedProfileGuidLabel = "compliance.openshift.io/extended-profile-uniqu
 
 // RuleReferenceSpec specifies a rule to be selected/deselected, as well as the reason why
 type RuleReferenceSpec struct {
-	// Name of the rule that's being referenced
+	// Name of the rule that's being referenced. It can also be a pattern
+	// using the shell glob syntax (e.g. "*-audit-*"), which is expanded to
+	// the matching rules of the profile bundle.
 	Name string `json:"name"`
 	// Rationale of why this rule is being selected/deselected
 	Rationale string `json:"rationale"`
+	// Severity overrides the severity the content gives to the rule. The
+	// check results of the rule are reported with this severity instead.
+	// Only used for enabled and manual rules.
+	// +kubebuilder:validation:Enum=unknown;info;low;medium;high
+	// +optional
+	Severity ComplianceCheckResultSeverity `json:"severity,omitempty"`
 }
 
 // ValueReferenceSpec specifies a value to be set for a variable with a reason why
@@ -87,6 +99,18 @@ type TailoredProfileStatus struct {
 	State        TailoredProfileState `json:"state,omitempty"`
 	ErrorMessage string               `json:"errorMessage,omitempty"`
 	Warnings     string               `json:"warnings,omitempty"`
+	// The rules that each of the rule name patterns matched
+	// +optional
+	ExpandedRulePatterns []RulePatternExpansion `json:"expandedRulePatterns,omitempty"`
+}
+
+// RulePatternExpansion records the rules a rule name pattern was expanded to
+type RulePatternExpansion struct {
+	// The pattern as given in the rule selection
+	Pattern string `json:"pattern"`
+	// The names of the rules the pattern matched, sorted
+	// +optional
+	Rules []string `json:"rules,omitempty"`
 }
 
 // OutputRef is a reference to the object created from the tailored profile
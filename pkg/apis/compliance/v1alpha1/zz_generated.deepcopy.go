@@ -21,8 +21,9 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -99,7 +100,7 @@ func (in *ComplianceRemediation) DeepCopyInto(out *ComplianceRemediation) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediation.
@@ -207,6 +208,13 @@ func (in *ComplianceRemediationSpecMeta) DeepCopy() *ComplianceRemediationSpecMe
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComplianceRemediationStatus) DeepCopyInto(out *ComplianceRemediationStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceRemediationStatus.
@@ -284,7 +292,7 @@ func (in *ComplianceScanSettings) DeepCopyInto(out *ComplianceScanSettings) {
 	in.RawResultStorage.DeepCopyInto(&out.RawResultStorage)
 	if in.ScanTolerations != nil {
 		in, out := &in.ScanTolerations, &out.ScanTolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -296,7 +304,7 @@ func (in *ComplianceScanSettings) DeepCopyInto(out *ComplianceScanSettings) {
 	}
 	if in.ScanLimits != nil {
 		in, out := &in.ScanLimits, &out.ScanLimits
-		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		*out = make(map[corev1.ResourceName]resource.Quantity, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val.DeepCopy()
 		}
@@ -328,6 +336,11 @@ func (in *ComplianceScanSpec) DeepCopyInto(out *ComplianceScanSpec) {
 		*out = new(TailoringConfigMapRef)
 		**out = **in
 	}
+	if in.ExtraResourcePaths != nil {
+		in, out := &in.ExtraResourcePaths, &out.ExtraResourcePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.ComplianceScanSettings.DeepCopyInto(&out.ComplianceScanSettings)
 }
 
@@ -376,6 +389,11 @@ func (in *ComplianceScanStatus) DeepCopyInto(out *ComplianceScanStatus) {
 		in, out := &in.EndTimestamp, &out.EndTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.AbsentResourceTypeRules != nil {
+		in, out := &in.AbsentResourceTypeRules, &out.AbsentResourceTypeRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanStatus.
@@ -466,6 +484,11 @@ func (in *ComplianceSuiteList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComplianceSuiteSettings) DeepCopyInto(out *ComplianceSuiteSettings) {
 	*out = *in
+	if in.AutoApplyRemediationSelector != nil {
+		in, out := &in.AutoApplyRemediationSelector, &out.AutoApplyRemediationSelector
+		*out = new(RemediationSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSuiteSettings.
@@ -481,7 +504,7 @@ func (in *ComplianceSuiteSettings) DeepCopy() *ComplianceSuiteSettings {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComplianceSuiteSpec) DeepCopyInto(out *ComplianceSuiteSpec) {
 	*out = *in
-	out.ComplianceSuiteSettings = in.ComplianceSuiteSettings
+	in.ComplianceSuiteSettings.DeepCopyInto(&out.ComplianceSuiteSettings)
 	if in.Scans != nil {
 		in, out := &in.Scans, &out.Scans
 		*out = make([]ComplianceScanSpecWrapper, len(*in))
@@ -511,6 +534,11 @@ func (in *ComplianceSuiteStatus) DeepCopyInto(out *ComplianceSuiteStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SeverityCounts != nil {
+		in, out := &in.SeverityCounts, &out.SeverityCounts
+		*out = make([]SeverityCount, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
@@ -792,6 +820,11 @@ func (in *ProfilePayload) DeepCopy() *ProfilePayload {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RawResultStorageSettings) DeepCopyInto(out *RawResultStorageSettings) {
 	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.StorageClassName != nil {
 		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
@@ -799,7 +832,7 @@ func (in *RawResultStorageSettings) DeepCopyInto(out *RawResultStorageSettings)
 	}
 	if in.PVAccessModes != nil {
 		in, out := &in.PVAccessModes, &out.PVAccessModes
-		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
 		copy(*out, *in)
 	}
 	if in.NodeSelector != nil {
@@ -811,7 +844,7 @@ func (in *RawResultStorageSettings) DeepCopyInto(out *RawResultStorageSettings)
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -844,6 +877,31 @@ func (in *RemediationObjectDependencyReference) DeepCopy() *RemediationObjectDep
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationSelector) DeepCopyInto(out *RemediationSelector) {
+	*out = *in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]RemediationType, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnnotationSelector != nil {
+		in, out := &in.AnnotationSelector, &out.AnnotationSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationSelector.
+func (in *RemediationSelector) DeepCopy() *RemediationSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rule) DeepCopyInto(out *Rule) {
 	*out = *in
@@ -902,6 +960,26 @@ func (in *RuleList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulePatternExpansion) DeepCopyInto(out *RulePatternExpansion) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulePatternExpansion.
+func (in *RulePatternExpansion) DeepCopy() *RulePatternExpansion {
+	if in == nil {
+		return nil
+	}
+	out := new(RulePatternExpansion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RulePayload) DeepCopyInto(out *RulePayload) {
 	*out = *in
@@ -944,7 +1022,7 @@ func (in *ScanSetting) DeepCopyInto(out *ScanSetting) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.ComplianceSuiteSettings = in.ComplianceSuiteSettings
+	in.ComplianceSuiteSettings.DeepCopyInto(&out.ComplianceSuiteSettings)
 	in.ComplianceScanSettings.DeepCopyInto(&out.ComplianceScanSettings)
 	if in.Roles != nil {
 		in, out := &in.Roles, &out.Roles
@@ -1067,7 +1145,7 @@ func (in *ScanSettingBindingStatus) DeepCopyInto(out *ScanSettingBindingStatus)
 	}
 	if in.OutputRef != nil {
 		in, out := &in.OutputRef, &out.OutputRef
-		*out = new(v1.TypedLocalObjectReference)
+		*out = new(corev1.TypedLocalObjectReference)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -1114,6 +1192,21 @@ func (in *ScanSettingList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeverityCount) DeepCopyInto(out *SeverityCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeverityCount.
+func (in *SeverityCount) DeepCopy() *SeverityCount {
+	if in == nil {
+		return nil
+	}
+	out := new(SeverityCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageReference) DeepCopyInto(out *StorageReference) {
 	*out = *in
@@ -1135,7 +1228,7 @@ func (in *TailoredProfile) DeepCopyInto(out *TailoredProfile) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailoredProfile.
@@ -1227,6 +1320,13 @@ func (in *TailoredProfileSpec) DeepCopy() *TailoredProfileSpec {
 func (in *TailoredProfileStatus) DeepCopyInto(out *TailoredProfileStatus) {
 	*out = *in
 	out.OutputRef = in.OutputRef
+	if in.ExpandedRulePatterns != nil {
+		in, out := &in.ExpandedRulePatterns, &out.ExpandedRulePatterns
+		*out = make([]RulePatternExpansion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TailoredProfileStatus.
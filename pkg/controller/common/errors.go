@@ -32,6 +32,11 @@ func (cerr NonRetriableCtrlError) Error() string {
 	return cerr.err.Error()
 }
 
+// Unwrap returns the wrapped error
+func (cerr NonRetriableCtrlError) Unwrap() error {
+	return cerr.err
+}
+
 // blank assignment to verify that RetriableCtrlError implements error
 var _ error = &NonRetriableCtrlError{}
 
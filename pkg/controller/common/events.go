@@ -22,6 +22,12 @@ func NewSafeRecorder(name string, mgr manager.Manager) *SafeRecorder {
 
 }
 
+// NewSafeRecorderFromRecorder wraps the given recorder, e.g. a fake one in
+// the tests
+func NewSafeRecorderFromRecorder(recorder record.EventRecorder) *SafeRecorder {
+	return &SafeRecorder{recorder: recorder}
+}
+
 func (sr *SafeRecorder) Event(object runtime.Object, eventtype, reason, message string) {
 	if sr.recorder == nil {
 		return
@@ -22,3 +22,22 @@ func RemoveFinalizer(slice []string, f string) (result []string) {
 	}
 	return
 }
+
+// EnsureFinalizer returns the given list of finalizers with exactly one
+// occurrence of the given finalizer `f`, dropping any duplicates, and
+// whether the list had to be changed for that
+func EnsureFinalizer(slice []string, f string) ([]string, bool) {
+	result := make([]string, 0, len(slice)+1)
+	seen := make(map[string]bool, len(slice)+1)
+	for _, item := range slice {
+		if seen[item] {
+			continue
+		}
+		seen[item] = true
+		result = append(result, item)
+	}
+	if !seen[f] {
+		result = append(result, f)
+	}
+	return result, len(result) != len(slice)
+}
@@ -0,0 +1,36 @@
+package common
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Finalizer helpers", func() {
+	const finalizer = "test.finalizer"
+
+	Context("Ensuring a finalizer", func() {
+		It("Should add a missing finalizer", func() {
+			result, changed := EnsureFinalizer([]string{"other"}, finalizer)
+			Expect(changed).To(BeTrue())
+			Expect(result).To(Equal([]string{"other", finalizer}))
+		})
+
+		It("Should leave a list with the finalizer alone", func() {
+			result, changed := EnsureFinalizer([]string{finalizer, "other"}, finalizer)
+			Expect(changed).To(BeFalse())
+			Expect(result).To(Equal([]string{finalizer, "other"}))
+		})
+
+		It("Should drop duplicated finalizers", func() {
+			result, changed := EnsureFinalizer([]string{finalizer, "other", finalizer, "other"}, finalizer)
+			Expect(changed).To(BeTrue())
+			Expect(result).To(Equal([]string{finalizer, "other"}))
+		})
+	})
+
+	Context("Removing a finalizer", func() {
+		It("Should remove every occurrence of the finalizer", func() {
+			Expect(RemoveFinalizer([]string{finalizer, "other", finalizer}, finalizer)).To(Equal([]string{"other"}))
+		})
+	})
+})
@@ -235,7 +235,10 @@ func (r *ReconcileComplianceRemediation) reconcileRemediation(instance *compv1al
 				return fmt.Errorf("failed to set related remediations to apply: %w", err)
 			}
 			err = r.createRemediation(obj, objectLogger)
-			if err != nil {
+			if err != nil && !common.IsRetriable(err) {
+				// Keep the error non-retriable so it's persisted in the status
+				return err
+			} else if err != nil {
 				return fmt.Errorf("failed to create remediation: %w", err)
 			}
 			return nil
@@ -306,6 +309,11 @@ func (r *ReconcileComplianceRemediation) createRemediation(remObj *unstructured.
 				" Please update the compliance-operator's permissions: %s", createErr)
 	}
 
+	if kerrors.IsInvalid(createErr) {
+		// The API server rejected the object, retrying won't change that
+		return common.WrapNonRetriableCtrlError(createErr)
+	}
+
 	return createErr
 }
 
@@ -321,6 +329,11 @@ func (r *ReconcileComplianceRemediation) patchRemediation(remObj *unstructured.U
 				"Please update the compliance-operator's permissions: %s", patchErr)
 	}
 
+	if kerrors.IsInvalid(patchErr) {
+		// The API server rejected the object, retrying won't change that
+		return common.WrapNonRetriableCtrlError(patchErr)
+	}
+
 	return patchErr
 
 }
@@ -764,6 +777,7 @@ func isNoLongerOutdated(r *compv1alpha1.ComplianceRemediation) bool {
 }
 
 func (r *ReconcileComplianceRemediation) setRemediationStatus(rem *compv1alpha1.ComplianceRemediation, errorApplying error, logger logr.Logger) {
+	setApplyFailedCondition(rem, errorApplying)
 	if errorApplying != nil {
 		if wasErrorOnOptionalRemediation(rem, errorApplying) {
 			logger.Info("Optional remediation couldn't be applied")
@@ -806,6 +820,38 @@ func (r *ReconcileComplianceRemediation) setRemediationStatus(rem *compv1alpha1.
 	rem.Status.ApplicationState = compv1alpha1.RemediationApplied
 }
 
+// setApplyFailedCondition records the API server's reason, message and the
+// fields involved in the failure if the remediation object was rejected. The
+// condition is removed otherwise.
+func setApplyFailedCondition(rem *compv1alpha1.ComplianceRemediation, errorApplying error) {
+	var apiStatus kerrors.APIStatus
+	if errorApplying == nil || !errors.As(errorApplying, &apiStatus) {
+		rem.Status.Conditions.RemoveCondition(compv1alpha1.RemediationApplyFailedCondition)
+		return
+	}
+
+	status := apiStatus.Status()
+	message := status.Message
+	if status.Details != nil {
+		fields := []string{}
+		for _, cause := range status.Details.Causes {
+			if cause.Field != "" {
+				fields = append(fields, cause.Field)
+			}
+		}
+		if len(fields) > 0 {
+			message = fmt.Sprintf("%s (fields: %s)", message, strings.Join(fields, ", "))
+		}
+	}
+
+	rem.Status.Conditions.SetCondition(compv1alpha1.Condition{
+		Type:    compv1alpha1.RemediationApplyFailedCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  compv1alpha1.ConditionReason(status.Reason),
+		Message: message,
+	})
+}
+
 func wasErrorOnOptionalRemediation(r *compv1alpha1.ComplianceRemediation, errorApplying error) bool {
 	annotations := r.GetAnnotations()
 	// This wasn't an optional remediation. That's represented through
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
 	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
 	"github.com/clarketm/json"
@@ -21,9 +22,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -180,6 +185,62 @@ var _ = Describe("Testing complianceremediation controller", func() {
 			})
 		})
 
+		Context("with a remediation object rejected by the API server", func() {
+			BeforeEach(func() {
+				cm := &corev1.ConfigMap{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "ConfigMap",
+						APIVersion: "v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cm",
+						Namespace: "test-ns",
+					},
+					Data: map[string]string{
+						"key": "val",
+					},
+				}
+				unstructuredCM, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cm)
+				Expect(err).ToNot(HaveOccurred())
+				remediationinstance.Spec.Current.Object = &unstructured.Unstructured{
+					Object: unstructuredCM,
+				}
+				err = reconciler.Client.Update(context.TODO(), remediationinstance)
+				Expect(err).NotTo(HaveOccurred())
+
+				reconciler.Client = interceptor.NewClient(reconciler.Client.(client.WithWatch), interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						return kerrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, obj.GetName(), field.ErrorList{
+							field.Invalid(field.NewPath("data").Key("key"), "val", "value is not allowed"),
+						})
+					},
+				})
+			})
+
+			It("should record the failure in a status condition", func() {
+				By("running a reconcile loop")
+				err := reconciler.reconcileRemediation(remediationinstance, logger)
+				Expect(err).ToNot(BeNil())
+				Expect(common.IsRetriable(err)).To(BeFalse())
+
+				By("updating the status of the remediation")
+				err = reconciler.reconcileRemediationStatus(remediationinstance, logger, err)
+				Expect(err).To(BeNil())
+
+				foundRem := &compv1alpha1.ComplianceRemediation{}
+				err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: remediationinstance.GetName()}, foundRem)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(foundRem.Status.ApplicationState).To(Equal(compv1alpha1.RemediationError))
+
+				cond := foundRem.Status.Conditions.GetCondition(compv1alpha1.RemediationApplyFailedCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.IsTrue()).To(BeTrue())
+				Expect(cond.Reason).To(BeEquivalentTo(metav1.StatusReasonInvalid))
+				Expect(cond.Message).To(ContainSubstring("value is not allowed"))
+				Expect(cond.Message).To(ContainSubstring("(fields: data[key])"))
+			})
+		})
+
 		Context("Apply all the related remediation", func() {
 			BeforeEach(func() {
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 
@@ -305,6 +306,11 @@ func (r *ReconcileComplianceScan) phasePendingHandler(instance *compv1alpha1.Com
 	instance.Status.Result = compv1alpha1.ResultNotAvailable
 	instance.Status.StartTimestamp = &metav1.Time{Time: time.Now()}
 	instance.Status.EndTimestamp = nil
+	// The platform scan reports these again, don't keep the ones of the
+	// previous run around meanwhile
+	instance.Status.FetchProgress = ""
+	instance.Status.FetchWarnings = ""
+	instance.Status.AbsentResourceTypeRules = nil
 	err := r.Client.Status().Update(context.TODO(), instance)
 	if err != nil {
 		logger.Error(err, "Cannot update the status")
@@ -658,9 +664,32 @@ func (r *ReconcileComplianceScan) phaseAggregatingHandler(h scanTypeHandler, log
 		return reconcile.Result{}, err
 	}
 	r.Metrics.IncComplianceScanStatus(instance.Name, instance.Status)
+	for reason, count := range parseFetchWarningCounts(instance.Status.FetchWarnings, logger) {
+		r.Metrics.AddComplianceScanFetchWarnings(instance.Name, reason, count)
+	}
 	return reconcile.Result{}, nil
 }
 
+// parseFetchWarningCounts parses the counts of the resources that the
+// platform scan couldn't fetch, which it records as "reason=count" pairs
+// separated by commas. Malformed pairs are skipped.
+func parseFetchWarningCounts(annotation string, logger logr.Logger) map[string]int {
+	counts := map[string]int{}
+	if annotation == "" {
+		return counts
+	}
+	for _, pair := range strings.Split(annotation, ",") {
+		reason, countStr, found := strings.Cut(pair, "=")
+		count, err := strconv.Atoi(countStr)
+		if !found || reason == "" || err != nil || count < 0 {
+			logger.Info("Skipping malformed fetch warning count", "count", pair)
+			continue
+		}
+		counts[reason] += count
+	}
+	return counts
+}
+
 func (r *ReconcileComplianceScan) phaseDoneHandler(h scanTypeHandler, instance *compv1alpha1.ComplianceScan, logger logr.Logger, doDelete bool) (reconcile.Result, error) {
 	var err error
 	logger.Info("Phase: Done")
@@ -553,3 +553,174 @@ var _ = Describe("Testing compliancescan controller phases", func() {
 		})
 	})
 })
+
+var _ = Describe("Testing the platform scan pod", func() {
+	var scan *compv1alpha1.ComplianceScan
+
+	BeforeEach(func() {
+		scan = &compv1alpha1.ComplianceScan{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: common.GetComplianceOperatorNamespace(),
+			},
+			Spec: compv1alpha1.ComplianceScanSpec{
+				ScanType:     compv1alpha1.ScanTypePlatform,
+				ContentImage: "quay.io/complianceascode/ocp4:latest",
+				Content:      "ssg-ocp4-ds.xml",
+			},
+		}
+	})
+
+	getLogCollector := func() corev1.Container {
+		r := &ReconcileComplianceScan{}
+		pod := r.newPlatformScanPod(scan, zapr.NewLogger(zap.NewNop()))
+		for _, container := range pod.Spec.Containers {
+			if container.Name == "log-collector" {
+				return container
+			}
+		}
+		Fail("the platform scan pod has no log-collector container")
+		return corev1.Container{}
+	}
+
+	It("doesn't store the fetched resources by default", func() {
+		collector := getLogCollector()
+		Expect(collector.Command).ToNot(ContainElement(HavePrefix("--fetched-resources-dir")))
+		for _, mount := range collector.VolumeMounts {
+			Expect(mount.Name).ToNot(Equal("fetch-results"))
+		}
+	})
+
+	It("stores the fetched resources when asked to", func() {
+		scan.Spec.RawResultStorage.StoreFetchedResources = true
+		collector := getLogCollector()
+		Expect(collector.Command).To(ContainElement("--fetched-resources-dir=" + PlatformScanDataRoot))
+		Expect(collector.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "fetch-results",
+			MountPath: PlatformScanDataRoot,
+			ReadOnly:  true,
+		}))
+	})
+
+	It("passes the extra resource paths to the resource collector", func() {
+		scan.Spec.ExtraResourcePaths = []string{"/api/v1/namespaces/openshift-config/configmaps/my-config"}
+		r := &ReconcileComplianceScan{}
+		pod := r.newPlatformScanPod(scan, zapr.NewLogger(zap.NewNop()))
+		var collectorCmd []string
+		for _, container := range pod.Spec.InitContainers {
+			if container.Name == "api-resource-collector" {
+				collectorCmd = container.Command
+			}
+		}
+		Expect(collectorCmd).To(ContainElement(
+			"--extra-resource-path=/api/v1/namespaces/openshift-config/configmaps/my-config"))
+	})
+
+	It("asks the resource collector to report its progress in the scan", func() {
+		r := &ReconcileComplianceScan{}
+		pod := r.newPlatformScanPod(scan, zapr.NewLogger(zap.NewNop()))
+		var collectorCmd []string
+		for _, container := range pod.Spec.InitContainers {
+			if container.Name == "api-resource-collector" {
+				collectorCmd = container.Command
+			}
+		}
+		Expect(collectorCmd).To(ContainElement("--scan-name=" + scan.Name))
+	})
+
+	It("asks the resource collector to clear stale resources when forced to re-fetch", func() {
+		getCollectorCmd := func() []string {
+			r := &ReconcileComplianceScan{}
+			pod := r.newPlatformScanPod(scan, zapr.NewLogger(zap.NewNop()))
+			for _, container := range pod.Spec.InitContainers {
+				if container.Name == "api-resource-collector" {
+					return container.Command
+				}
+			}
+			return nil
+		}
+		Expect(getCollectorCmd()).ToNot(ContainElement("--clear-resultdir"))
+
+		scan.Annotations = map[string]string{compv1alpha1.ComplianceScanForceRefetchAnnotation: ""}
+		Expect(getCollectorCmd()).To(ContainElement("--clear-resultdir"))
+	})
+})
+
+var _ = Describe("Setting the priority class of the scan workloads", func() {
+	var scan *compv1alpha1.ComplianceScan
+
+	BeforeEach(func() {
+		scan = &compv1alpha1.ComplianceScan{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: common.GetComplianceOperatorNamespace(),
+			},
+			Spec: compv1alpha1.ComplianceScanSpec{
+				ContentImage: "quay.io/complianceascode/ocp4:latest",
+				Content:      "ssg-ocp4-ds.xml",
+			},
+		}
+	})
+
+	getPriorityClassNames := func() []string {
+		r := &ReconcileComplianceScan{}
+		logger := zapr.NewLogger(zap.NewNop())
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+		return []string{
+			newScanPodForNode(scan, node, logger).Spec.PriorityClassName,
+			r.newPlatformScanPod(scan, logger).Spec.PriorityClassName,
+			r.newAggregatorPod(scan, logger).Spec.PriorityClassName,
+			resultServer(scan, map[string]string{}, 0, 0, logger).Spec.Template.Spec.PriorityClassName,
+		}
+	}
+
+	It("sets the priority class on the rendered pods", func() {
+		scan.Spec.PriorityClass = "compliance-high-priority"
+		Expect(getPriorityClassNames()).To(HaveEach("compliance-high-priority"))
+	})
+
+	It("omits the priority class when it's not set", func() {
+		Expect(getPriorityClassNames()).To(HaveEach(BeEmpty()))
+	})
+})
+
+var _ = Describe("Building the result server command", func() {
+	var scan *compv1alpha1.ComplianceScan
+
+	BeforeEach(func() {
+		scan = &compv1alpha1.ComplianceScan{}
+		scan.Spec.RawResultStorage.Rotation = 3
+	})
+
+	It("rotates by count by default", func() {
+		cmd := resultServerCommand(scan)
+		Expect(cmd).To(ContainElement("--rotation=3"))
+		Expect(cmd).NotTo(ContainElement(HavePrefix("--max-age")))
+	})
+
+	It("passes the maximum age when set", func() {
+		scan.Spec.RawResultStorage.MaxAge = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+		Expect(resultServerCommand(scan)).To(ContainElement("--max-age=720h0m0s"))
+	})
+})
+
+var _ = Describe("Parsing the fetch warning counts", func() {
+	logger := zapr.NewLogger(zap.NewNop())
+
+	It("parses the counts by reason", func() {
+		Expect(parseFetchWarningCounts("forbidden=2,notfound=1", logger)).To(Equal(map[string]int{
+			"forbidden": 2,
+			"notfound":  1,
+		}))
+	})
+
+	It("returns no counts without the annotation", func() {
+		Expect(parseFetchWarningCounts("", logger)).To(BeEmpty())
+	})
+
+	It("skips malformed counts", func() {
+		Expect(parseFetchWarningCounts("forbidden=2,timeout,nomatch=x,=3,notfound=-1", logger)).To(Equal(map[string]int{
+			"forbidden": 2,
+		}))
+	})
+})
@@ -202,17 +202,7 @@ func resultServer(scanInstance *compv1alpha1.ComplianceScan, labels map[string]s
 							Name:            "result-server",
 							Image:           utils.GetComponentImage(utils.OPERATOR),
 							ImagePullPolicy: corev1.PullAlways,
-							Command: []string{
-								"compliance-operator", "resultserver",
-								"--path=/reports/",
-								"--address=0.0.0.0",
-								fmt.Sprintf("--port=%d", ResultServerPort),
-								fmt.Sprintf("--scan-index=%d", scanInstance.Status.CurrentIndex),
-								fmt.Sprintf("--rotation=%d", scanInstance.Spec.RawResultStorage.Rotation),
-								"--tls-server-cert=/etc/pki/tls/tls.crt",
-								"--tls-server-key=/etc/pki/tls/tls.key",
-								"--tls-ca=/etc/pki/tls/ca.crt",
-							},
+							Command:         resultServerCommand(scanInstance),
 							SecurityContext: &corev1.SecurityContext{
 								AllowPrivilegeEscalation: &falseP,
 								ReadOnlyRootFilesystem:   &trueP,
@@ -257,6 +247,24 @@ func resultServer(scanInstance *compv1alpha1.ComplianceScan, labels map[string]s
 	}
 }
 
+func resultServerCommand(scanInstance *compv1alpha1.ComplianceScan) []string {
+	command := []string{
+		"compliance-operator", "resultserver",
+		"--path=/reports/",
+		"--address=0.0.0.0",
+		fmt.Sprintf("--port=%d", ResultServerPort),
+		fmt.Sprintf("--scan-index=%d", scanInstance.Status.CurrentIndex),
+		fmt.Sprintf("--rotation=%d", scanInstance.Spec.RawResultStorage.Rotation),
+		"--tls-server-cert=/etc/pki/tls/tls.crt",
+		"--tls-server-key=/etc/pki/tls/tls.key",
+		"--tls-ca=/etc/pki/tls/ca.crt",
+	}
+	if maxAge := scanInstance.Spec.RawResultStorage.MaxAge; maxAge != nil && maxAge.Duration > 0 {
+		command = append(command, fmt.Sprintf("--max-age=%s", maxAge.Duration))
+	}
+	return command
+}
+
 func resultServerService(scanInstance *compv1alpha1.ComplianceScan, labels map[string]string) *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
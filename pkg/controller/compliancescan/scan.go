@@ -370,6 +370,7 @@ func (r *ReconcileComplianceScan) newPlatformScanPod(scanInstance *compv1alpha1.
 		"--profile=" + scanInstance.Spec.Profile,
 		"--warnings-output-file=/reports/warning_output",
 		"--platform=" + os.Getenv("PLATFORM"),
+		"--scan-name=" + scanInstance.Name,
 	}
 	if scanInstance.Spec.TailoringConfigMap != nil {
 		// NOTE(jaosorior): Adding the tailoring volume is handled in the
@@ -381,10 +382,54 @@ func (r *ReconcileComplianceScan) newPlatformScanPod(scanInstance *compv1alpha1.
 	falseP := false
 	trueP := true
 
+	for _, resourcePath := range scanInstance.Spec.ExtraResourcePaths {
+		collectorCmd = append(collectorCmd, "--extra-resource-path="+resourcePath)
+	}
+
+	if _, ok := scanInstance.Annotations[compv1alpha1.ComplianceScanForceRefetchAnnotation]; ok {
+		collectorCmd = append(collectorCmd, "--clear-resultdir")
+	}
+
 	if scanInstance.Spec.Debug {
 		collectorCmd = append(collectorCmd, "--debug")
 	}
 
+	logCollectorCmd := []string{
+		"compliance-operator", "resultscollector",
+		"--arf-file=/reports/report-arf.xml",
+		"--results-file=/reports/report.xml",
+		"--exit-code-file=/reports/exit_code",
+		"--oscap-output-file=/reports/cmd_output",
+		"--warnings-output-file=/reports/warning_output",
+		"--config-map-name=" + cmName,
+		"--owner=" + scanInstance.Name,
+		"--namespace=" + scanInstance.Namespace,
+		"--resultserveruri=" + getResultServerURI(scanInstance),
+		"--tls-client-cert=/etc/pki/tls/tls.crt",
+		"--tls-client-key=/etc/pki/tls/tls.key",
+		"--tls-ca=/etc/pki/tls/ca.crt",
+	}
+	logCollectorVolumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "report-dir",
+			MountPath: "/reports",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "tls",
+			MountPath: "/etc/pki/tls",
+			ReadOnly:  true,
+		},
+	}
+	if scanInstance.Spec.RawResultStorage.StoreFetchedResources {
+		logCollectorCmd = append(logCollectorCmd, "--fetched-resources-dir="+PlatformScanDataRoot)
+		logCollectorVolumeMounts = append(logCollectorVolumeMounts, corev1.VolumeMount{
+			Name:      "fetch-results",
+			MountPath: PlatformScanDataRoot,
+			ReadOnly:  true,
+		})
+	}
+
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      podName,
@@ -492,23 +537,9 @@ func (r *ReconcileComplianceScan) newPlatformScanPod(scanInstance *compv1alpha1.
 			},
 			Containers: []corev1.Container{
 				{
-					Name:  "log-collector",
-					Image: utils.GetComponentImage(utils.OPERATOR),
-					Command: []string{
-						"compliance-operator", "resultscollector",
-						"--arf-file=/reports/report-arf.xml",
-						"--results-file=/reports/report.xml",
-						"--exit-code-file=/reports/exit_code",
-						"--oscap-output-file=/reports/cmd_output",
-						"--warnings-output-file=/reports/warning_output",
-						"--config-map-name=" + cmName,
-						"--owner=" + scanInstance.Name,
-						"--namespace=" + scanInstance.Namespace,
-						"--resultserveruri=" + getResultServerURI(scanInstance),
-						"--tls-client-cert=/etc/pki/tls/tls.crt",
-						"--tls-client-key=/etc/pki/tls/tls.key",
-						"--tls-ca=/etc/pki/tls/ca.crt",
-					},
+					Name:            "log-collector",
+					Image:           utils.GetComponentImage(utils.OPERATOR),
+					Command:         logCollectorCmd,
 					ImagePullPolicy: corev1.PullAlways,
 					SecurityContext: &corev1.SecurityContext{
 						AllowPrivilegeEscalation: &falseP,
@@ -527,18 +558,7 @@ func (r *ReconcileComplianceScan) newPlatformScanPod(scanInstance *compv1alpha1.
 							corev1.ResourceCPU:    resource.MustParse("100m"),
 						},
 					},
-					VolumeMounts: []corev1.VolumeMount{
-						{
-							Name:      "report-dir",
-							MountPath: "/reports",
-							ReadOnly:  true,
-						},
-						{
-							Name:      "tls",
-							MountPath: "/etc/pki/tls",
-							ReadOnly:  true,
-						},
-					},
+					VolumeMounts: logCollectorVolumeMounts,
 				},
 				{
 					Name:    OpenSCAPScanContainerName,
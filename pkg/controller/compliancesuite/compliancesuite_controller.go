@@ -371,6 +371,14 @@ func (r *ReconcileComplianceSuite) updateScanStatus(suite *compv1alpha1.Complian
 		suite.Status.SetConditionsProcessing()
 	}
 
+	if suite.Status.Phase == compv1alpha1.PhaseDone {
+		counts, err := r.getSeverityCounts(suite)
+		if err != nil {
+			return err
+		}
+		suite.Status.SeverityCounts = counts
+	}
+
 	logger.Info("Updating scan status", "ComplianceScan.Name", modScanStatus.Name, "ComplianceScan.Phase", modScanStatus.Phase)
 	if err := r.Client.Status().Update(context.TODO(), suite); err != nil {
 		return err
@@ -378,6 +386,63 @@ func (r *ReconcileComplianceSuite) updateScanStatus(suite *compv1alpha1.Complian
 	return r.setSuiteMetric(suite)
 }
 
+// getSeverityCounts lists all the check results that belong to the suite and
+// tallies them by severity
+func (r *ReconcileComplianceSuite) getSeverityCounts(suite *compv1alpha1.ComplianceSuite) ([]compv1alpha1.SeverityCount, error) {
+	checkList := &compv1alpha1.ComplianceCheckResultList{}
+	listOpts := client.ListOptions{
+		Namespace:     suite.Namespace,
+		LabelSelector: labels.SelectorFromSet(labels.Set{compv1alpha1.SuiteLabel: suite.Name}),
+	}
+	if err := r.Client.List(context.TODO(), checkList, &listOpts); err != nil {
+		return nil, err
+	}
+	return tallyCheckResultsBySeverity(checkList.Items), nil
+}
+
+// tallyCheckResultsBySeverity counts the passing, failing and erroring check
+// results per severity. Severities that have no such results are left out.
+// The result is sorted from the most to the least severe.
+func tallyCheckResultsBySeverity(checks []compv1alpha1.ComplianceCheckResult) []compv1alpha1.SeverityCount {
+	severities := []compv1alpha1.ComplianceCheckResultSeverity{
+		compv1alpha1.CheckResultSeverityHigh,
+		compv1alpha1.CheckResultSeverityMedium,
+		compv1alpha1.CheckResultSeverityLow,
+		compv1alpha1.CheckResultSeverityInfo,
+		compv1alpha1.CheckResultSeverityUnknown,
+	}
+
+	bySeverity := make(map[compv1alpha1.ComplianceCheckResultSeverity]*compv1alpha1.SeverityCount)
+	for _, sev := range severities {
+		bySeverity[sev] = &compv1alpha1.SeverityCount{Severity: sev}
+	}
+
+	for i := range checks {
+		count, ok := bySeverity[checks[i].Severity]
+		if !ok {
+			count = bySeverity[compv1alpha1.CheckResultSeverityUnknown]
+		}
+		switch checks[i].Status {
+		case compv1alpha1.CheckResultPass:
+			count.Pass++
+		case compv1alpha1.CheckResultFail:
+			count.Fail++
+		case compv1alpha1.CheckResultError:
+			count.Error++
+		}
+	}
+
+	counts := make([]compv1alpha1.SeverityCount, 0)
+	for _, sev := range severities {
+		count := bySeverity[sev]
+		if count.Pass == 0 && count.Fail == 0 && count.Error == 0 {
+			continue
+		}
+		counts = append(counts, *count)
+	}
+	return counts
+}
+
 func (r *ReconcileComplianceSuite) generateEventsForSuite(suite *compv1alpha1.ComplianceSuite, logger logr.Logger) {
 	logger.Info("Generating events for suite")
 
@@ -509,8 +574,25 @@ func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.Com
 		return reconcile.Result{}, nil
 	}
 
+	// Only apply the remediations the suite selected
+	selected := make(map[string]bool, len(remList.Items))
+	for i := range remList.Items {
+		matches, err := suite.Spec.AutoApplyRemediationSelector.Matches(&remList.Items[i])
+		if err != nil {
+			logger.Error(err, "Invalid remediation selector, not applying remediations")
+			r.Recorder.Event(suite, corev1.EventTypeWarning, "InvalidRemediationSelector", err.Error())
+			return reconcile.Result{}, nil
+		}
+		selected[remList.Items[i].Name] = matches
+	}
+
 	// Construct the list of the statuses
 	for _, rem := range remList.Items {
+		if !selected[rem.Name] {
+			logger.Info("Remediation not selected for applying", "ComplianceRemediation.Name", rem.Name)
+			continue
+		}
+
 		// get relevant scan
 		scan := &compv1alpha1.ComplianceScan{}
 		scanKey := types.NamespacedName{Name: rem.Labels[compv1alpha1.ComplianceScanLabel], Namespace: rem.Namespace}
@@ -539,6 +621,9 @@ func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.Com
 
 	// Check that all remediations have been applied yet. If not, requeue.
 	for _, rem := range postProcessRemList.Items {
+		if isSelected, ok := selected[rem.Name]; ok && !isSelected {
+			continue
+		}
 		if !rem.IsApplied() {
 			if rem.Status.ApplicationState == compv1alpha1.RemediationNeedsReview {
 				r.Recorder.Event(suite, corev1.EventTypeWarning, "CannotRemediate", "Remediation needs-review. Values not set"+" Remediation:"+rem.Name)
@@ -560,9 +645,7 @@ func (r *ReconcileComplianceSuite) reconcileRemediations(suite *compv1alpha1.Com
 		}
 		if pool.Spec.Paused {
 			logger.Info("Unpausing pool", "MachineConfigPool.Name", pool.Name)
-			poolCopy := pool.DeepCopy()
-			poolCopy.Spec.Paused = false
-			if err := r.Client.Update(context.TODO(), poolCopy); err != nil {
+			if _, err := utils.SetMcfgPoolPaused(context.TODO(), r.Client, pool.Name, false); err != nil {
 				logger.Error(err, "Could not unpause pool", "MachineConfigPool.Name", pool.Name)
 				return reconcile.Result{}, err
 			}
@@ -640,11 +723,11 @@ func (r *ReconcileComplianceSuite) applyMcfgRemediationAndPausePool(rem compv1al
 	// the remediation hasn't been applied
 	if !pool.Spec.Paused {
 		logger.Info("Pausing pool", "MachineConfigPool.Name", pool.Name)
-		pool.Spec.Paused = true
-		if err := r.Client.Update(context.TODO(), pool); err != nil {
+		if _, err := utils.SetMcfgPoolPaused(context.TODO(), r.Client, pool.Name, true); err != nil {
 			logger.Error(err, "Could not pause pool", "MachineConfigPool.Name", pool.Name)
 			return err
 		}
+		pool.Spec.Paused = true
 	}
 
 	remCopy.Spec.Apply = true
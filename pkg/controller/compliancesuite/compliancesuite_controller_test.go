@@ -250,6 +250,62 @@ var _ = Describe("ComplianceSuiteController", func() {
 			})
 		})
 
+		Context("With spec.AutoApplyRemediationSelector set", func() {
+			BeforeEach(suiteAndScansInDonePhase)
+
+			setSelector := func(selector *compv1alpha1.RemediationSelector) {
+				s := &compv1alpha1.ComplianceSuite{}
+				Expect(reconciler.Client.Get(ctx, types.NamespacedName{Name: suiteName, Namespace: namespace}, s)).To(Succeed())
+				s.Spec.AutoApplyRemediations = true
+				s.Spec.AutoApplyRemediationSelector = selector
+				Expect(reconciler.Client.Update(ctx, s)).To(Succeed())
+				suite = s
+			}
+
+			annotateRemediation := func(key, value string) {
+				rem := &compv1alpha1.ComplianceRemediation{}
+				Expect(reconciler.Client.Get(ctx, types.NamespacedName{Name: remediationName, Namespace: namespace}, rem)).To(Succeed())
+				rem.Annotations = map[string]string{key: value}
+				Expect(reconciler.Client.Update(ctx, rem)).To(Succeed())
+			}
+
+			It("Should apply remediations of a selected type", func() {
+				setSelector(&compv1alpha1.RemediationSelector{
+					Types: []compv1alpha1.RemediationType{compv1alpha1.ConfigurationRemediation},
+				})
+				Expect(reconcileAndGetRemediation().Spec.Apply).To(BeTrue())
+			})
+
+			It("Should not apply remediations of other types", func() {
+				setSelector(&compv1alpha1.RemediationSelector{
+					Types: []compv1alpha1.RemediationType{compv1alpha1.EnforcementRemediation},
+				})
+				result, err := reconciler.reconcileRemediations(suite, logger)
+				Expect(err).To(BeNil())
+				By("Not waiting for the unselected remediation to be applied")
+				Expect(result.Requeue).To(BeFalse())
+				reconcileShouldNotApplyTheRemediation()
+			})
+
+			It("Should filter remediations by their annotations", func() {
+				setSelector(&compv1alpha1.RemediationSelector{
+					AnnotationSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{{
+							Key:      "compliance.openshift.io/disruption",
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{"high"},
+						}},
+					},
+				})
+
+				annotateRemediation("compliance.openshift.io/disruption", "high")
+				reconcileShouldNotApplyTheRemediation()
+
+				annotateRemediation("compliance.openshift.io/disruption", "low")
+				Expect(reconcileAndGetRemediation().Spec.Apply).To(BeTrue())
+			})
+		})
+
 		Context("With apply-remediations annotation", func() {
 			BeforeEach(func() {
 				suite.Annotations = make(map[string]string, 2)
@@ -927,3 +983,36 @@ var _ = Describe("ComplianceSuiteController", func() {
 	})
 
 })
+
+var _ = Describe("Tallying check results by severity", func() {
+	newCheck := func(status compv1alpha1.ComplianceCheckStatus, severity compv1alpha1.ComplianceCheckResultSeverity) compv1alpha1.ComplianceCheckResult {
+		return compv1alpha1.ComplianceCheckResult{
+			Status:   status,
+			Severity: severity,
+		}
+	}
+
+	It("counts pass, fail and error results per severity", func() {
+		checks := []compv1alpha1.ComplianceCheckResult{
+			newCheck(compv1alpha1.CheckResultPass, compv1alpha1.CheckResultSeverityLow),
+			newCheck(compv1alpha1.CheckResultFail, compv1alpha1.CheckResultSeverityHigh),
+			newCheck(compv1alpha1.CheckResultFail, compv1alpha1.CheckResultSeverityHigh),
+			newCheck(compv1alpha1.CheckResultPass, compv1alpha1.CheckResultSeverityHigh),
+			newCheck(compv1alpha1.CheckResultError, compv1alpha1.CheckResultSeverityMedium),
+			newCheck(compv1alpha1.CheckResultManual, compv1alpha1.CheckResultSeverityInfo),
+			newCheck(compv1alpha1.CheckResultFail, ""),
+		}
+
+		counts := tallyCheckResultsBySeverity(checks)
+		Expect(counts).To(Equal([]compv1alpha1.SeverityCount{
+			{Severity: compv1alpha1.CheckResultSeverityHigh, Pass: 1, Fail: 2},
+			{Severity: compv1alpha1.CheckResultSeverityMedium, Error: 1},
+			{Severity: compv1alpha1.CheckResultSeverityLow, Pass: 1},
+			{Severity: compv1alpha1.CheckResultSeverityUnknown, Fail: 1},
+		}))
+	})
+
+	It("returns an empty list when there are no results", func() {
+		Expect(tallyCheckResultsBySeverity(nil)).To(BeEmpty())
+	})
+})
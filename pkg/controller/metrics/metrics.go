@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-logr/logr"
 	libgocrypto "github.com/openshift/library-go/pkg/crypto"
@@ -23,14 +24,18 @@ const (
 	metricNameComplianceScanError         = "compliance_scan_error_total"
 	metricNameComplianceRemediationStatus = "compliance_remediation_status_total"
 	metricNameComplianceStateGauge        = "compliance_state"
+	metricNameProfileBundleBacklog        = "profile_bundle_backlog"
+	metricNameProfileBundleOldestPending  = "profile_bundle_oldest_pending_seconds"
+	metricNameComplianceScanFetchWarnings = "compliance_scan_fetch_warnings_total"
 
-	metricLabelScanResult       = "result"
-	metricLabelScanName         = "name"
-	metricLabelSuiteName        = "name"
-	metricLabelScanPhase        = "phase"
-	metricLabelScanError        = "error"
-	metricLabelRemediationName  = "name"
-	metricLabelRemediationState = "state"
+	metricLabelScanResult         = "result"
+	metricLabelScanName           = "name"
+	metricLabelSuiteName          = "name"
+	metricLabelScanPhase          = "phase"
+	metricLabelScanError          = "error"
+	metricLabelRemediationName    = "name"
+	metricLabelRemediationState   = "state"
+	metricLabelFetchWarningReason = "reason"
 
 	HandlerPath                  = "/metrics-co"
 	ControllerMetricsServiceName = "metrics-co"
@@ -57,6 +62,9 @@ type ControllerMetrics struct {
 	metricComplianceScanStatus        *prometheus.CounterVec
 	metricComplianceRemediationStatus *prometheus.CounterVec
 	metricComplianceStateGauge        *prometheus.GaugeVec
+	metricProfileBundleBacklog        prometheus.Gauge
+	metricProfileBundleOldestPending  prometheus.Gauge
+	metricComplianceScanFetchWarnings *prometheus.CounterVec
 }
 
 func DefaultControllerMetrics() *ControllerMetrics {
@@ -102,6 +110,31 @@ func DefaultControllerMetrics() *ControllerMetrics {
 				metricLabelSuiteName,
 			},
 		),
+		metricProfileBundleBacklog: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:      metricNameProfileBundleBacklog,
+				Namespace: metricNamespace,
+				Help:      "A gauge for the number of ProfileBundles that aren't VALID yet",
+			},
+		),
+		metricProfileBundleOldestPending: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:      metricNameProfileBundleOldestPending,
+				Namespace: metricNamespace,
+				Help:      "A gauge for the number of seconds the oldest ProfileBundle that isn't VALID yet has been waiting. Set to 0 when there is none",
+			},
+		),
+		metricComplianceScanFetchWarnings: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:      metricNameComplianceScanFetchWarnings,
+				Namespace: metricNamespace,
+				Help:      "A counter for the total number of resources a ComplianceScan couldn't fetch, by reason",
+			},
+			[]string{
+				metricLabelScanName,
+				metricLabelFetchWarningReason,
+			},
+		),
 	}
 }
 
@@ -125,6 +158,9 @@ func (m *Metrics) Register() error {
 		metricNameComplianceScanStatus:        m.metrics.metricComplianceScanStatus,
 		metricNameComplianceRemediationStatus: m.metrics.metricComplianceRemediationStatus,
 		metricNameComplianceStateGauge:        m.metrics.metricComplianceStateGauge,
+		metricNameProfileBundleBacklog:        m.metrics.metricProfileBundleBacklog,
+		metricNameProfileBundleOldestPending:  m.metrics.metricProfileBundleOldestPending,
+		metricNameComplianceScanFetchWarnings: m.metrics.metricComplianceScanFetchWarnings,
 	} {
 		m.log.Info(fmt.Sprintf("Registering metric: %s", name))
 		if err := m.impl.Register(collector); err != nil {
@@ -170,6 +206,15 @@ func (m *Metrics) IncComplianceScanStatus(name string, status v1alpha1.Complianc
 	}
 }
 
+// AddComplianceScanFetchWarnings adds count to the number of resources the
+// scan couldn't fetch for the given reason
+func (m *Metrics) AddComplianceScanFetchWarnings(name, reason string, count int) {
+	m.metrics.metricComplianceScanFetchWarnings.With(prometheus.Labels{
+		metricLabelScanName:           name,
+		metricLabelFetchWarningReason: reason,
+	}).Add(float64(count))
+}
+
 // IncComplianceRemediationStatus increments the ComplianceRemediation status counter
 func (m *Metrics) IncComplianceRemediationStatus(name string, status v1alpha1.ComplianceRemediationStatus) {
 	m.metrics.metricComplianceRemediationStatus.With(prometheus.Labels{
@@ -197,3 +242,34 @@ func (m *Metrics) SetComplianceStateOutOfCompliance(name string) {
 func (m *Metrics) SetComplianceStateInCompliance(name string) {
 	m.metrics.metricComplianceStateGauge.WithLabelValues(name).Set(METRIC_STATE_COMPLIANT)
 }
+
+// SetProfileBundleBacklog sets the profile_bundle_backlog gauge to the number
+// of bundles that aren't VALID, and the profile_bundle_oldest_pending_seconds
+// gauge to how long the oldest of them has been waiting.
+func (m *Metrics) SetProfileBundleBacklog(bundles []v1alpha1.ProfileBundle) {
+	m.setProfileBundleBacklog(bundles, time.Now())
+}
+
+func (m *Metrics) setProfileBundleBacklog(bundles []v1alpha1.ProfileBundle, now time.Time) {
+	backlog := 0
+	var oldest time.Duration
+	for i := range bundles {
+		pb := &bundles[i]
+		if pb.Status.DataStreamStatus == v1alpha1.DataStreamValid {
+			continue
+		}
+		backlog++
+
+		// The Ready condition turns false when the bundle stops being VALID,
+		// bundles that don't have it yet were just created
+		since := pb.CreationTimestamp.Time
+		if cond := pb.Status.Conditions.GetCondition("Ready"); cond != nil && !cond.LastTransitionTime.IsZero() {
+			since = cond.LastTransitionTime.Time
+		}
+		if waiting := now.Sub(since); waiting > oldest {
+			oldest = waiting
+		}
+	}
+	m.metrics.metricProfileBundleBacklog.Set(float64(backlog))
+	m.metrics.metricProfileBundleOldestPending.Set(oldest.Seconds())
+}
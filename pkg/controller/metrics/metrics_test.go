@@ -19,10 +19,13 @@ package metrics
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
@@ -97,6 +100,25 @@ func TestComplianceOperatorMetrics(t *testing.T) {
 				require.Equal(t, 1, getMetricValue(ctr))
 			},
 		},
+		{ // fetch warnings by reason
+			when: func(m *Metrics) {
+				m.AddComplianceScanFetchWarnings("foo", "forbidden", 2)
+				m.AddComplianceScanFetchWarnings("foo", "forbidden", 1)
+				m.AddComplianceScanFetchWarnings("foo", "notfound", 1)
+			},
+			then: func(m *Metrics) {
+				ctr, err := m.metrics.metricComplianceScanFetchWarnings.GetMetricWith(prometheus.Labels{metricLabelScanName: "foo",
+					metricLabelFetchWarningReason: "forbidden",
+				})
+				require.Nil(t, err)
+				require.Equal(t, 3, getMetricValue(ctr))
+				ctr, err = m.metrics.metricComplianceScanFetchWarnings.GetMetricWith(prometheus.Labels{metricLabelScanName: "foo",
+					metricLabelFetchWarningReason: "notfound",
+				})
+				require.Nil(t, err)
+				require.Equal(t, 1, getMetricValue(ctr))
+			},
+		},
 		{ // gauge compliant
 			when: func(m *Metrics) {
 				m.SetComplianceStateInCompliance("cstate")
@@ -126,3 +148,63 @@ func TestComplianceOperatorMetrics(t *testing.T) {
 		tc.then(sut)
 	}
 }
+
+func TestProfileBundleBacklogMetrics(t *testing.T) {
+	t.Parallel()
+
+	getGaugeValue := func(col prometheus.Collector) float64 {
+		c := make(chan prometheus.Metric, 1)
+		col.Collect(c)
+		m := dto.Metric{}
+		err := (<-c).Write(&m)
+		require.Nil(t, err)
+		return *m.Gauge.Value
+	}
+
+	now := time.Now()
+	newBundle := func(status v1alpha1.DataStreamStatusType, created, transitioned time.Time) v1alpha1.ProfileBundle {
+		pb := v1alpha1.ProfileBundle{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: created}},
+		}
+		pb.Status.DataStreamStatus = status
+		if !transitioned.IsZero() {
+			pb.Status.Conditions = v1alpha1.Conditions{{
+				Type:               "Ready",
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.Time{Time: transitioned},
+			}}
+		}
+		return pb
+	}
+
+	for _, tc := range []struct {
+		bundles         []v1alpha1.ProfileBundle
+		expectedBacklog float64
+		expectedOldest  float64
+	}{
+		{ // no bundles
+			bundles: nil,
+		},
+		{ // only valid bundles
+			bundles: []v1alpha1.ProfileBundle{
+				newBundle(v1alpha1.DataStreamValid, now.Add(-time.Hour), time.Time{}),
+			},
+		},
+		{ // a mix of pending, invalid and valid bundles
+			bundles: []v1alpha1.ProfileBundle{
+				newBundle(v1alpha1.DataStreamValid, now.Add(-time.Hour), time.Time{}),
+				newBundle(v1alpha1.DataStreamPending, now.Add(-time.Hour), now.Add(-10*time.Minute)),
+				newBundle(v1alpha1.DataStreamInvalid, now.Add(-time.Hour), now.Add(-20*time.Minute)),
+				newBundle("", now.Add(-5*time.Minute), time.Time{}),
+			},
+			expectedBacklog: 3,
+			expectedOldest:  (20 * time.Minute).Seconds(),
+		},
+	} {
+		sut := NewMetrics(&metricsfakes.FakeImpl{})
+		sut.setProfileBundleBacklog(tc.bundles, now)
+
+		require.Equal(t, tc.expectedBacklog, getGaugeValue(sut.metrics.metricProfileBundleBacklog))
+		require.Equal(t, tc.expectedOldest, getGaugeValue(sut.metrics.metricProfileBundleOldestPending))
+	}
+}
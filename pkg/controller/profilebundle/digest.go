@@ -0,0 +1,113 @@
+package profilebundle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/openshift/library-go/pkg/image/reference"
+
+	compliancev1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// How often to check whether the tag of a tracked content image moved to a
+// different digest
+const digestCheckInterval = 1 * time.Hour
+
+// The manifest types we accept when resolving a tag. The digest of whichever
+// of them the registry returns is fine, as long as it's the same one every
+// time.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// imageDigestResolver resolves an image reference by tag to the digest the
+// tag currently points to
+type imageDigestResolver interface {
+	resolveDigest(ctx context.Context, image string) (string, error)
+}
+
+// registryDigestResolver asks the registry for the digest using the Docker
+// registry v2 API. Only anonymous access is supported.
+type registryDigestResolver struct {
+	client *http.Client
+}
+
+func newRegistryDigestResolver() *registryDigestResolver {
+	return &registryDigestResolver{
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (r *registryDigestResolver) resolveDigest(ctx context.Context, image string) (string, error) {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return "", err
+	}
+	ref = ref.DockerClientDefaults().AsV2()
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.Registry, ref.RepositoryName(), ref.Tag)
+
+	resp, err := r.do(ctx, http.MethodHead, manifestURL, manifestMediaTypes...)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status resolving %s: %s", image, resp.Status)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("the registry didn't return a digest for %s", image)
+	}
+	return digest, nil
+}
+
+// do sends a request to the registry. The caller must close the response
+// body.
+func (r *registryDigestResolver) do(ctx context.Context, method, requestURL string, accept ...string) (*http.Response, error) {
+	return utils.RegistryRequest(ctx, r.client, method, requestURL, accept...)
+}
+
+// tracksImageDigest returns whether the content image tag of the bundle is
+// to be pinned to a digest, and whether the image reference is a tag at all.
+// Besides the bundles asking for it, that's the case of the bundles whose
+// image signature is verified, so that they run the very image that was
+// verified.
+func tracksImageDigest(pb *compliancev1alpha1.ProfileBundle) bool {
+	if pb.Annotations[compliancev1alpha1.ProfileBundleTrackDigestAnnotation] != "true" && !verifiesImageSignature(pb) {
+		return false
+	}
+	ref, err := reference.Parse(pb.Spec.ContentImage)
+	if err != nil {
+		return false
+	}
+	return ref.ID == ""
+}
+
+// getPinnedImage resolves the tag of image and returns the same reference
+// pinned to the digest instead
+func getPinnedImage(ctx context.Context, resolver imageDigestResolver, image string) (string, error) {
+	digest, err := resolver.resolveDigest(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	return pinImage(image, digest)
+}
+
+// pinImage returns the image reference pinned to the digest instead of its
+// tag
+func pinImage(image, digest string) (string, error) {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return "", err
+	}
+	ref.Tag = ""
+	ref.ID = digest
+	return ref.Exact(), nil
+}
@@ -0,0 +1,65 @@
+package profilebundle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resolving content image digests", func() {
+	const digest = "sha256:0000000000000000000000000000000000000000000000000000000000000001"
+	var (
+		server   *httptest.Server
+		resolver *registryDigestResolver
+		image    string
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("scope") != "repository:complianceascode/ocp4:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token": "anonymous"}`)
+		})
+		mux.HandleFunc("/v2/complianceascode/ocp4/manifests/latest", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer anonymous" {
+				w.Header().Set("Www-Authenticate", fmt.Sprintf(
+					`Bearer realm="https://%s/token",service="test",scope="repository:complianceascode/ocp4:pull"`, r.Host))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", digest)
+		})
+		server = httptest.NewTLSServer(mux)
+		resolver = &registryDigestResolver{client: server.Client()}
+		image = strings.TrimPrefix(server.URL, "https://") + "/complianceascode/ocp4:latest"
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("resolves the tag after getting an anonymous token", func() {
+		resolved, err := resolver.resolveDigest(context.TODO(), image)
+		Expect(err).To(BeNil())
+		Expect(resolved).To(Equal(digest))
+	})
+
+	It("pins the image to the digest", func() {
+		pinned, err := getPinnedImage(context.TODO(), resolver, image)
+		Expect(err).To(BeNil())
+		Expect(pinned).To(Equal(strings.TrimSuffix(image, ":latest") + "@" + digest))
+	})
+
+	It("fails for unknown tags", func() {
+		_, err := resolver.resolveDigest(context.TODO(), strings.TrimSuffix(image, ":latest")+":missing")
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,160 @@
+package profilebundle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compliancev1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+// How many times a failed profileparser job is retried. Parse errors don't
+// go away on their own, so there's no point in retrying for long.
+var parserJobBackoffLimit int32 = 2
+
+// usesParserJob returns whether the bundle asks to be parsed by a Job that
+// goes away once parsing is done rather than by a Deployment
+func usesParserJob(pb *compliancev1alpha1.ProfileBundle) bool {
+	return pb.GetAnnotations()[compliancev1alpha1.ProfileBundleParserJobAnnotation] == "true"
+}
+
+// newParserJobForBundle returns a Job that runs the profileparser once. It
+// runs the same pod as the Deployment returned by newWorkloadForBundle, only
+// with the profileparser as its main container instead of the pauser.
+func (r *ReconcileProfileBundle) newParserJobForBundle(pb *compliancev1alpha1.ProfileBundle, image string) *batchv1.Job {
+	depl := r.newWorkloadForBundle(pb, image)
+	template := depl.Spec.Template
+	template.Spec.InitContainers = []corev1.Container{newContentContainer(pb, image)}
+	template.Spec.Containers = []corev1.Container{newProfileParserContainer(pb)}
+	template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	return &batchv1.Job{
+		ObjectMeta: depl.ObjectMeta,
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &parserJobBackoffLimit,
+			Template:     template,
+		},
+	}
+}
+
+// reconcileParserJob makes sure the bundle is parsed by an up-to-date Job and
+// tracks its completion
+func (r *ReconcileProfileBundle) reconcileParserJob(ctx context.Context, pb *compliancev1alpha1.ProfileBundle, image string, logger logr.Logger) (reconcile.Result, error) {
+	job := r.newParserJobForBundle(pb, image)
+	found := &batchv1.Job{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, found)
+	if errors.IsNotFound(err) {
+		verifiedImage, verified, verifyErr := r.verifyContentImage(ctx, pb, image, logger)
+		if !verified {
+			return reconcile.Result{}, verifyErr
+		}
+		job = r.newParserJobForBundle(pb, verifiedImage)
+		// The bundle might have been parsed by a Deployment before
+		depl := r.newWorkloadForBundle(pb, image)
+		if err := r.Client.Delete(ctx, depl); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		if pb.Status.DataStreamStatus != compliancev1alpha1.DataStreamPending {
+			if err := r.setBundlePending(pb, logger); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		logger.Info("Creating a new profileparser Job", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+		job.Annotations = map[string]string{workloadContentImageAnnotation: verifiedImage}
+		if err := r.Client.Create(ctx, job); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if jobNeedsUpdate(job, found) {
+		if _, verified, verifyErr := r.verifyContentImage(ctx, pb, image, logger); !verified {
+			return reconcile.Result{}, verifyErr
+		}
+		if err := r.setBundlePending(pb, logger); err != nil {
+			return reconcile.Result{}, err
+		}
+		// The pod template of a Job can't be changed, so the Job is
+		// re-created with the new one the next time around
+		logger.Info("Deleting outdated profileparser Job", "Job.Namespace", found.Namespace, "Job.Name", found.Name)
+		if err := r.Client.Delete(ctx, found, client.PropagationPolicy("Background")); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+	}
+
+	if found.Status.Succeeded > 0 {
+		// The profileparser updated the status of the bundle itself
+		logger.Info("The profileparser Job completed", "Job.Namespace", found.Namespace, "Job.Name", found.Name)
+		if r.digestResolver != nil && tracksImageDigest(pb) {
+			return reconcile.Result{RequeueAfter: digestCheckInterval}, nil
+		}
+		return reconcile.Result{}, nil
+	}
+
+	foundPods := &corev1.PodList{}
+	if err := r.Client.List(ctx, foundPods, client.InNamespace(found.Namespace), client.MatchingLabels(getWorkloadLabels(pb))); err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(foundPods.Items) > 0 {
+		relevantPod := utils.FindNewestPod(foundPods.Items)
+		if podStartupError(relevantPod) {
+			return reconcile.Result{}, r.setBundleInvalid(pb, compliancev1alpha1.ProfileBundleReasonImagePullFailed,
+				"The init container failed to start. Verify Status.ContentImage.", logger)
+		}
+		if podContentFileMissing(relevantPod) {
+			return reconcile.Result{}, r.setBundleInvalid(pb, compliancev1alpha1.ProfileBundleReasonContentFileMissing,
+				"The content file was not found in the image. Verify Spec.ContentFile.", logger)
+		}
+	}
+
+	if failed := jobFailedCondition(found); failed != nil {
+		// The profileparser usually explains why parsing failed itself
+		if pb.Status.DataStreamStatus == compliancev1alpha1.DataStreamInvalid {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, r.setBundleInvalid(pb, compliancev1alpha1.ProfileBundleReasonParseFailed,
+			fmt.Sprintf("The profileparser Job failed: %s", failed.Message), logger)
+	}
+
+	logger.Info("Waiting for the profileparser Job to complete", "Job.Namespace", found.Namespace, "Job.Name", found.Name)
+	return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+}
+
+// deleteParserJob removes the profileparser Job of the bundle, if any
+func (r *ReconcileProfileBundle) deleteParserJob(ctx context.Context, pb *compliancev1alpha1.ProfileBundle) error {
+	job := r.newParserJobForBundle(pb, "")
+	err := r.Client.Delete(ctx, job, client.PropagationPolicy("Background"))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// jobNeedsUpdate returns whether the found Job runs different images or
+// commands than the expected one
+func jobNeedsUpdate(expected, found *batchv1.Job) bool {
+	return containersNeedUpdate(expected.Spec.Template.Spec.InitContainers, found.Spec.Template.Spec.InitContainers) ||
+		containersNeedUpdate(expected.Spec.Template.Spec.Containers, found.Spec.Template.Spec.Containers)
+}
+
+// jobFailedCondition returns the Failed condition of the Job if it's true
+func jobFailedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		cond := &job.Status.Conditions[i]
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			return cond
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package profilebundle
 
 import (
 	"context"
+	goerrors "errors"
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -12,6 +13,7 @@ import (
 
 	"fmt"
 	"path"
+	"reflect"
 
 	compliancev1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
 	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
@@ -21,6 +23,7 @@ import (
 	"github.com/openshift/library-go/pkg/image/reference"
 	ocptrigger "github.com/openshift/library-go/pkg/image/trigger"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -28,8 +31,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -39,6 +44,17 @@ var log = logf.Log.WithName("profilebundlectrl")
 
 var oneReplica int32 = 1
 
+// How often to look for profileparser workloads whose bundle is gone
+const orphanedWorkloadSweepInterval = 30 * time.Minute
+
+// Records on the workload the content image the bundle was last parsed from,
+// so that image changes made by others can be told apart
+const workloadContentImageAnnotation = "compliance.openshift.io/content-image"
+
+// The content container exits with this code if the content file isn't in
+// the content image
+const contentFileMissingExitCode = 3
+
 func (r *ReconcileProfileBundle) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&compliancev1alpha1.ProfileBundle{}).
@@ -52,21 +68,33 @@ func Add(mgr manager.Manager, met *metrics.Metrics, si utils.CtlplaneSchedulingI
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, met *metrics.Metrics, si utils.CtlplaneSchedulingInfo) reconcile.Reconciler {
+func newReconciler(mgr manager.Manager, met *metrics.Metrics, si utils.CtlplaneSchedulingInfo) *ReconcileProfileBundle {
 	return &ReconcileProfileBundle{
-		Client:         mgr.GetClient(),
-		Scheme:         mgr.GetScheme(),
-		reader:         mgr.GetAPIReader(),
-		Metrics:        met,
-		schedulingInfo: si,
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		reader:            mgr.GetAPIReader(),
+		Metrics:           met,
+		schedulingInfo:    si,
+		digestResolver:    newRegistryDigestResolver(),
+		signatureVerifier: newCosignSignatureVerifier(),
 	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r *ReconcileProfileBundle) error {
+	// Orphaned workloads have no bundle left to trigger a reconcile, so
+	// they're swept on their own
+	if err := mgr.Add(manager.RunnableFunc(r.sweepOrphanedWorkloads)); err != nil {
+		return err
+	}
+	wlMapper := &workloadMapper{mgr.GetClient()}
+	keysMapper := &signatureKeysMapper{mgr.GetClient()}
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("profilebundle-controller").
 		For(&compliancev1alpha1.ProfileBundle{}).
+		Watches(&appsv1.Deployment{}, handler.EnqueueRequestsFromMapFunc(wlMapper.Map)).
+		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(wlMapper.Map)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(keysMapper.Map)).
 		Complete(r)
 }
 
@@ -85,6 +113,10 @@ type ReconcileProfileBundle struct {
 	// helps us schedule platform scans on the nodes labeled for the
 	// compliance operator's control plane
 	schedulingInfo utils.CtlplaneSchedulingInfo
+	// resolves content image tags to digests for the bundles that track them
+	digestResolver imageDigestResolver
+	// verifies the content image signature for the bundles that ask for it
+	signatureVerifier imageSignatureVerifier
 }
 
 // Reconcile reads that state of the cluster for a ProfileBundle object and makes changes based on the state read
@@ -95,6 +127,8 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling ProfileBundle")
 
+	r.updateBacklogMetrics(reqLogger)
+
 	// Fetch the ProfileBundle instance
 	instance := &compliancev1alpha1.ProfileBundle{}
 	err := r.Client.Get(context.TODO(), request.NamespacedName, instance)
@@ -113,10 +147,11 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
 		// The object is not being deleted, so if it does not have our finalizer,
 		// then lets add the finalizer and update the object. This is equivalent
-		// registering our finalizer.
-		if !common.ContainsFinalizer(instance.ObjectMeta.Finalizers, compliancev1alpha1.ProfileBundleFinalizer) {
+		// registering our finalizer. Any duplicate finalizers, e.g. from
+		// concurrent reconciles, are dropped at the same time.
+		if finalizers, changed := common.EnsureFinalizer(instance.ObjectMeta.Finalizers, compliancev1alpha1.ProfileBundleFinalizer); changed {
 			pb := instance.DeepCopy()
-			pb.ObjectMeta.Finalizers = append(pb.ObjectMeta.Finalizers, compliancev1alpha1.ProfileBundleFinalizer)
+			pb.ObjectMeta.Finalizers = finalizers
 			if err := r.Client.Update(context.TODO(), pb); err != nil {
 				return reconcile.Result{}, err
 			}
@@ -147,7 +182,13 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 	}
 
 	annotations := map[string]string{}
-	isISTag, isTagImageRef, err := r.pointsToISTag(instance.Spec.ContentImage)
+	isISTag := false
+	isTagImageRef := ""
+	if !instance.UsesArtifact() {
+		// Artifacts are pulled straight from a registry, they can't be
+		// image stream tags
+		isISTag, isTagImageRef, err = r.pointsToISTag(instance.Spec.ContentImage)
+	}
 	if err != nil {
 		if common.IsRetriable(err) {
 			return reconcile.Result{}, err
@@ -156,7 +197,7 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 		pbCopy := instance.DeepCopy()
 		pbCopy.Status.DataStreamStatus = compliancev1alpha1.DataStreamInvalid
 		pbCopy.Status.ErrorMessage = err.Error()
-		pbCopy.Status.SetConditionInvalid()
+		pbCopy.Status.SetConditionInvalid(compliancev1alpha1.ProfileBundleReasonInvalidImageReference, pbCopy.Status.ErrorMessage)
 		err = r.Client.Status().Update(context.TODO(), pbCopy)
 		if err != nil {
 			reqLogger.Error(err, "Couldn't update ProfileBundle status")
@@ -172,6 +213,24 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 		ref, _ := reference.Parse(instance.Spec.ContentImage)
 		annotations = getISTagAnnotation(ref.NameString(), getISTagNamespace(ref))
 		effectiveImage = isTagImageRef
+	} else if r.digestResolver != nil && tracksImageDigest(instance) {
+		// Pinning the workload to the digest makes a digest change
+		// under the same tag show up as an image change below.
+		pinnedImage, err := getPinnedImage(ctx, r.digestResolver, instance.Spec.ContentImage)
+		if err != nil && verifiesImageSignature(instance) {
+			// The tag might not point to the image that gets verified
+			return reconcile.Result{}, err
+		} else if err != nil {
+			reqLogger.Error(err, "Couldn't resolve the content image digest, using the tag")
+		} else {
+			effectiveImage = pinnedImage
+		}
+	}
+
+	// The image trigger of an ImageStreamTag can't update the pod template
+	// of a Job, so those bundles are always parsed by a Deployment
+	if usesParserJob(instance) && !isISTag {
+		return r.reconcileParserJob(ctx, instance, effectiveImage, reqLogger)
 	}
 
 	// Define a new Pod object
@@ -180,8 +239,18 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 	found := &appsv1.Deployment{}
 	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: depl.Name, Namespace: depl.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
+		verifiedImage, verified, verifyErr := r.verifyContentImage(ctx, instance, effectiveImage, reqLogger)
+		if !verified {
+			return reconcile.Result{}, verifyErr
+		}
+		depl = r.newWorkloadForBundle(instance, verifiedImage)
+		// The bundle might have been parsed by a Job before
+		if err := r.deleteParserJob(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
 		reqLogger.Info("Creating a new Workload", "Deployment.Namespace", depl.Namespace, "Deployment.Name", depl.Name)
 		depl.Annotations = annotations
+		depl.Annotations[workloadContentImageAnnotation] = getContentContainerImage(depl)
 		err = r.Client.Create(context.TODO(), depl)
 		if err != nil {
 			return reconcile.Result{}, err
@@ -192,14 +261,13 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{}, err
 	}
 
-	if workloadNeedsUpdate(effectiveImage, found) {
-		pbCopy := instance.DeepCopy()
-		pbCopy.Status.DataStreamStatus = compliancev1alpha1.DataStreamPending
-		pbCopy.Status.ErrorMessage = ""
-		pbCopy.Status.SetConditionPending()
-		err = r.Client.Status().Update(context.TODO(), pbCopy)
-		if err != nil {
-			reqLogger.Error(err, "Couldn't update ProfileBundle status")
+	if workloadNeedsUpdate(depl, found) {
+		verifiedImage, verified, verifyErr := r.verifyContentImage(ctx, instance, effectiveImage, reqLogger)
+		if !verified {
+			return reconcile.Result{}, verifyErr
+		}
+		depl = r.newWorkloadForBundle(instance, verifiedImage)
+		if err := r.setBundlePending(instance, reqLogger); err != nil {
 			return reconcile.Result{}, err
 		}
 
@@ -207,9 +275,13 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 		updatedDepl := found.DeepCopy()
 		updatedDepl.Spec.Template = depl.Spec.Template
 		// Copy annotations if needed
+		if updatedDepl.Annotations == nil {
+			updatedDepl.Annotations = map[string]string{}
+		}
 		for key, val := range annotations {
 			updatedDepl.Annotations[key] = val
 		}
+		updatedDepl.Annotations[workloadContentImageAnnotation] = getContentContainerImage(depl)
 		reqLogger.Info("Updating Workload", "Deployment.Namespace", depl.Namespace, "Deployment.Name", depl.Name)
 		err = r.Client.Update(context.TODO(), updatedDepl)
 		if err != nil {
@@ -219,6 +291,41 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
 	}
 
+	// The image trigger of an ImageStreamTag updates the workload on its
+	// own, which rolls out a new parser pod. The bundle needs to go back to
+	// pending until that pod parses the new content.
+	if parsedImage, ok := found.Annotations[workloadContentImageAnnotation]; !ok || parsedImage != getContentContainerImage(found) {
+		if ok {
+			reqLogger.Info("The content image of the workload changed", "Old", parsedImage, "New", getContentContainerImage(found))
+			if _, verified, verifyErr := r.verifyContentImage(ctx, instance, getContentContainerImage(found), reqLogger); !verified {
+				if verifyErr != nil {
+					return reconcile.Result{}, verifyErr
+				}
+				// The new image is already rolling out, so the workload
+				// goes away before it gets to parse unverified content
+				reqLogger.Info("Deleting the Workload of the unverified content image", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
+				if err := r.Client.Delete(ctx, found); err != nil && !errors.IsNotFound(err) {
+					return reconcile.Result{}, err
+				}
+				return reconcile.Result{}, nil
+			}
+			if err := r.setBundlePending(instance, reqLogger); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+
+		updatedDepl := found.DeepCopy()
+		if updatedDepl.Annotations == nil {
+			updatedDepl.Annotations = map[string]string{}
+		}
+		updatedDepl.Annotations[workloadContentImageAnnotation] = getContentContainerImage(found)
+		err = r.Client.Update(context.TODO(), updatedDepl)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+	}
+
 	labels := getWorkloadLabels(instance)
 	foundPods := &corev1.PodList{}
 	err = r.Client.List(context.TODO(), foundPods, client.MatchingLabels(labels))
@@ -235,18 +342,15 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 	relevantPod := utils.FindNewestPod(foundPods.Items)
 
 	if podStartupError(relevantPod) {
-		// report to status
-		pbCopy := instance.DeepCopy()
-		pbCopy.Status.DataStreamStatus = compliancev1alpha1.DataStreamInvalid
-		pbCopy.Status.ErrorMessage = "The init container failed to start. Verify Status.ContentImage."
-		pbCopy.Status.SetConditionInvalid()
-		err = r.Client.Status().Update(context.TODO(), pbCopy)
-		if err != nil {
-			reqLogger.Error(err, "Couldn't update ProfileBundle status")
-			return reconcile.Result{}, err
-		}
 		// this was a fatal error, don't requeue
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, r.setBundleInvalid(instance, compliancev1alpha1.ProfileBundleReasonImagePullFailed,
+			"The init container failed to start. Verify Status.ContentImage.", reqLogger)
+	}
+
+	if podContentFileMissing(relevantPod) {
+		// this was a fatal error, don't requeue
+		return reconcile.Result{}, r.setBundleInvalid(instance, compliancev1alpha1.ProfileBundleReasonContentFileMissing,
+			"The content file was not found in the image. Verify Spec.ContentFile.", reqLogger)
 	}
 
 	// Pod already exists and its init container at least ran - don't requeue
@@ -264,9 +368,85 @@ func (r *ReconcileProfileBundle) Reconcile(ctx context.Context, request reconcil
 			return reconcile.Result{}, err
 		}
 	}
+
+	if r.digestResolver != nil && tracksImageDigest(instance) {
+		// Come back later to check whether the tag moved
+		return reconcile.Result{RequeueAfter: digestCheckInterval}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
+// verifyContentImage checks the signature of the content image if the bundle
+// asks for it, before the image is used for parsing. The image to use is
+// returned pinned to the digest that was verified, so that moving the tag
+// afterwards can't get unverified content parsed. If the signature can't be
+// verified, the bundle is marked as invalid and false is returned. Errors that
+// might go away on their own are returned so that the request is retried.
+func (r *ReconcileProfileBundle) verifyContentImage(ctx context.Context, pb *compliancev1alpha1.ProfileBundle, image string, logger logr.Logger) (string, bool, error) {
+	if !verifiesImageSignature(pb) || r.signatureVerifier == nil {
+		return image, true, nil
+	}
+	keysConfigMap := pb.Annotations[compliancev1alpha1.ProfileBundleSignatureKeysAnnotation]
+
+	var verifyErr error
+	var digest string
+	keysCM := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: keysConfigMap, Namespace: pb.Namespace}, keysCM)
+	if errors.IsNotFound(err) {
+		verifyErr = fmt.Errorf("the ConfigMap %s with the signature keys was not found", keysConfigMap)
+	} else if err != nil {
+		return "", false, err
+	} else if keys, parseErr := parsePublicKeys(keysCM.Data); parseErr != nil {
+		verifyErr = fmt.Errorf("the ConfigMap %s has invalid signature keys: %w", keysConfigMap, parseErr)
+	} else if digest, err = r.signatureVerifier.verifyImage(ctx, image, keys); err != nil {
+		var sigErr *signatureVerificationError
+		if !goerrors.As(err, &sigErr) {
+			return "", false, err
+		}
+		verifyErr = err
+	}
+
+	if verifyErr == nil {
+		verifiedImage, err := pinImage(image, digest)
+		if err != nil {
+			return "", false, err
+		}
+		logger.Info("Verified the content image signature", "image", verifiedImage)
+		return verifiedImage, true, nil
+	}
+
+	logger.Info("Couldn't verify the content image signature", "image", image, "reason", verifyErr.Error())
+	pbCopy := pb.DeepCopy()
+	pbCopy.Status.DataStreamStatus = compliancev1alpha1.DataStreamInvalid
+	pbCopy.Status.ErrorMessage = fmt.Sprintf("Content image signature verification failed: %s", verifyErr)
+	pbCopy.Status.SetConditionInvalid(compliancev1alpha1.ProfileBundleReasonSignatureVerificationFailed, pbCopy.Status.ErrorMessage)
+	if err := r.Client.Status().Update(ctx, pbCopy); err != nil {
+		logger.Error(err, "Couldn't update ProfileBundle status")
+		return "", false, err
+	}
+	// Don't retry, the bundle or the keys need to change first. The keys
+	// ConfigMap is watched, so fixing it gets the image verified again.
+	return "", false, nil
+}
+
+// verifiesImageSignature returns whether the bundle asked for the signature
+// of its content image to be verified
+func verifiesImageSignature(pb *compliancev1alpha1.ProfileBundle) bool {
+	_, ok := pb.Annotations[compliancev1alpha1.ProfileBundleSignatureKeysAnnotation]
+	return ok
+}
+
+// updateBacklogMetrics refreshes the metrics about the bundles that are
+// waiting to be parsed. Failing to do so isn't a reason to fail the reconcile.
+func (r *ReconcileProfileBundle) updateBacklogMetrics(logger logr.Logger) {
+	bundles := compliancev1alpha1.ProfileBundleList{}
+	if err := r.Client.List(context.TODO(), &bundles); err != nil {
+		logger.Error(err, "Couldn't list ProfileBundles to update the backlog metrics")
+		return
+	}
+	r.Metrics.SetProfileBundleBacklog(bundles.Items)
+}
+
 func (r *ReconcileProfileBundle) profileBundleDeleteHandler(pb *compliancev1alpha1.ProfileBundle, logger logr.Logger) error {
 	logger.Info("The ProfileBundle is being deleted")
 	pod := r.newWorkloadForBundle(pb, "")
@@ -275,6 +455,9 @@ func (r *ReconcileProfileBundle) profileBundleDeleteHandler(pb *compliancev1alph
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
+	if err := r.deleteParserJob(context.TODO(), pb); err != nil {
+		return err
+	}
 
 	pbCopy := pb.DeepCopy()
 	// remove our finalizer from the list and update it.
@@ -361,6 +544,91 @@ func (r *ReconcileProfileBundle) deleteNonNamespacedWorkload(pb *compliancev1alp
 	return nil
 }
 
+// sweepOrphanedWorkloads deletes the orphaned profileparser workloads when
+// the manager starts and then every orphanedWorkloadSweepInterval, until the
+// context is cancelled
+func (r *ReconcileProfileBundle) sweepOrphanedWorkloads(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		// Errors were logged already, the next sweep retries
+		_ = r.deleteOrphanedWorkloads(ctx, log)
+	}, orphanedWorkloadSweepInterval)
+	return nil
+}
+
+// deleteOrphanedWorkloads removes profileparser deployments and jobs in the
+// operator's namespace whose ProfileBundle no longer exists. These are
+// typically left behind by renames or by upgrades from older versions of the
+// operator.
+func (r *ReconcileProfileBundle) deleteOrphanedWorkloads(ctx context.Context, logger logr.Logger) error {
+	workloadLabels := client.MatchingLabels{"workload": "profileparser"}
+	deployments := &appsv1.DeploymentList{}
+	err := r.Client.List(ctx, deployments, client.InNamespace(common.GetComplianceOperatorNamespace()), workloadLabels)
+	if err != nil {
+		logger.Error(err, "Couldn't list profileparser deployments")
+		return err
+	}
+	jobs := &batchv1.JobList{}
+	err = r.Client.List(ctx, jobs, client.InNamespace(common.GetComplianceOperatorNamespace()), workloadLabels)
+	if err != nil {
+		logger.Error(err, "Couldn't list profileparser jobs")
+		return err
+	}
+	if len(deployments.Items) == 0 && len(jobs.Items) == 0 {
+		return nil
+	}
+
+	bundles := &compliancev1alpha1.ProfileBundleList{}
+	if err := r.Client.List(ctx, bundles); err != nil {
+		logger.Error(err, "Couldn't list ProfileBundles")
+		return err
+	}
+
+	for i := range deployments.Items {
+		depl := &deployments.Items[i]
+		if workloadHasOwningBundle(depl, bundles.Items) {
+			continue
+		}
+
+		logger.Info("Deleting orphaned profileparser deployment", "Deployment.Name", depl.Name)
+		err = r.Client.Delete(ctx, depl)
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Couldn't delete orphaned deployment", "Deployment.Name", depl.Name)
+			return err
+		}
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if workloadHasOwningBundle(job, bundles.Items) {
+			continue
+		}
+
+		logger.Info("Deleting orphaned profileparser job", "Job.Name", job.Name)
+		err = r.Client.Delete(ctx, job, client.PropagationPolicy("Background"))
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Couldn't delete orphaned job", "Job.Name", job.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+// workloadHasOwningBundle returns whether any of the given ProfileBundles
+// would render the given deployment or job, either with the current or the
+// legacy non-namespaced name.
+func workloadHasOwningBundle(workload metav1.Object, bundles []compliancev1alpha1.ProfileBundle) bool {
+	for i := range bundles {
+		pb := &bundles[i]
+		if !hasWorkloadLabels(workload, pb) {
+			continue
+		}
+		if workload.GetName() == getWorkloadName(pb) || workload.GetName() == pb.Name+"-pp" {
+			return true
+		}
+	}
+	return false
+}
+
 // Gets the namespace for the image stream tag. If none is given, it'll use the operator's namespace
 func getISTagNamespace(ref reference.DockerImageReference) string {
 	if ref.Namespace != "" {
@@ -369,6 +637,10 @@ func getISTagNamespace(ref reference.DockerImageReference) string {
 	return common.GetComplianceOperatorNamespace()
 }
 
+func getWorkloadName(pb *compliancev1alpha1.ProfileBundle) string {
+	return pb.Name + "-" + pb.Namespace + "-pp"
+}
+
 func getWorkloadLabels(pb *compliancev1alpha1.ProfileBundle) map[string]string {
 	return map[string]string{
 		"profile-bundle": pb.Name,
@@ -404,7 +676,7 @@ func (r *ReconcileProfileBundle) newWorkloadForBundle(pb *compliancev1alpha1.Pro
 	labels := getWorkloadLabels(pb)
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      pb.Name + "-" + pb.Namespace + "-pp",
+			Name:      getWorkloadName(pb),
 			Namespace: common.GetComplianceOperatorNamespace(),
 			Labels:    labels,
 		},
@@ -427,79 +699,8 @@ func (r *ReconcileProfileBundle) newWorkloadForBundle(pb *compliancev1alpha1.Pro
 						RunAsNonRoot: &trueP,
 					},
 					InitContainers: []corev1.Container{
-						{
-							Name:  "content-container",
-							Image: image,
-							Command: []string{
-								"sh",
-								"-c",
-								fmt.Sprintf("cp %s /content | /bin/true", path.Join("/", pb.Spec.ContentFile)),
-							},
-							ImagePullPolicy: corev1.PullAlways,
-							SecurityContext: &corev1.SecurityContext{
-								AllowPrivilegeEscalation: &falseP,
-								ReadOnlyRootFilesystem:   &trueP,
-								RunAsNonRoot:             &trueP,
-								Capabilities: &corev1.Capabilities{
-									Drop: []corev1.Capability{"ALL"},
-								},
-							},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceMemory: resource.MustParse("10Mi"),
-									corev1.ResourceCPU:    resource.MustParse("10m"),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceMemory: resource.MustParse("50Mi"),
-									corev1.ResourceCPU:    resource.MustParse("50m"),
-								},
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "content-dir",
-									MountPath: "/content",
-								},
-							},
-						},
-						{
-							Name:  "profileparser",
-							Image: utils.GetComponentImage(utils.OPERATOR),
-							SecurityContext: &corev1.SecurityContext{
-								AllowPrivilegeEscalation: &falseP,
-								ReadOnlyRootFilesystem:   &trueP,
-								RunAsNonRoot:             &trueP,
-								Capabilities: &corev1.Capabilities{
-									Drop: []corev1.Capability{"ALL"},
-								},
-							},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceMemory: resource.MustParse("20Mi"),
-									corev1.ResourceCPU:    resource.MustParse("10m"),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceMemory: resource.MustParse("200Mi"),
-									corev1.ResourceCPU:    resource.MustParse("100m"),
-								},
-							},
-							Command: []string{
-								"compliance-operator", "profileparser",
-								"--name", pb.Name,
-								"--namespace", pb.Namespace,
-								"--ds-path", path.Join("/content", pb.Spec.ContentFile),
-							},
-							Env: []corev1.EnvVar{
-								corev1.EnvVar{Name: "PLATFORM", Value: utils.GetPlatform()},
-								corev1.EnvVar{Name: "CONTROL_PLANE_TOPOLOGY", Value: utils.GetControlPlaneTopology()},
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "content-dir",
-									MountPath: "/content",
-									ReadOnly:  true,
-								},
-							},
-						},
+						newContentContainer(pb, image),
+						newProfileParserContainer(pb),
 					},
 					Containers: []corev1.Container{
 						{
@@ -551,6 +752,121 @@ func (r *ReconcileProfileBundle) newWorkloadForBundle(pb *compliancev1alpha1.Pro
 	}
 }
 
+// newProfileParserContainer returns the container that parses the content
+// file of the bundle
+func newProfileParserContainer(pb *compliancev1alpha1.ProfileBundle) corev1.Container {
+	falseP := false
+	trueP := true
+	return corev1.Container{
+		Name:  "profileparser",
+		Image: utils.GetComponentImage(utils.OPERATOR),
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &falseP,
+			ReadOnlyRootFilesystem:   &trueP,
+			RunAsNonRoot:             &trueP,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("20Mi"),
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("200Mi"),
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+			},
+		},
+		Command: newProfileParserCommand(pb),
+		Env: []corev1.EnvVar{
+			corev1.EnvVar{Name: "PLATFORM", Value: utils.GetPlatform()},
+			corev1.EnvVar{Name: "CONTROL_PLANE_TOPOLOGY", Value: utils.GetControlPlaneTopology()},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "content-dir",
+				MountPath: "/content",
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+// newContentContainer returns the init container that puts the content file
+// of the bundle into the content volume, either by copying it out of the
+// content image or by downloading it from an OCI artifact.
+func newContentContainer(pb *compliancev1alpha1.ProfileBundle, image string) corev1.Container {
+	falseP := false
+	trueP := true
+	container := corev1.Container{
+		Name:  "content-container",
+		Image: image,
+		Command: []string{
+			"sh",
+			"-c",
+			fmt.Sprintf("test -f %[1]s || { echo 'content file %[1]s not found in image'; exit %[2]d; }; cp %[1]s /content",
+				path.Join("/", pb.Spec.ContentFile), contentFileMissingExitCode),
+		},
+		ImagePullPolicy: getContentImagePullPolicy(pb, image),
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &falseP,
+			ReadOnlyRootFilesystem:   &trueP,
+			RunAsNonRoot:             &trueP,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("10Mi"),
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("50Mi"),
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "content-dir",
+				MountPath: "/content",
+			},
+		},
+	}
+
+	if pb.UsesArtifact() {
+		// The artifact isn't runnable, so the operator image downloads it
+		container.Image = utils.GetComponentImage(utils.OPERATOR)
+		container.ImagePullPolicy = ""
+		container.Command = []string{
+			"compliance-operator", "fetch-artifact",
+			"--artifact", image,
+			"--file", pb.Spec.ContentFile,
+			"--output", path.Join("/content", pb.Spec.ContentFile),
+		}
+		container.Resources.Requests[corev1.ResourceMemory] = resource.MustParse("20Mi")
+		container.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("100Mi")
+		container.Resources.Limits[corev1.ResourceCPU] = resource.MustParse("100m")
+	}
+
+	return container
+}
+
+// getContentImagePullPolicy returns the pull policy the bundle asks for, or
+// defaults it based on whether the image is pinned to a digest. Digests
+// always point to the same content, so there's no need to pull them again.
+func getContentImagePullPolicy(pb *compliancev1alpha1.ProfileBundle, image string) corev1.PullPolicy {
+	if pb.Spec.ContentImagePullPolicy != "" {
+		return pb.Spec.ContentImagePullPolicy
+	}
+	ref, err := reference.Parse(image)
+	if err != nil || ref.ID == "" {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
 // podStartupError returns false if for some reason the pod couldn't even
 // run. If there's more conditions in the function in the future, let's
 // split it
@@ -577,25 +893,104 @@ func podStartupError(pod *corev1.Pod) bool {
 	return false
 }
 
-func workloadNeedsUpdate(image string, depl *appsv1.Deployment) bool {
-	initContainers := depl.Spec.Template.Spec.InitContainers
-	if len(initContainers) != 2 {
-		// For some weird reason we don't have the amount of init containers we expect.
-		return true
+// podContentFileMissing returns whether the content container of the pod
+// exited because the content file wasn't found in the content image. As the
+// pod is restarted, the failure might be recorded as the last state of the
+// container.
+func podContentFileMissing(pod *corev1.Pod) bool {
+	for _, initStatus := range pod.Status.InitContainerStatuses {
+		if initStatus.Name != "content-container" {
+			continue
+		}
+		for _, state := range []corev1.ContainerState{initStatus.State, initStatus.LastTerminationState} {
+			if state.Terminated != nil && state.Terminated.ExitCode == contentFileMissingExitCode {
+				return true
+			}
+		}
 	}
+	return false
+}
 
-	isSameContentImage := false
-	isSaneProfileparserImage := false
+// newProfileParserCommand returns the command that runs the profileparser
+// for the given bundle
+func newProfileParserCommand(pb *compliancev1alpha1.ProfileBundle) []string {
+	cmd := []string{
+		"compliance-operator", "profileparser",
+		"--name", pb.Name,
+		"--namespace", pb.Namespace,
+		"--ds-path", path.Join("/content", pb.Spec.ContentFile),
+	}
+	if pb.GetAnnotations()[compliancev1alpha1.ProfileBundleDebugAnnotation] == "true" {
+		cmd = append(cmd, "--debug")
+	}
+	return cmd
+}
 
-	for _, container := range initContainers {
+// getContentContainerImage returns the image of the init container that
+// provides the content to the workload
+func getContentContainerImage(depl *appsv1.Deployment) string {
+	for _, container := range depl.Spec.Template.Spec.InitContainers {
 		if container.Name == "content-container" {
-			// we need an update if the image reference doesn't match.
-			isSameContentImage = container.Image == image
+			return container.Image
+		}
+	}
+	return ""
+}
+
+// workloadNeedsUpdate returns whether the init containers of the found
+// workload run different images, commands or pull policies than the expected
+// ones
+func workloadNeedsUpdate(expected, found *appsv1.Deployment) bool {
+	return containersNeedUpdate(expected.Spec.Template.Spec.InitContainers, found.Spec.Template.Spec.InitContainers)
+}
+
+// containersNeedUpdate returns whether the found containers run different
+// images, commands or pull policies than the expected ones
+func containersNeedUpdate(expectedContainers, foundContainers []corev1.Container) bool {
+	if len(foundContainers) != len(expectedContainers) {
+		// For some weird reason we don't have the amount of containers we expect.
+		return true
+	}
+
+	for i := range expectedContainers {
+		if foundContainers[i].Name != expectedContainers[i].Name ||
+			foundContainers[i].Image != expectedContainers[i].Image ||
+			!reflect.DeepEqual(foundContainers[i].Command, expectedContainers[i].Command) {
+			return true
 		}
-		if container.Name == "profileparser" {
-			isSaneProfileparserImage = utils.GetComponentImage(utils.OPERATOR) == container.Image
+		// An unset pull policy is defaulted by the API server, so only
+		// policies that were set explicitly are compared
+		if expectedContainers[i].ImagePullPolicy != "" &&
+			foundContainers[i].ImagePullPolicy != expectedContainers[i].ImagePullPolicy {
+			return true
 		}
 	}
 
-	return !(isSameContentImage && isSaneProfileparserImage)
+	return false
+}
+
+// setBundlePending marks the bundle as waiting to be parsed again
+func (r *ReconcileProfileBundle) setBundlePending(pb *compliancev1alpha1.ProfileBundle, logger logr.Logger) error {
+	pbCopy := pb.DeepCopy()
+	pbCopy.Status.DataStreamStatus = compliancev1alpha1.DataStreamPending
+	pbCopy.Status.ErrorMessage = ""
+	pbCopy.Status.SetConditionPending()
+	if err := r.Client.Status().Update(context.TODO(), pbCopy); err != nil {
+		logger.Error(err, "Couldn't update ProfileBundle status")
+		return err
+	}
+	return nil
+}
+
+// setBundleInvalid marks the bundle as invalid for the given reason
+func (r *ReconcileProfileBundle) setBundleInvalid(pb *compliancev1alpha1.ProfileBundle, reason compliancev1alpha1.ConditionReason, message string, logger logr.Logger) error {
+	pbCopy := pb.DeepCopy()
+	pbCopy.Status.DataStreamStatus = compliancev1alpha1.DataStreamInvalid
+	pbCopy.Status.ErrorMessage = message
+	pbCopy.Status.SetConditionInvalid(reason, message)
+	if err := r.Client.Status().Update(context.TODO(), pbCopy); err != nil {
+		logger.Error(err, "Couldn't update ProfileBundle status")
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,963 @@
+package profilebundle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ocpimg "github.com/openshift/api/image/v1"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/common"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/controller/metrics/metricsfakes"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/utils"
+)
+
+func newTestBundle(name string) *compv1alpha1.ProfileBundle {
+	return &compv1alpha1.ProfileBundle{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: common.GetComplianceOperatorNamespace(),
+		},
+		Spec: compv1alpha1.ProfileBundleSpec{
+			ContentImage: "quay.io/complianceascode/ocp4:latest",
+			ContentFile:  "ssg-ocp4-ds.xml",
+		},
+	}
+}
+
+func newTestDeployment(name, bundleName string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: common.GetComplianceOperatorNamespace(),
+			Labels: map[string]string{
+				"profile-bundle": bundleName,
+				"workload":       "profileparser",
+			},
+		},
+	}
+}
+
+func newTestJob(name, bundleName string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: common.GetComplianceOperatorNamespace(),
+			Labels: map[string]string{
+				"profile-bundle": bundleName,
+				"workload":       "profileparser",
+			},
+		},
+	}
+}
+
+// fakeDigestResolver resolves every image to the same configurable digest
+type fakeDigestResolver struct {
+	digest string
+}
+
+func (f *fakeDigestResolver) resolveDigest(_ context.Context, _ string) (string, error) {
+	return f.digest, nil
+}
+
+// fakeSignatureVerifier records the images it was asked to verify and
+// returns a configurable digest and error
+type fakeSignatureVerifier struct {
+	verified []string
+	digest   string
+	err      error
+}
+
+func (f *fakeSignatureVerifier) verifyImage(_ context.Context, image string, _ []*ecdsa.PublicKey) (string, error) {
+	f.verified = append(f.verified, image)
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.digest, nil
+}
+
+var _ = Describe("Testing the profilebundle controller", func() {
+	var (
+		reconciler *ReconcileProfileBundle
+		resolver   *fakeDigestResolver
+		verifier   *fakeSignatureVerifier
+		objs       []runtime.Object
+	)
+
+	BeforeEach(func() {
+		dev, _ := zap.NewDevelopment()
+		log = zapr.NewLogger(dev)
+		objs = []runtime.Object{}
+		resolver = &fakeDigestResolver{}
+		verifier = &fakeSignatureVerifier{}
+	})
+
+	JustBeforeEach(func() {
+		cscheme := scheme.Scheme
+		Expect(apis.AddToScheme(cscheme)).To(Succeed())
+		Expect(ocpimg.AddToScheme(cscheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(cscheme).
+			WithStatusSubresource(&compv1alpha1.ProfileBundle{}, &batchv1.Job{}).
+			WithRuntimeObjects(objs...).
+			Build()
+		reconciler = &ReconcileProfileBundle{
+			Client:            fakeClient,
+			reader:            fakeClient,
+			Scheme:            cscheme,
+			Metrics:           metrics.NewMetrics(&metricsfakes.FakeImpl{}),
+			digestResolver:    resolver,
+			signatureVerifier: verifier,
+		}
+	})
+
+	Context("Handling duplicated finalizers", func() {
+		var pb *compv1alpha1.ProfileBundle
+
+		BeforeEach(func() {
+			pb = newTestBundle("ocp4")
+			pb.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer, compv1alpha1.ProfileBundleFinalizer}
+			objs = append(objs, pb)
+		})
+
+		reconcileBundle := func() {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			})
+			Expect(err).To(BeNil())
+		}
+
+		It("keeps a single finalizer", func() {
+			reconcileBundle()
+
+			updated := &compv1alpha1.ProfileBundle{}
+			key := types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+			Expect(updated.Finalizers).To(Equal([]string{compv1alpha1.ProfileBundleFinalizer}))
+
+			By("keeping it on the next reconcile")
+			reconcileBundle()
+			Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+			Expect(updated.Finalizers).To(Equal([]string{compv1alpha1.ProfileBundleFinalizer}))
+		})
+
+		When("the bundle is being deleted", func() {
+			BeforeEach(func() {
+				now := metav1.Now()
+				pb.DeletionTimestamp = &now
+			})
+
+			It("removes all the duplicates", func() {
+				reconcileBundle()
+
+				updated := &compv1alpha1.ProfileBundle{}
+				key := types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace}
+				err := reconciler.Client.Get(context.TODO(), key, updated)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("Cleaning up orphaned workloads", func() {
+		BeforeEach(func() {
+			ocp4 := newTestBundle("ocp4")
+			ocp4.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer}
+			ocp4.Status.DataStreamStatus = compv1alpha1.DataStreamPending
+			rhcos4 := newTestBundle("rhcos4")
+			objs = append(objs,
+				ocp4,
+				rhcos4,
+				newTestDeployment(getWorkloadName(ocp4), ocp4.Name),
+				newTestDeployment(getWorkloadName(rhcos4), rhcos4.Name),
+				// left over from a bundle that was removed
+				newTestDeployment("removed-"+common.GetComplianceOperatorNamespace()+"-pp", "removed"),
+				// legacy name of a bundle that was removed
+				newTestDeployment("old-pp", "old"),
+				// labels point to a live bundle, but the name doesn't match it
+				newTestDeployment("renamed-"+common.GetComplianceOperatorNamespace()+"-pp", ocp4.Name),
+			)
+			// not a profileparser workload, must be left alone
+			unrelated := newTestDeployment("unrelated", "removed")
+			unrelated.Labels = map[string]string{"app": "unrelated"}
+			objs = append(objs, unrelated)
+			objs = append(objs,
+				newTestJob(getWorkloadName(ocp4), ocp4.Name),
+				// parser job of a bundle that was removed
+				newTestJob("removed-"+common.GetComplianceOperatorNamespace()+"-pp", "removed"),
+			)
+		})
+
+		It("only removes the deployments without an owning ProfileBundle", func() {
+			err := reconciler.deleteOrphanedWorkloads(context.TODO(), log)
+			Expect(err).To(BeNil())
+
+			remaining := &appsv1.DeploymentList{}
+			err = reconciler.Client.List(context.TODO(), remaining, client.InNamespace(common.GetComplianceOperatorNamespace()))
+			Expect(err).To(BeNil())
+
+			names := []string{}
+			for _, depl := range remaining.Items {
+				names = append(names, depl.Name)
+			}
+			Expect(names).To(ConsistOf(
+				"ocp4-"+common.GetComplianceOperatorNamespace()+"-pp",
+				"rhcos4-"+common.GetComplianceOperatorNamespace()+"-pp",
+				"unrelated",
+			))
+		})
+
+		It("only removes the jobs without an owning ProfileBundle", func() {
+			err := reconciler.deleteOrphanedWorkloads(context.TODO(), log)
+			Expect(err).To(BeNil())
+
+			remaining := &batchv1.JobList{}
+			err = reconciler.Client.List(context.TODO(), remaining, client.InNamespace(common.GetComplianceOperatorNamespace()))
+			Expect(err).To(BeNil())
+
+			names := []string{}
+			for _, job := range remaining.Items {
+				names = append(names, job.Name)
+			}
+			Expect(names).To(ConsistOf("ocp4-" + common.GetComplianceOperatorNamespace() + "-pp"))
+		})
+
+		It("doesn't look for orphaned workloads when reconciling a bundle", func() {
+			key := types.NamespacedName{Name: "ocp4", Namespace: common.GetComplianceOperatorNamespace()}
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).To(BeNil())
+
+			orphan := &appsv1.Deployment{}
+			orphanKey := types.NamespacedName{Name: "old-pp", Namespace: common.GetComplianceOperatorNamespace()}
+			Expect(reconciler.Client.Get(context.TODO(), orphanKey, orphan)).To(Succeed())
+		})
+	})
+
+	Context("Tracking the content image digest", func() {
+		const (
+			firstDigest  = "sha256:0000000000000000000000000000000000000000000000000000000000000001"
+			secondDigest = "sha256:0000000000000000000000000000000000000000000000000000000000000002"
+		)
+		var pb *compv1alpha1.ProfileBundle
+
+		BeforeEach(func() {
+			pb = newTestBundle("ocp4")
+			pb.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer}
+			pb.Status.DataStreamStatus = compv1alpha1.DataStreamValid
+			objs = append(objs, pb)
+			resolver.digest = firstDigest
+		})
+
+		reconcileAndGetContentImage := func() string {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			})
+			Expect(err).To(BeNil())
+
+			depl := &appsv1.Deployment{}
+			key := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, depl)).To(Succeed())
+			for _, container := range depl.Spec.Template.Spec.InitContainers {
+				if container.Name == "content-container" {
+					return container.Image
+				}
+			}
+			return ""
+		}
+
+		When("the bundle doesn't track the digest", func() {
+			It("keeps using the tag", func() {
+				Expect(reconcileAndGetContentImage()).To(Equal(pb.Spec.ContentImage))
+				resolver.digest = secondDigest
+				Expect(reconcileAndGetContentImage()).To(Equal(pb.Spec.ContentImage))
+			})
+		})
+
+		When("the bundle tracks the digest", func() {
+			BeforeEach(func() {
+				pb.Annotations = map[string]string{
+					compv1alpha1.ProfileBundleTrackDigestAnnotation: "true",
+				}
+			})
+
+			It("updates the workload when the tag moves to another digest", func() {
+				Expect(reconcileAndGetContentImage()).To(Equal("quay.io/complianceascode/ocp4@" + firstDigest))
+
+				resolver.digest = secondDigest
+				Expect(reconcileAndGetContentImage()).To(Equal("quay.io/complianceascode/ocp4@" + secondDigest))
+
+				updated := &compv1alpha1.ProfileBundle{}
+				key := types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace}
+				Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+				Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamPending))
+			})
+		})
+	})
+
+	Context("Following ImageStreamTag changes", func() {
+		const (
+			firstImage  = "image-registry.openshift-image-registry.svc:5000/openshift/ocp4@sha256:0000000000000000000000000000000000000000000000000000000000000001"
+			secondImage = "image-registry.openshift-image-registry.svc:5000/openshift/ocp4@sha256:0000000000000000000000000000000000000000000000000000000000000002"
+		)
+		var (
+			pb    *compv1alpha1.ProfileBundle
+			istag *ocpimg.ImageStreamTag
+		)
+
+		BeforeEach(func() {
+			pb = newTestBundle("ocp4")
+			pb.Spec.ContentImage = "ocp4:latest"
+			pb.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer}
+			pb.Status.DataStreamStatus = compv1alpha1.DataStreamValid
+			istag = &ocpimg.ImageStreamTag{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ocp4:latest",
+					Namespace: common.GetComplianceOperatorNamespace(),
+				},
+				Image: ocpimg.Image{DockerImageReference: firstImage},
+			}
+			objs = append(objs, pb, istag)
+		})
+
+		reconcileBundle := func() *compv1alpha1.ProfileBundle {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			})
+			Expect(err).To(BeNil())
+
+			updated := &compv1alpha1.ProfileBundle{}
+			key := types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+			return updated
+		}
+
+		It("goes back to pending when the image trigger updates the workload", func() {
+			reconcileBundle()
+			depl := &appsv1.Deployment{}
+			deplKey := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), deplKey, depl)).To(Succeed())
+			Expect(getContentContainerImage(depl)).To(Equal(firstImage))
+
+			// Nothing changed, the bundle stays valid
+			Expect(reconcileBundle().Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamValid))
+
+			// The tag moves and the image trigger updates the workload
+			istagKey := types.NamespacedName{Name: istag.Name, Namespace: istag.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), istagKey, istag)).To(Succeed())
+			istag.Image.DockerImageReference = secondImage
+			Expect(reconciler.Client.Update(context.TODO(), istag)).To(Succeed())
+			Expect(reconciler.Client.Get(context.TODO(), deplKey, depl)).To(Succeed())
+			for i := range depl.Spec.Template.Spec.InitContainers {
+				if depl.Spec.Template.Spec.InitContainers[i].Name == "content-container" {
+					depl.Spec.Template.Spec.InitContainers[i].Image = secondImage
+				}
+			}
+			Expect(reconciler.Client.Update(context.TODO(), depl)).To(Succeed())
+
+			updated := reconcileBundle()
+			Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamPending))
+			Expect(reconciler.Client.Get(context.TODO(), deplKey, depl)).To(Succeed())
+			Expect(depl.Annotations).To(HaveKeyWithValue(workloadContentImageAnnotation, secondImage))
+		})
+
+		When("the bundle asks for verification", func() {
+			BeforeEach(func() {
+				pb.Annotations = map[string]string{
+					compv1alpha1.ProfileBundleSignatureKeysAnnotation: "content-keys",
+				}
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				Expect(err).To(BeNil())
+				der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+				Expect(err).To(BeNil())
+				objs = append(objs, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "content-keys",
+						Namespace: pb.Namespace,
+					},
+					Data: map[string]string{
+						"cosign.pub": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+					},
+				})
+				verifier.digest = "sha256:0000000000000000000000000000000000000000000000000000000000000001"
+			})
+
+			It("verifies the image the trigger rolled out", func() {
+				reconcileBundle()
+				depl := &appsv1.Deployment{}
+				deplKey := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+				Expect(reconciler.Client.Get(context.TODO(), deplKey, depl)).To(Succeed())
+				Expect(verifier.verified).To(Equal([]string{firstImage}))
+
+				// The tag moves to an image that isn't signed and the image
+				// trigger rolls it out
+				istagKey := types.NamespacedName{Name: istag.Name, Namespace: istag.Namespace}
+				Expect(reconciler.Client.Get(context.TODO(), istagKey, istag)).To(Succeed())
+				istag.Image.DockerImageReference = secondImage
+				Expect(reconciler.Client.Update(context.TODO(), istag)).To(Succeed())
+				for i := range depl.Spec.Template.Spec.InitContainers {
+					if depl.Spec.Template.Spec.InitContainers[i].Name == "content-container" {
+						depl.Spec.Template.Spec.InitContainers[i].Image = secondImage
+					}
+				}
+				Expect(reconciler.Client.Update(context.TODO(), depl)).To(Succeed())
+				verifier.err = &signatureVerificationError{image: secondImage, reason: "the image has no signatures"}
+
+				updated := reconcileBundle()
+				Expect(verifier.verified).To(Equal([]string{firstImage, secondImage}))
+				Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamInvalid))
+				Expect(updated.Status.Conditions.GetCondition(compv1alpha1.ProfileBundleConditionDegraded).Reason).To(
+					Equal(compv1alpha1.ProfileBundleReasonSignatureVerificationFailed))
+				err := reconciler.Client.Get(context.TODO(), deplKey, depl)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		It("maps the workload to its bundle", func() {
+			reconcileBundle()
+			depl := &appsv1.Deployment{}
+			deplKey := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), deplKey, depl)).To(Succeed())
+
+			mapper := &workloadMapper{reconciler.Client}
+			Expect(mapper.Map(context.TODO(), depl)).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			}))
+			Expect(mapper.Map(context.TODO(), newTestDeployment("unrelated", "removed"))).To(BeEmpty())
+		})
+	})
+
+	Context("Detecting content container failures", func() {
+		var pb *compv1alpha1.ProfileBundle
+
+		BeforeEach(func() {
+			pb = newTestBundle("ocp4")
+			pb.Spec.ContentFile = "missing-ds.xml"
+			pb.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer}
+			pb.Status.DataStreamStatus = compv1alpha1.DataStreamPending
+			objs = append(objs, pb)
+		})
+
+		reconcileBundle := func() *compv1alpha1.ProfileBundle {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			})
+			Expect(err).To(BeNil())
+
+			updated := &compv1alpha1.ProfileBundle{}
+			key := types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+			return updated
+		}
+
+		createParserPod := func(initStatus corev1.ContainerStatus) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      getWorkloadName(pb) + "-abcde",
+					Namespace: pb.Namespace,
+					Labels:    getWorkloadLabels(pb),
+				},
+				Status: corev1.PodStatus{
+					InitContainerStatuses: []corev1.ContainerStatus{initStatus},
+				},
+			}
+			Expect(reconciler.Client.Create(context.TODO(), pod)).To(Succeed())
+		}
+
+		It("marks the bundle as invalid when the content container fails", func() {
+			// Create the workload and record its content image
+			reconcileBundle()
+			reconcileBundle()
+
+			createParserPod(corev1.ContainerStatus{
+				Name: "content-container",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: contentFileMissingExitCode},
+				},
+			})
+
+			updated := reconcileBundle()
+			Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamInvalid))
+			Expect(updated.Status.ErrorMessage).To(ContainSubstring("content file was not found in the image"))
+			Expect(updated.Status.Conditions.GetCondition("Ready").Reason).To(BeEquivalentTo("Invalid"))
+			degraded := updated.Status.Conditions.GetCondition(compv1alpha1.ProfileBundleConditionDegraded)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.IsTrue()).To(BeTrue())
+			Expect(degraded.Reason).To(Equal(compv1alpha1.ProfileBundleReasonContentFileMissing))
+		})
+
+		It("marks the bundle as degraded when the content image can't be pulled", func() {
+			reconcileBundle()
+			reconcileBundle()
+
+			createParserPod(corev1.ContainerStatus{
+				Name: "content-container",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+				},
+			})
+
+			updated := reconcileBundle()
+			Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamInvalid))
+			Expect(updated.Status.Conditions.GetCondition("Ready").Reason).To(BeEquivalentTo("Invalid"))
+			degraded := updated.Status.Conditions.GetCondition(compv1alpha1.ProfileBundleConditionDegraded)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.IsTrue()).To(BeTrue())
+			Expect(degraded.Reason).To(Equal(compv1alpha1.ProfileBundleReasonImagePullFailed))
+			Expect(degraded.Message).To(Equal(updated.Status.ErrorMessage))
+		})
+
+		It("leaves the bundle alone when the content container succeeds", func() {
+			reconcileBundle()
+			reconcileBundle()
+
+			createParserPod(corev1.ContainerStatus{
+				Name:  "content-container",
+				Ready: true,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+				},
+			})
+
+			Expect(reconcileBundle().Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamPending))
+		})
+	})
+
+	Context("Choosing the content source", func() {
+		var pb *compv1alpha1.ProfileBundle
+
+		BeforeEach(func() {
+			pb = newTestBundle("ocp4")
+			pb.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer}
+			pb.Status.DataStreamStatus = compv1alpha1.DataStreamValid
+			objs = append(objs, pb)
+		})
+
+		reconcileAndGetContentContainer := func() corev1.Container {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			})
+			Expect(err).To(BeNil())
+
+			depl := &appsv1.Deployment{}
+			key := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, depl)).To(Succeed())
+			Expect(depl.Spec.Template.Spec.InitContainers).To(HaveLen(2))
+			Expect(depl.Spec.Template.Spec.InitContainers[1].Command).To(ContainElement("/content/ssg-ocp4-ds.xml"))
+			return depl.Spec.Template.Spec.InitContainers[0]
+		}
+
+		It("copies the content out of the image by default", func() {
+			container := reconcileAndGetContentContainer()
+			Expect(container.Name).To(Equal("content-container"))
+			Expect(container.Image).To(Equal(pb.Spec.ContentImage))
+			Expect(container.Command).To(Equal([]string{"sh", "-c",
+				"test -f /ssg-ocp4-ds.xml || { echo 'content file /ssg-ocp4-ds.xml not found in image'; exit 3; }; cp /ssg-ocp4-ds.xml /content"}))
+		})
+
+		When("the content is an OCI artifact", func() {
+			BeforeEach(func() {
+				pb.Spec.ContentSource = compv1alpha1.ContentSourceArtifact
+			})
+
+			It("downloads the content from the artifact", func() {
+				container := reconcileAndGetContentContainer()
+				Expect(container.Name).To(Equal("content-container"))
+				Expect(container.Image).To(Equal(utils.GetComponentImage(utils.OPERATOR)))
+				Expect(container.Command).To(Equal([]string{
+					"compliance-operator", "fetch-artifact",
+					"--artifact", pb.Spec.ContentImage,
+					"--file", "ssg-ocp4-ds.xml",
+					"--output", "/content/ssg-ocp4-ds.xml",
+				}))
+			})
+
+			It("updates the workload when switching back to an image", func() {
+				reconcileAndGetContentContainer()
+
+				updated := &compv1alpha1.ProfileBundle{}
+				key := types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace}
+				Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+				updated.Spec.ContentSource = compv1alpha1.ContentSourceImage
+				Expect(reconciler.Client.Update(context.TODO(), updated)).To(Succeed())
+
+				container := reconcileAndGetContentContainer()
+				Expect(container.Image).To(Equal(pb.Spec.ContentImage))
+				Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+				Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamPending))
+			})
+		})
+	})
+
+	Context("Choosing the content image pull policy", func() {
+		const digestImage = "quay.io/complianceascode/ocp4@sha256:4f3b2c0cde21f1d0f3a0ab0fbc7fd0b1e1d1f3bcd9da0b17e0a9b2e3b7d3c1a2"
+
+		var pb *compv1alpha1.ProfileBundle
+
+		BeforeEach(func() {
+			pb = newTestBundle("ocp4")
+			pb.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer}
+			pb.Status.DataStreamStatus = compv1alpha1.DataStreamValid
+			objs = append(objs, pb)
+		})
+
+		reconcileAndGetPullPolicy := func() corev1.PullPolicy {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			})
+			Expect(err).To(BeNil())
+
+			depl := &appsv1.Deployment{}
+			key := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, depl)).To(Succeed())
+			Expect(depl.Spec.Template.Spec.InitContainers[0].Name).To(Equal("content-container"))
+			return depl.Spec.Template.Spec.InitContainers[0].ImagePullPolicy
+		}
+
+		It("always pulls images referenced by a tag", func() {
+			Expect(reconcileAndGetPullPolicy()).To(Equal(corev1.PullAlways))
+		})
+
+		When("the image is pinned to a digest", func() {
+			BeforeEach(func() {
+				pb.Spec.ContentImage = digestImage
+			})
+
+			It("only pulls the image if it's not present", func() {
+				Expect(reconcileAndGetPullPolicy()).To(Equal(corev1.PullIfNotPresent))
+			})
+		})
+
+		When("the bundle sets a pull policy", func() {
+			BeforeEach(func() {
+				pb.Spec.ContentImagePullPolicy = corev1.PullNever
+			})
+
+			It("uses the policy of the bundle", func() {
+				Expect(reconcileAndGetPullPolicy()).To(Equal(corev1.PullNever))
+			})
+
+			It("updates the workload when the policy changes", func() {
+				reconcileAndGetPullPolicy()
+
+				updated := &compv1alpha1.ProfileBundle{}
+				key := types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace}
+				Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+				updated.Spec.ContentImagePullPolicy = corev1.PullIfNotPresent
+				Expect(reconciler.Client.Update(context.TODO(), updated)).To(Succeed())
+
+				Expect(reconcileAndGetPullPolicy()).To(Equal(corev1.PullIfNotPresent))
+				Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+				Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamPending))
+			})
+		})
+	})
+
+	Context("Running the profileparser with debug logging", func() {
+		var pb *compv1alpha1.ProfileBundle
+
+		BeforeEach(func() {
+			pb = newTestBundle("ocp4")
+			pb.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer}
+			pb.Status.DataStreamStatus = compv1alpha1.DataStreamValid
+			objs = append(objs, pb)
+		})
+
+		reconcileAndGetParserCommand := func() []string {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			})
+			Expect(err).To(BeNil())
+
+			depl := &appsv1.Deployment{}
+			key := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, depl)).To(Succeed())
+			Expect(depl.Spec.Template.Spec.InitContainers).To(HaveLen(2))
+			Expect(depl.Spec.Template.Spec.InitContainers[1].Name).To(Equal("profileparser"))
+			return depl.Spec.Template.Spec.InitContainers[1].Command
+		}
+
+		It("doesn't pass the debug flag by default", func() {
+			Expect(reconcileAndGetParserCommand()).NotTo(ContainElement("--debug"))
+		})
+
+		When("debug logging is requested", func() {
+			BeforeEach(func() {
+				pb.Annotations = map[string]string{
+					compv1alpha1.ProfileBundleDebugAnnotation: "true",
+				}
+			})
+
+			It("passes the debug flag", func() {
+				Expect(reconcileAndGetParserCommand()).To(Equal([]string{
+					"compliance-operator", "profileparser",
+					"--name", pb.Name,
+					"--namespace", pb.Namespace,
+					"--ds-path", "/content/ssg-ocp4-ds.xml",
+					"--debug",
+				}))
+			})
+		})
+	})
+
+	Context("Parsing the content with a Job", func() {
+		var pb *compv1alpha1.ProfileBundle
+
+		BeforeEach(func() {
+			pb = newTestBundle("ocp4")
+			pb.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer}
+			pb.Annotations = map[string]string{
+				compv1alpha1.ProfileBundleParserJobAnnotation: "true",
+			}
+			pb.Status.DataStreamStatus = compv1alpha1.DataStreamValid
+			objs = append(objs, pb)
+		})
+
+		reconcileBundle := func() (reconcile.Result, *compv1alpha1.ProfileBundle) {
+			res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			})
+			Expect(err).To(BeNil())
+
+			updated := &compv1alpha1.ProfileBundle{}
+			key := types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+			return res, updated
+		}
+
+		getJob := func() *batchv1.Job {
+			job := &batchv1.Job{}
+			key := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, job)).To(Succeed())
+			return job
+		}
+
+		It("runs the profileparser as the main container of a Job", func() {
+			res, updated := reconcileBundle()
+			Expect(res.Requeue).To(BeTrue())
+			Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamPending))
+
+			job := getJob()
+			podSpec := job.Spec.Template.Spec
+			Expect(podSpec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			Expect(podSpec.InitContainers).To(HaveLen(1))
+			Expect(podSpec.InitContainers[0].Name).To(Equal("content-container"))
+			Expect(podSpec.Containers).To(HaveLen(1))
+			Expect(podSpec.Containers[0].Name).To(Equal("profileparser"))
+
+			depl := &appsv1.Deployment{}
+			key := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+			Expect(kerrors.IsNotFound(reconciler.Client.Get(context.TODO(), key, depl))).To(BeTrue())
+		})
+
+		It("stops requeueing once the Job completed", func() {
+			reconcileBundle()
+			res, _ := reconcileBundle()
+			Expect(res.Requeue).To(BeTrue())
+
+			job := getJob()
+			job.Status.Succeeded = 1
+			Expect(reconciler.Client.Status().Update(context.TODO(), job)).To(Succeed())
+
+			res, _ = reconcileBundle()
+			Expect(res).To(Equal(reconcile.Result{}))
+		})
+
+		It("marks the bundle as degraded when the Job failed", func() {
+			reconcileBundle()
+
+			job := getJob()
+			job.Status.Conditions = []batchv1.JobCondition{{
+				Type:    batchv1.JobFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit",
+			}}
+			Expect(reconciler.Client.Status().Update(context.TODO(), job)).To(Succeed())
+
+			_, updated := reconcileBundle()
+			Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamInvalid))
+			Expect(updated.Status.ErrorMessage).To(ContainSubstring("backoff limit"))
+			degraded := updated.Status.Conditions.GetCondition(compv1alpha1.ProfileBundleConditionDegraded)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.IsTrue()).To(BeTrue())
+			Expect(degraded.Reason).To(Equal(compv1alpha1.ProfileBundleReasonParseFailed))
+		})
+
+		When("the bundle was parsed by a Deployment before", func() {
+			BeforeEach(func() {
+				objs = append(objs, newTestDeployment(getWorkloadName(pb), pb.Name))
+			})
+
+			It("replaces the Deployment with a Job", func() {
+				reconcileBundle()
+
+				depl := &appsv1.Deployment{}
+				key := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+				Expect(kerrors.IsNotFound(reconciler.Client.Get(context.TODO(), key, depl))).To(BeTrue())
+				getJob()
+			})
+		})
+	})
+
+	Context("Verifying the content image signature", func() {
+		const digest = "sha256:0000000000000000000000000000000000000000000000000000000000000001"
+		var pb *compv1alpha1.ProfileBundle
+
+		BeforeEach(func() {
+			resolver.digest = digest
+			verifier.digest = digest
+			pb = newTestBundle("ocp4")
+			pb.Finalizers = []string{compv1alpha1.ProfileBundleFinalizer}
+			pb.Status.DataStreamStatus = compv1alpha1.DataStreamPending
+			objs = append(objs, pb)
+		})
+
+		getDeployment := func() (*appsv1.Deployment, error) {
+			depl := &appsv1.Deployment{}
+			deplKey := types.NamespacedName{Name: getWorkloadName(pb), Namespace: pb.Namespace}
+			return depl, reconciler.Client.Get(context.TODO(), deplKey, depl)
+		}
+
+		reconcileBundle := func() (*compv1alpha1.ProfileBundle, bool) {
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+			})
+			Expect(err).To(BeNil())
+
+			updated := &compv1alpha1.ProfileBundle{}
+			key := types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace}
+			Expect(reconciler.Client.Get(context.TODO(), key, updated)).To(Succeed())
+
+			_, deplErr := getDeployment()
+			return updated, deplErr == nil
+		}
+
+		newKeysConfigMap := func(name string) *corev1.ConfigMap {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).To(BeNil())
+			der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+			Expect(err).To(BeNil())
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: pb.Namespace,
+				},
+				Data: map[string]string{
+					"cosign.pub": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+				},
+			}
+		}
+
+		When("the bundle doesn't ask for verification", func() {
+			It("creates the workload without verifying the image", func() {
+				_, created := reconcileBundle()
+				Expect(created).To(BeTrue())
+				Expect(verifier.verified).To(BeEmpty())
+			})
+		})
+
+		When("the bundle asks for verification", func() {
+			BeforeEach(func() {
+				pb.Annotations = map[string]string{
+					compv1alpha1.ProfileBundleSignatureKeysAnnotation: "content-keys",
+				}
+				objs = append(objs, newKeysConfigMap("content-keys"))
+			})
+
+			It("creates the workload if the signature is valid", func() {
+				_, created := reconcileBundle()
+				Expect(created).To(BeTrue())
+				Expect(verifier.verified).To(HaveLen(1))
+			})
+
+			It("runs the content image by the digest that was verified", func() {
+				reconcileBundle()
+				depl, err := getDeployment()
+				Expect(err).To(BeNil())
+				pinned := "quay.io/complianceascode/ocp4@" + digest
+				Expect(getContentContainerImage(depl)).To(Equal(pinned))
+				Expect(depl.Annotations[workloadContentImageAnnotation]).To(Equal(pinned))
+
+				// The tag is resolved again on the next reconcile, which
+				// leaves the workload alone while it points to the same image
+				reconcileBundle()
+				Expect(verifier.verified).To(HaveLen(1))
+			})
+
+			It("marks the bundle as invalid if the signature isn't valid", func() {
+				verifier.err = &signatureVerificationError{image: pb.Spec.ContentImage, reason: "the image has no signatures"}
+				updated, created := reconcileBundle()
+				Expect(created).To(BeFalse())
+				Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamInvalid))
+				Expect(updated.Status.ErrorMessage).To(ContainSubstring("the image has no signatures"))
+				Expect(updated.Status.Conditions.GetCondition(compv1alpha1.ProfileBundleConditionDegraded).Reason).To(
+					Equal(compv1alpha1.ProfileBundleReasonSignatureVerificationFailed))
+			})
+
+			It("retries if the registry can't be reached", func() {
+				verifier.err = fmt.Errorf("connection refused")
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+				})
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("the keys are missing", func() {
+			BeforeEach(func() {
+				pb.Annotations = map[string]string{
+					compv1alpha1.ProfileBundleSignatureKeysAnnotation: "missing-keys",
+				}
+			})
+
+			It("marks the bundle as invalid", func() {
+				updated, created := reconcileBundle()
+				Expect(created).To(BeFalse())
+				Expect(verifier.verified).To(BeEmpty())
+				Expect(updated.Status.DataStreamStatus).To(Equal(compv1alpha1.DataStreamInvalid))
+				Expect(updated.Status.ErrorMessage).To(ContainSubstring("missing-keys"))
+			})
+
+			It("verifies the image again once the keys are created", func() {
+				_, created := reconcileBundle()
+				Expect(created).To(BeFalse())
+
+				keys := newKeysConfigMap("missing-keys")
+				Expect(reconciler.Client.Create(context.TODO(), keys)).To(Succeed())
+				mapper := &signatureKeysMapper{reconciler.Client}
+				Expect(mapper.Map(context.TODO(), keys)).To(ConsistOf(reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: pb.Name, Namespace: pb.Namespace},
+				}))
+				Expect(mapper.Map(context.TODO(), newKeysConfigMap("unrelated"))).To(BeEmpty())
+
+				_, created = reconcileBundle()
+				Expect(created).To(BeTrue())
+				Expect(verifier.verified).To(HaveLen(1))
+			})
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package profilebundle
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestProfilebundle(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Profilebundle Suite")
+}
@@ -0,0 +1,192 @@
+package profilebundle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/image/reference"
+)
+
+// The annotation cosign stores the signature of a payload layer in
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// Signature payloads are small JSON documents, don't read more than this
+const maxSignaturePayloadSize = 1 << 20
+
+// imageSignatureVerifier checks that an image was signed with one of the
+// given keys and returns the digest of the image that was verified
+type imageSignatureVerifier interface {
+	verifyImage(ctx context.Context, image string, keys []*ecdsa.PublicKey) (string, error)
+}
+
+// signatureVerificationError means that the image is definitely not signed
+// with any of the keys, as opposed to errors talking to the registry, which
+// might go away on their own.
+type signatureVerificationError struct {
+	image  string
+	reason string
+}
+
+func (e *signatureVerificationError) Error() string {
+	return fmt.Sprintf("couldn't verify the signature of %s: %s", e.image, e.reason)
+}
+
+// cosignSignatureVerifier verifies the signatures cosign stores in the
+// registry next to the image, under the sha256-<digest>.sig tag. Only
+// anonymous registry access and ECDSA keys are supported.
+type cosignSignatureVerifier struct {
+	registry *registryDigestResolver
+}
+
+func newCosignSignatureVerifier() *cosignSignatureVerifier {
+	return &cosignSignatureVerifier{registry: newRegistryDigestResolver()}
+}
+
+func (v *cosignSignatureVerifier) verifyImage(ctx context.Context, image string, keys []*ecdsa.PublicKey) (string, error) {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return "", &signatureVerificationError{image: image, reason: err.Error()}
+	}
+	ref = ref.DockerClientDefaults().AsV2()
+	digest := ref.ID
+	if digest == "" {
+		digest, err = v.registry.resolveDigest(ctx, image)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	repoURL := fmt.Sprintf("https://%s/v2/%s", ref.Registry, ref.RepositoryName())
+	sigTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	resp, err := v.registry.do(ctx, http.MethodGet, repoURL+"/manifests/"+sigTag,
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", &signatureVerificationError{image: image, reason: "the image has no signatures"}
+	} else if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status getting the signatures of %s: %s", image, resp.Status)
+	}
+
+	manifest := struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return "", fmt.Errorf("couldn't parse the signatures of %s: %w", image, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		signature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		payload, err := v.getBlob(ctx, repoURL, layer.Digest)
+		if err != nil {
+			return "", err
+		}
+		if verifySignedPayload(payload, signature, digest, keys) == nil {
+			return digest, nil
+		}
+	}
+	return "", &signatureVerificationError{image: image, reason: "no signature matches the configured keys"}
+}
+
+// getBlob downloads a blob and checks that its contents match the digest
+func (v *cosignSignatureVerifier) getBlob(ctx context.Context, repoURL, digest string) ([]byte, error) {
+	resp, err := v.registry.do(ctx, http.MethodGet, repoURL+"/blobs/"+digest)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status getting blob %s: %s", digest, resp.Status)
+	}
+
+	blob, err := io.ReadAll(io.LimitReader(resp.Body, maxSignaturePayloadSize))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(blob)
+	if digest != "sha256:"+hex.EncodeToString(sum[:]) {
+		return nil, fmt.Errorf("the contents of blob %s don't match its digest", digest)
+	}
+	return blob, nil
+}
+
+// verifySignedPayload checks that the payload refers to the image digest and
+// that the signature of the payload was made with one of the keys
+func verifySignedPayload(payload []byte, signature, digest string, keys []*ecdsa.PublicKey) error {
+	simpleSigning := struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}{}
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return err
+	}
+	if simpleSigning.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("the signature is for a different image: %s", simpleSigning.Critical.Image.DockerManifestDigest)
+	}
+
+	rawSignature, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(payload)
+	for _, key := range keys {
+		if ecdsa.VerifyASN1(key, hash[:], rawSignature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the signature doesn't match any key")
+}
+
+// parsePublicKeys reads the PEM encoded public keys in the values of a
+// ConfigMap
+func parsePublicKeys(data map[string]string) ([]*ecdsa.PublicKey, error) {
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	keys := make([]*ecdsa.PublicKey, 0, len(names))
+	for _, name := range names {
+		block, _ := pem.Decode([]byte(data[name]))
+		if block == nil {
+			return nil, fmt.Errorf("key %s is not PEM encoded", name)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse key %s: %w", name, err)
+		}
+		ecdsaKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("key %s is not an ECDSA public key", name)
+		}
+		keys = append(keys, ecdsaKey)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys configured")
+	}
+	return keys, nil
+}
@@ -0,0 +1,43 @@
+package profilebundle
+
+import (
+	"context"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// signatureKeysMapper maps a ConfigMap to the bundles that verify their
+// content image with the keys it holds, so that creating or fixing the keys
+// gets the signature of those bundles verified again
+type signatureKeysMapper struct {
+	client.Client
+}
+
+func (s *signatureKeysMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	pbList := v1alpha1.ProfileBundleList{}
+	err := s.List(ctx, &pbList, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return requests
+	}
+
+	for i := range pbList.Items {
+		pb := &pbList.Items[i]
+		keysConfigMap, ok := pb.Annotations[v1alpha1.ProfileBundleSignatureKeysAnnotation]
+		if !ok || keysConfigMap != obj.GetName() {
+			continue
+		}
+
+		objKey := types.NamespacedName{
+			Name:      pb.GetName(),
+			Namespace: pb.GetNamespace(),
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: objKey})
+	}
+
+	return requests
+}
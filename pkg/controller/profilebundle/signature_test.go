@@ -0,0 +1,91 @@
+package profilebundle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verifying content image signatures", func() {
+	const digest = "sha256:0000000000000000000000000000000000000000000000000000000000000001"
+	var (
+		server     *httptest.Server
+		verifier   *cosignSignatureVerifier
+		signingKey *ecdsa.PrivateKey
+		payload    []byte
+		signature  []byte
+		image      string
+	)
+
+	BeforeEach(func() {
+		var err error
+		signingKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).To(BeNil())
+		payload = []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"ocp4"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"}}`, digest))
+		hash := sha256.Sum256(payload)
+		signature, err = ecdsa.SignASN1(rand.Reader, signingKey, hash[:])
+		Expect(err).To(BeNil())
+		payloadSum := sha256.Sum256(payload)
+		payloadDigest := "sha256:" + hex.EncodeToString(payloadSum[:])
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v2/complianceascode/ocp4/manifests/latest", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Docker-Content-Digest", digest)
+		})
+		mux.HandleFunc("/v2/complianceascode/ocp4/manifests/sha256-"+strings.TrimPrefix(digest, "sha256:")+".sig", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"layers": [{"digest": "%s", "annotations": {"%s": "%s"}}]}`,
+				payloadDigest, cosignSignatureAnnotation, base64.StdEncoding.EncodeToString(signature))
+		})
+		mux.HandleFunc("/v2/complianceascode/ocp4/blobs/"+payloadDigest, func(w http.ResponseWriter, r *http.Request) {
+			w.Write(payload)
+		})
+		server = httptest.NewTLSServer(mux)
+		verifier = &cosignSignatureVerifier{registry: &registryDigestResolver{client: server.Client()}}
+		image = strings.TrimPrefix(server.URL, "https://") + "/complianceascode/ocp4:latest"
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("accepts an image signed with one of the keys", func() {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).To(BeNil())
+		verified, err := verifier.verifyImage(context.TODO(), image, []*ecdsa.PublicKey{&otherKey.PublicKey, &signingKey.PublicKey})
+		Expect(err).To(BeNil())
+		Expect(verified).To(Equal(digest))
+	})
+
+	It("rejects an image signed with a different key", func() {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).To(BeNil())
+		_, err = verifier.verifyImage(context.TODO(), image, []*ecdsa.PublicKey{&otherKey.PublicKey})
+		var sigErr *signatureVerificationError
+		Expect(err).To(BeAssignableToTypeOf(sigErr))
+	})
+
+	It("rejects an image without signatures", func() {
+		unsigned := strings.TrimSuffix(image, ":latest") + "@sha256:0000000000000000000000000000000000000000000000000000000000000002"
+		_, err := verifier.verifyImage(context.TODO(), unsigned, []*ecdsa.PublicKey{&signingKey.PublicKey})
+		var sigErr *signatureVerificationError
+		Expect(err).To(BeAssignableToTypeOf(sigErr))
+		Expect(err.Error()).To(ContainSubstring("no signatures"))
+	})
+
+	It("rejects a signature made for a different image", func() {
+		Expect(verifySignedPayload(payload, base64.StdEncoding.EncodeToString(signature),
+			"sha256:0000000000000000000000000000000000000000000000000000000000000002",
+			[]*ecdsa.PublicKey{&signingKey.PublicKey})).ToNot(Succeed())
+	})
+})
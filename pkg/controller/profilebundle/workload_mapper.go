@@ -0,0 +1,42 @@
+package profilebundle
+
+import (
+	"context"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// workloadMapper maps a profileparser deployment to the bundle it parses,
+// so that changes made to the deployment by others, such as the image
+// trigger of an ImageStreamTag, get reconciled
+type workloadMapper struct {
+	client.Client
+}
+
+func (w *workloadMapper) Map(ctx context.Context, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	pbList := v1alpha1.ProfileBundleList{}
+	err := w.List(ctx, &pbList, &client.ListOptions{})
+	if err != nil {
+		return requests
+	}
+
+	for i := range pbList.Items {
+		pb := &pbList.Items[i]
+		if !hasWorkloadLabels(obj, pb) || obj.GetName() != getWorkloadName(pb) {
+			continue
+		}
+
+		objKey := types.NamespacedName{
+			Name:      pb.GetName(),
+			Namespace: pb.GetNamespace(),
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: objKey})
+	}
+
+	return requests
+}
@@ -144,15 +144,24 @@ func (r *ReconcileScanSettingBinding) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, err
 	}
 
-	for i := range instance.Profiles {
-		ss := &instance.Profiles[i]
-
-		key := types.NamespacedName{Namespace: instance.Namespace, Name: ss.Name}
-		profileObj, geterr := getUnstructured(r, instance, key, ss.Kind, ss.APIGroup, reqLogger)
-		if geterr != nil {
-			return reconcile.Result{}, geterr
+	refs, msg, err := r.resolveReferences(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	} else if msg != "" {
+		reqLogger.Info("ScanSettingBinding has an invalid reference", "message", msg)
+		if !scanSettingBindingHasInvalidCondition(instance, msg) {
+			ssb := instance.DeepCopy()
+			ssb.Status.SetConditionInvalid(msg)
+			ssb.Status.Phase = compliancev1alpha1.ScanSettingBindingPhaseInvalid
+			if updateErr := r.Client.Status().Update(context.TODO(), ssb); updateErr != nil {
+				return reconcile.Result{}, fmt.Errorf("couldn't update ScanSettingBinding condition: %w", updateErr)
+			}
 		}
+		// The referenced object might just not have been created yet
+		return reconcile.Result{Requeue: true, RequeueAfter: requeueAfterDefault}, nil
+	}
 
+	for _, profileObj := range refs.profiles {
 		if profileObj.GetKind() == "TailoredProfile" {
 			val, found, nsErr := unstructured.NestedString(
 				profileObj.Object, "status", "state")
@@ -191,7 +200,7 @@ func (r *ReconcileScanSettingBinding) Reconcile(ctx context.Context, request rec
 	}
 
 	if instance.SettingsRef != nil {
-		err := r.applyConstraint(instance, &suite, instance.SettingsRef, log)
+		err := r.applyConstraint(instance, &suite, refs.settings, log)
 		if err != nil {
 			return common.ReturnWithRetriableError(reqLogger, err)
 		}
@@ -312,6 +321,73 @@ func (r *ReconcileScanSettingBinding) Reconcile(ctx context.Context, request rec
 	return reconcile.Result{}, nil
 }
 
+// bindingReferences holds the objects a binding references, in the order of
+// the binding
+type bindingReferences struct {
+	profiles []*unstructured.Unstructured
+	settings *unstructured.Unstructured
+}
+
+// resolveReferences gets every object the binding references from the
+// binding's namespace, checking that it exists and is of a kind the binding
+// can use. If one of them isn't, it returns a message naming the first such
+// reference instead.
+func (r *ReconcileScanSettingBinding) resolveReferences(instance *compliancev1alpha1.ScanSettingBinding) (*bindingReferences, string, error) {
+	refs := &bindingReferences{}
+	for i := range instance.Profiles {
+		ref := &instance.Profiles[i]
+		if ref.Kind != "Profile" && ref.Kind != "TailoredProfile" {
+			return nil, fmt.Sprintf("Profile reference %s has unsupported kind %s, use one of Profile, TailoredProfile",
+				ref.Name, ref.Kind), nil
+		}
+		profile, msg, err := r.resolveReference(instance, ref)
+		if msg != "" || err != nil {
+			return nil, msg, err
+		}
+		refs.profiles = append(refs.profiles, profile)
+	}
+
+	if instance.SettingsRef != nil {
+		if instance.SettingsRef.Kind != "ScanSetting" {
+			return nil, fmt.Sprintf("Settings reference %s has unsupported kind %s, use ScanSetting",
+				instance.SettingsRef.Name, instance.SettingsRef.Kind), nil
+		}
+		settings, msg, err := r.resolveReference(instance, instance.SettingsRef)
+		if msg != "" || err != nil {
+			return nil, msg, err
+		}
+		refs.settings = settings
+	}
+
+	return refs, "", nil
+}
+
+func (r *ReconcileScanSettingBinding) resolveReference(instance *compliancev1alpha1.ScanSettingBinding, ref *compliancev1alpha1.NamedObjectReference) (*unstructured.Unstructured, string, error) {
+	o := unstructured.Unstructured{}
+	o.SetAPIVersion(ref.APIGroup)
+	o.SetKind(ref.Kind)
+
+	key := types.NamespacedName{Namespace: instance.Namespace, Name: ref.Name}
+	err := r.Client.Get(context.TODO(), key, &o)
+	if errors.IsNotFound(err) {
+		// This might be a temporary issue in the order the objects are being created
+		r.Eventf(
+			instance, corev1.EventTypeWarning, "NamedReferenceLookupError",
+			"NamedObjectReference %s %s not found", ref.Kind, key,
+		)
+		return nil, fmt.Sprintf("The %s %s referenced by the binding was not found in namespace %s",
+			ref.Kind, ref.Name, instance.Namespace), nil
+	} else if err != nil {
+		return nil, "", err
+	}
+	return &o, "", nil
+}
+
+func scanSettingBindingHasInvalidCondition(ssb *compliancev1alpha1.ScanSettingBinding, msg string) bool {
+	c := ssb.Status.Conditions.GetCondition("Ready")
+	return c != nil && c.Status == "False" && c.Reason == "Invalid" && c.Message == msg
+}
+
 func getRelevantProduct(nodeProduct, incomingProduct string) string {
 	// Initialize
 	if nodeProduct == "" && incomingProduct != "" {
@@ -327,15 +403,9 @@ func isDifferentProduct(nodeProduct, incomingProduct string) bool {
 func (r *ReconcileScanSettingBinding) applyConstraint(
 	instance *compliancev1alpha1.ScanSettingBinding,
 	suite *compliancev1alpha1.ComplianceSuite,
-	constraintRef *compliancev1alpha1.NamedObjectReference,
+	constraint *unstructured.Unstructured,
 	logger logr.Logger,
 ) error {
-	key := types.NamespacedName{Namespace: instance.Namespace, Name: constraintRef.Name}
-	constraint, err := getUnstructured(r, instance, key, constraintRef.Kind, constraintRef.APIGroup, logger)
-	if err != nil {
-		return err
-	}
-
 	if err := isCmpv1Alpha1Gvk(constraint, "ScanSetting"); err != nil {
 		return err
 	}
@@ -13,10 +13,13 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
@@ -577,7 +580,7 @@ var _ = Describe("Testing scansettingbinding controller", func() {
 					Name:      ssb.Name,
 				},
 			})
-			Expect(err).ToNot(BeNil())
+			Expect(err).To(BeNil())
 
 			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{
 				Namespace: ssb.Namespace,
@@ -586,12 +589,161 @@ var _ = Describe("Testing scansettingbinding controller", func() {
 			Expect(err).To(BeNil())
 			Expect(ssb.Status.Conditions.GetCondition("Ready")).ToNot(BeNil())
 			Expect(ssb.Status.Conditions.IsTrueFor("Ready")).To(BeFalse())
+			Expect(ssb.Status.Conditions.GetCondition("Ready").Reason).To(Equal(compv1alpha1.ConditionReason("Invalid")))
 
 			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: ssb.Name, Namespace: ssb.Namespace}, suite)
 			Expect(err).ToNot(BeNil())
 		})
 	})
 
+	Context("Validates the references of the binding", func() {
+		reconcileBinding := func(profiles []compv1alpha1.NamedObjectReference) reconcile.Result {
+			ssb = &compv1alpha1.ScanSettingBinding{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "validated-references",
+					Namespace: common.GetComplianceOperatorNamespace(),
+				},
+				Profiles: profiles,
+				SettingsRef: &compv1alpha1.NamedObjectReference{
+					Name:     setting.Name,
+					Kind:     setting.Kind,
+					APIGroup: setting.APIVersion,
+				},
+			}
+			ssb.Status.SetConditionPending()
+			err := reconciler.Client.Create(context.TODO(), ssb)
+			Expect(err).To(BeNil())
+
+			res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: ssb.Namespace,
+					Name:      ssb.Name,
+				},
+			})
+			Expect(err).To(BeNil())
+
+			err = reconciler.Client.Get(context.TODO(), types.NamespacedName{
+				Namespace: ssb.Namespace,
+				Name:      ssb.Name,
+			}, ssb)
+			Expect(err).To(BeNil())
+			return res
+		}
+
+		It("accepts a binding whose references all exist", func() {
+			res := reconcileBinding([]compv1alpha1.NamedObjectReference{
+				{
+					Name:     profRhcosE8.Name,
+					Kind:     profRhcosE8.Kind,
+					APIGroup: profRhcosE8.APIVersion,
+				},
+				{
+					Name:     tpRhcosE8.Name,
+					Kind:     tpRhcosE8.Kind,
+					APIGroup: tpRhcosE8.APIVersion,
+				},
+			})
+			Expect(res.Requeue).To(BeFalse())
+			Expect(ssb.Status.Conditions.IsTrueFor("Ready")).To(BeTrue())
+		})
+
+		It("gets every referenced object once", func() {
+			gets := map[string]int{}
+			reconciler.Client = interceptor.NewClient(reconciler.Client.(runtimeclient.WithWatch), interceptor.Funcs{
+				Get: func(ctx context.Context, c runtimeclient.WithWatch, key runtimeclient.ObjectKey, obj runtimeclient.Object, opts ...runtimeclient.GetOption) error {
+					if u, ok := obj.(*unstructured.Unstructured); ok {
+						gets[u.GetKind()+"/"+key.Name]++
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			})
+			res := reconcileBinding([]compv1alpha1.NamedObjectReference{
+				{
+					Name:     profRhcosE8.Name,
+					Kind:     profRhcosE8.Kind,
+					APIGroup: profRhcosE8.APIVersion,
+				},
+			})
+			Expect(res.Requeue).To(BeFalse())
+			Expect(ssb.Status.Conditions.IsTrueFor("Ready")).To(BeTrue())
+			Expect(gets).To(HaveKeyWithValue("Profile/"+profRhcosE8.Name, 1))
+			Expect(gets).To(HaveKeyWithValue("ScanSetting/"+setting.Name, 1))
+		})
+
+		It("reports the missing TailoredProfile", func() {
+			recorder := record.NewFakeRecorder(10)
+			reconciler.Recorder = common.NewSafeRecorderFromRecorder(recorder)
+			res := reconcileBinding([]compv1alpha1.NamedObjectReference{
+				{
+					Name:     profRhcosE8.Name,
+					Kind:     profRhcosE8.Kind,
+					APIGroup: profRhcosE8.APIVersion,
+				},
+				{
+					Name:     "missing-tp",
+					Kind:     "TailoredProfile",
+					APIGroup: compv1alpha1.SchemeGroupVersion.String(),
+				},
+			})
+			Expect(res.Requeue).To(BeTrue())
+			Expect(ssb.Status.Phase).To(Equal(compv1alpha1.ScanSettingBindingPhaseInvalid))
+			cond := ssb.Status.Conditions.GetCondition("Ready")
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Reason).To(Equal(compv1alpha1.ConditionReason("Invalid")))
+			Expect(cond.Message).To(ContainSubstring("TailoredProfile missing-tp"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("NamedReferenceLookupError")))
+
+			err := reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: ssb.Name, Namespace: ssb.Namespace}, suite)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("reports the missing ScanSetting", func() {
+			recorder := record.NewFakeRecorder(10)
+			reconciler.Recorder = common.NewSafeRecorderFromRecorder(recorder)
+			ssb = &compv1alpha1.ScanSettingBinding{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "missing-setting",
+					Namespace: common.GetComplianceOperatorNamespace(),
+				},
+				Profiles: []compv1alpha1.NamedObjectReference{
+					{
+						Name:     profRhcosE8.Name,
+						Kind:     profRhcosE8.Kind,
+						APIGroup: profRhcosE8.APIVersion,
+					},
+				},
+				SettingsRef: &compv1alpha1.NamedObjectReference{
+					Name:     "missing-setting",
+					Kind:     "ScanSetting",
+					APIGroup: compv1alpha1.SchemeGroupVersion.String(),
+				},
+			}
+			ssb.Status.SetConditionPending()
+			Expect(reconciler.Client.Create(context.TODO(), ssb)).To(Succeed())
+
+			res, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: ssb.Namespace, Name: ssb.Name},
+			})
+			Expect(err).To(BeNil())
+			Expect(res.Requeue).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("NamedReferenceLookupError")))
+		})
+
+		It("rejects references of an unsupported kind", func() {
+			reconcileBinding([]compv1alpha1.NamedObjectReference{
+				{
+					Name:     setting.Name,
+					Kind:     setting.Kind,
+					APIGroup: setting.APIVersion,
+				},
+			})
+			cond := ssb.Status.Conditions.GetCondition("Ready")
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Reason).To(Equal(compv1alpha1.ConditionReason("Invalid")))
+			Expect(cond.Message).To(ContainSubstring("unsupported kind ScanSetting"))
+		})
+	})
+
 	Context("Waits if TailoredProfile isn't ready", func() {
 		JustBeforeEach(func() {
 			By("Setting the TP to PENDING")
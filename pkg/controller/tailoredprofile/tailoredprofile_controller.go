@@ -3,6 +3,9 @@ package tailoredprofile
 import (
 	"context"
 	"fmt"
+	"path"
+	"reflect"
+	"sort"
 	"strings"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -202,11 +205,11 @@ func (r *ReconcileTailoredProfile) Reconcile(ctx context.Context, request reconc
 			// don't need to set it
 			_, ok := anns[cmpv1alpha1.ProductTypeAnnotation]
 			if !ok {
-				if strings.HasSuffix(tpCopy.GetName(), "-node") {
-					anns[cmpv1alpha1.ProductTypeAnnotation] = string(cmpv1alpha1.ScanTypeNode)
-				} else {
-					anns[cmpv1alpha1.ProductTypeAnnotation] = string(cmpv1alpha1.ScanTypePlatform)
+				scanType, typeErr := r.getScanTypeFromRules(tpCopy)
+				if typeErr != nil {
+					return reconcile.Result{}, typeErr
 				}
+				anns[cmpv1alpha1.ProductTypeAnnotation] = string(scanType)
 				tpCopy.SetAnnotations(anns)
 			}
 			// This will trigger an update anyway
@@ -245,7 +248,22 @@ func (r *ReconcileTailoredProfile) Reconcile(ctx context.Context, request reconc
 
 	}
 
-	rules, ruleErr := r.getRulesFromSelections(instance, pb)
+	tailoring, expansions, expandErr := r.expandRulePatterns(instance, pb)
+	if expandErr != nil {
+		// Surface the error.
+		suerr := r.handleTailoredProfileStatusError(instance, expandErr)
+		return reconcile.Result{}, suerr
+	}
+	if !reflect.DeepEqual(expansions, instance.Status.ExpandedRulePatterns) {
+		tpCopy := instance.DeepCopy()
+		tpCopy.Status.ExpandedRulePatterns = expansions
+		if err := r.Client.Status().Update(context.TODO(), tpCopy); err != nil {
+			return reconcile.Result{}, err
+		}
+		instance = tpCopy
+	}
+
+	rules, ruleErr := r.getRulesFromSelections(tailoring, pb)
 	if ruleErr != nil && !common.IsRetriable(ruleErr) {
 		// Surface the error.
 		suerr := r.handleTailoredProfileStatusError(instance, ruleErr)
@@ -272,7 +290,7 @@ func (r *ReconcileTailoredProfile) Reconcile(ctx context.Context, request reconc
 	// Get tailored profile config map
 	tpcm := newTailoredProfileCM(instance)
 
-	tpcm.Data[tailoringFile], err = xccdf.TailoredProfileToXML(instance, p, pb, rules, variables)
+	tpcm.Data[tailoringFile], err = xccdf.TailoredProfileToXML(tailoring, p, pb, rules, variables)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -448,6 +466,10 @@ func (r *ReconcileTailoredProfile) getProfileInfoFromExtends(tp *cmpv1alpha1.Tai
 func (r *ReconcileTailoredProfile) getProfileBundleFromRulesOrVars(tp *cmpv1alpha1.TailoredProfile) (*cmpv1alpha1.ProfileBundle, error) {
 	var ruleToBeChecked *cmpv1alpha1.Rule
 	for _, selection := range append(tp.Spec.EnableRules, append(tp.Spec.DisableRules, tp.Spec.ManualRules...)...) {
+		// Patterns are expanded once we know the bundle
+		if isRulePattern(selection.Name) {
+			continue
+		}
 		rule := &cmpv1alpha1.Rule{}
 		ruleKey := types.NamespacedName{Name: selection.Name, Namespace: tp.Namespace}
 		geterr := r.Client.Get(context.TODO(), ruleKey, rule)
@@ -499,6 +521,119 @@ func (r *ReconcileTailoredProfile) getProfileBundleFromRulesOrVars(tp *cmpv1alph
 	return nil, common.NewNonRetriableCtrlError("Unable to get ProfileBundle from selected rules and variables")
 }
 
+// isRulePattern returns whether a rule selection is a glob pattern rather
+// than the name of a rule. Rule names are DNS names, so they can't contain
+// any of the pattern characters.
+func isRulePattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// expandRulePatterns returns a copy of the TailoredProfile where the rule
+// name patterns in the selections are replaced by the rules of the bundle
+// they match, along with what each pattern matched. Rules that are selected
+// by name, or by an earlier pattern, are not selected again.
+func (r *ReconcileTailoredProfile) expandRulePatterns(tp *cmpv1alpha1.TailoredProfile, pb *cmpv1alpha1.ProfileBundle) (*cmpv1alpha1.TailoredProfile, []cmpv1alpha1.RulePatternExpansion, error) {
+	selected := make(map[string]bool)
+	hasPatterns := false
+	for _, selection := range append(tp.Spec.EnableRules, append(tp.Spec.DisableRules, tp.Spec.ManualRules...)...) {
+		if isRulePattern(selection.Name) {
+			hasPatterns = true
+		} else {
+			selected[selection.Name] = true
+		}
+	}
+	if !hasPatterns {
+		return tp, nil, nil
+	}
+
+	ruleList := &cmpv1alpha1.RuleList{}
+	if err := r.Client.List(context.TODO(), ruleList, client.InNamespace(tp.Namespace)); err != nil {
+		return nil, nil, err
+	}
+	bundleRules := make([]string, 0, len(ruleList.Items))
+	for i := range ruleList.Items {
+		if isOwnedBy(&ruleList.Items[i], pb) {
+			bundleRules = append(bundleRules, ruleList.Items[i].Name)
+		}
+	}
+	sort.Strings(bundleRules)
+
+	allowEmpty := tp.GetAnnotations()[cmpv1alpha1.AllowEmptyRulePatternsAnnotation] == "true"
+	var expansions []cmpv1alpha1.RulePatternExpansion
+	expand := func(selections []cmpv1alpha1.RuleReferenceSpec) ([]cmpv1alpha1.RuleReferenceSpec, error) {
+		var expanded []cmpv1alpha1.RuleReferenceSpec
+		for _, selection := range selections {
+			if !isRulePattern(selection.Name) {
+				expanded = append(expanded, selection)
+				continue
+			}
+			expansion := cmpv1alpha1.RulePatternExpansion{Pattern: selection.Name}
+			for _, rule := range bundleRules {
+				matches, err := path.Match(selection.Name, rule)
+				if err != nil {
+					return nil, common.NewNonRetriableCtrlError("invalid rule pattern '%s': %w", selection.Name, err)
+				}
+				if !matches {
+					continue
+				}
+				expansion.Rules = append(expansion.Rules, rule)
+				if selected[rule] {
+					continue
+				}
+				selected[rule] = true
+				expanded = append(expanded, cmpv1alpha1.RuleReferenceSpec{Name: rule, Rationale: selection.Rationale, Severity: selection.Severity})
+			}
+			if len(expansion.Rules) == 0 && !allowEmpty {
+				return nil, common.NewNonRetriableCtrlError("rule pattern '%s' doesn't match any rule of ProfileBundle %s",
+					selection.Name, pb.GetName())
+			}
+			expansions = append(expansions, expansion)
+		}
+		return expanded, nil
+	}
+
+	tpCopy := tp.DeepCopy()
+	var err error
+	if tpCopy.Spec.EnableRules, err = expand(tp.Spec.EnableRules); err != nil {
+		return nil, nil, err
+	}
+	if tpCopy.Spec.DisableRules, err = expand(tp.Spec.DisableRules); err != nil {
+		return nil, nil, err
+	}
+	if tpCopy.Spec.ManualRules, err = expand(tp.Spec.ManualRules); err != nil {
+		return nil, nil, err
+	}
+	return tpCopy, expansions, nil
+}
+
+// getScanTypeFromRules returns the type of scan that the rules the tailored
+// profile enables need, going by their check type. Rule patterns and rules
+// that don't exist are skipped, they're validated later on. Tailored profiles
+// without any rule of a certain type get a platform scan, just like profiles
+// without a product type.
+func (r *ReconcileTailoredProfile) getScanTypeFromRules(tp *cmpv1alpha1.TailoredProfile) (cmpv1alpha1.ComplianceScanType, error) {
+	for _, selection := range append(tp.Spec.EnableRules, tp.Spec.ManualRules...) {
+		if isRulePattern(selection.Name) {
+			continue
+		}
+		rule := &cmpv1alpha1.Rule{}
+		ruleKey := types.NamespacedName{Name: selection.Name, Namespace: tp.Namespace}
+		if err := r.Client.Get(context.TODO(), ruleKey, rule); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return cmpv1alpha1.ScanTypePlatform, err
+		}
+		switch rule.CheckType {
+		case cmpv1alpha1.CheckTypeNode:
+			return cmpv1alpha1.ScanTypeNode, nil
+		case cmpv1alpha1.CheckTypePlatform:
+			return cmpv1alpha1.ScanTypePlatform, nil
+		}
+	}
+	return cmpv1alpha1.ScanTypePlatform, nil
+}
+
 func (r *ReconcileTailoredProfile) getRulesFromSelections(tp *cmpv1alpha1.TailoredProfile, pb *cmpv1alpha1.ProfileBundle) (map[string]*cmpv1alpha1.Rule, error) {
 	rules := make(map[string]*cmpv1alpha1.Rule, len(tp.Spec.EnableRules)+len(tp.Spec.DisableRules)+len(tp.Spec.ManualRules))
 
@@ -528,16 +663,20 @@ func (r *ReconcileTailoredProfile) getRulesFromSelections(tp *cmpv1alpha1.Tailor
 	return rules, nil
 }
 
+// getVariablesFromSelections fetches the variables the tailored profile sets
+// and validates their values. All the variables that don't exist are
+// reported at once, so they can be fixed before a scan uses the profile.
 func (r *ReconcileTailoredProfile) getVariablesFromSelections(tp *cmpv1alpha1.TailoredProfile, pb *cmpv1alpha1.ProfileBundle) ([]*cmpv1alpha1.Variable, error) {
 	variableList := []*cmpv1alpha1.Variable{}
+	missing := []string{}
 	for _, setValues := range tp.Spec.SetValues {
 		variable := &cmpv1alpha1.Variable{}
 		varKey := types.NamespacedName{Name: setValues.Name, Namespace: tp.Namespace}
 		err := r.Client.Get(context.TODO(), varKey, variable)
-		if err != nil {
-			if kerrors.IsNotFound(err) {
-				return nil, common.NewNonRetriableCtrlError("fetching variable: %w", err)
-			}
+		if kerrors.IsNotFound(err) {
+			missing = append(missing, setValues.Name)
+			continue
+		} else if err != nil {
 			return nil, err
 		}
 
@@ -555,6 +694,10 @@ func (r *ReconcileTailoredProfile) getVariablesFromSelections(tp *cmpv1alpha1.Ta
 
 		variableList = append(variableList, variable)
 	}
+	if len(missing) > 0 {
+		return nil, common.NewNonRetriableCtrlError("variables referenced in setValues not found: %s",
+			strings.Join(missing, ", "))
+	}
 	return variableList, nil
 }
 
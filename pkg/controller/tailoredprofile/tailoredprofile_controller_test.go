@@ -460,6 +460,106 @@ var _ = Describe("TailoredprofileController", func() {
 		})
 	})
 
+	When("selecting rules with a pattern", func() {
+		var tpName = "tailoring"
+		tpKey := types.NamespacedName{
+			Name:      tpName,
+			Namespace: namespace,
+		}
+		tpReq := reconcile.Request{NamespacedName: tpKey}
+
+		createTP := func(pattern string, annotations map[string]string) {
+			tp := &compv1alpha1.TailoredProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        tpName,
+					Namespace:   namespace,
+					Annotations: annotations,
+				},
+				Spec: compv1alpha1.TailoredProfileSpec{
+					Extends: profileName,
+					EnableRules: []compv1alpha1.RuleReferenceSpec{
+						{
+							Name:      pattern,
+							Rationale: "All of them",
+						},
+					},
+					DisableRules: []compv1alpha1.RuleReferenceSpec{
+						{
+							Name:      "rule-2",
+							Rationale: "Except this one",
+						},
+					},
+				},
+			}
+			createErr := r.Client.Create(ctx, tp)
+			Expect(createErr).To(BeNil())
+
+			By("Reconciling twice to set the ownership and process the TP")
+			_, err := r.Reconcile(context.TODO(), tpReq)
+			Expect(err).To(BeNil())
+			_, err = r.Reconcile(context.TODO(), tpReq)
+			Expect(err).To(BeNil())
+		}
+
+		It("expands the pattern to the rules of the bundle", func() {
+			createTP("rule-*", nil)
+
+			tp := &compv1alpha1.TailoredProfile{}
+			geterr := r.Client.Get(ctx, tpKey, tp)
+			Expect(geterr).To(BeNil())
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateReady))
+
+			By("Recording the rules the pattern matched")
+			// rules 5 to 9 belong to a different bundle
+			Expect(tp.Status.ExpandedRulePatterns).To(Equal([]compv1alpha1.RulePatternExpansion{
+				{
+					Pattern: "rule-*",
+					Rules:   []string{"rule-1", "rule-2", "rule-3", "rule-4"},
+				},
+			}))
+
+			cm := &corev1.ConfigMap{}
+			cmKey := types.NamespacedName{
+				Name:      tp.Status.OutputRef.Name,
+				Namespace: tp.Status.OutputRef.Namespace,
+			}
+			geterr = r.Client.Get(ctx, cmKey, cm)
+			Expect(geterr).To(BeNil())
+			data := cm.Data["tailoring.xml"]
+			Expect(data).To(ContainSubstring(`select idref="rule_1" selected="true"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_3" selected="true"`))
+			Expect(data).To(ContainSubstring(`select idref="rule_4" selected="true"`))
+			By("Keeping the rule that was selected by name out of the expansion")
+			Expect(data).To(ContainSubstring(`select idref="rule_2" selected="false"`))
+			Expect(data).ToNot(ContainSubstring(`select idref="rule_2" selected="true"`))
+			Expect(data).ToNot(ContainSubstring(`rule_5`))
+		})
+
+		It("reports an error if the pattern doesn't match any rule", func() {
+			createTP("nothing-*", nil)
+
+			tp := &compv1alpha1.TailoredProfile{}
+			geterr := r.Client.Get(ctx, tpKey, tp)
+			Expect(geterr).To(BeNil())
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
+			Expect(tp.Status.ErrorMessage).To(ContainSubstring("nothing-*"))
+		})
+
+		It("ignores patterns that don't match any rule if asked to", func() {
+			createTP("nothing-*", map[string]string{
+				compv1alpha1.AllowEmptyRulePatternsAnnotation: "true",
+			})
+
+			tp := &compv1alpha1.TailoredProfile{}
+			geterr := r.Client.Get(ctx, tpKey, tp)
+			Expect(geterr).To(BeNil())
+			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateReady))
+			Expect(tp.Status.ExpandedRulePatterns).To(Equal([]compv1alpha1.RulePatternExpansion{
+				{Pattern: "nothing-*"},
+			}))
+		})
+	})
+
 	When("Trying to reference an unexistent rule", func() {
 		var tpName = "tailoring"
 		BeforeEach(func() {
@@ -560,6 +660,13 @@ var _ = Describe("TailoredprofileController", func() {
 						{
 							Name: "unexistent",
 						},
+						{
+							Name:  "var-1",
+							Value: "1234",
+						},
+						{
+							Name: "also-unexistent",
+						},
 					},
 				},
 			}
@@ -591,6 +698,10 @@ var _ = Describe("TailoredprofileController", func() {
 			Expect(tp.Status.State).To(Equal(compv1alpha1.TailoredProfileStateError))
 			Expect(tp.Status.ErrorMessage).To(MatchRegexp(
 				`not found`))
+
+			By("Naming all the missing variables")
+			Expect(tp.Status.ErrorMessage).To(ContainSubstring("unexistent, also-unexistent"))
+			Expect(tp.Status.ErrorMessage).NotTo(ContainSubstring("var-1"))
 		})
 	})
 
@@ -1076,7 +1187,7 @@ var _ = Describe("TailoredprofileController", func() {
 				Expect(ownerRefs).To(HaveLen(1))
 				Expect(ownerRefs[0].Kind).To(Equal("ProfileBundle"))
 				Expect(tp.GetAnnotations()).NotTo(BeNil())
-				Expect(tp.GetAnnotations()[cmpv1alpha1.ProductTypeAnnotation]).To(Equal(string(compv1alpha1.ScanTypePlatform)))
+				Expect(tp.GetAnnotations()[cmpv1alpha1.ProductTypeAnnotation]).To(Equal(string(compv1alpha1.ScanTypeNode)))
 
 				By("Reconciling a second time (setting status)")
 				_, err = r.Reconcile(context.TODO(), tpReq)
@@ -1155,7 +1266,7 @@ var _ = Describe("TailoredprofileController", func() {
 				Expect(ownerRefs).To(HaveLen(1))
 				Expect(ownerRefs[0].Kind).To(Equal("ProfileBundle"))
 				Expect(tp.GetAnnotations()).NotTo(BeNil())
-				Expect(tp.GetAnnotations()[cmpv1alpha1.ProductTypeAnnotation]).To(Equal(string(compv1alpha1.ScanTypePlatform)))
+				Expect(tp.GetAnnotations()[cmpv1alpha1.ProductTypeAnnotation]).To(Equal(string(compv1alpha1.ScanTypeNode)))
 
 				By("Reconciling a second time (setting status)")
 				_, err = r.Reconcile(context.TODO(), tpReq)
@@ -107,6 +107,11 @@ func ParseBundle(contentDom *xmlquery.Node, pb *cmpv1alpha1.ProfileBundle, pcfg
 					return fmt.Errorf("unexpected type")
 				}
 
+				// The external reference is set by the admins rather than
+				// by the content, so it must survive the update
+				if ref, ok := foundRule.Annotations[cmpv1alpha1.RuleExternalRefAnnotationKey]; ok {
+					updatedRule.Annotations[cmpv1alpha1.RuleExternalRefAnnotationKey] = ref
+				}
 				foundRule.Annotations = updatedRule.Annotations
 				// if the check type has changed, add an annotation to the rule
 				// to indicate that the rule needs to be checked in TailoredProfile validation
@@ -359,6 +364,7 @@ func parseProfileFromNode(profileRoot *xmlquery.Node, pb *cmpv1alpha1.ProfileBun
 			}
 			selectedvalues = append(selectedvalues, cmpv1alpha1.ProfileValue(idref))
 		}
+		log.V(1).Info("Parsed profile", "id", id, "rules", len(selectedrules), "values", len(selectedvalues))
 
 		p := cmpv1alpha1.Profile{
 			TypeMeta: metav1.TypeMeta{
@@ -498,6 +504,8 @@ func ParseVariablesAndDo(contentDom *xmlquery.Node, pb *cmpv1alpha1.ProfileBundl
 				continue
 			}
 
+			log.V(1).Info("Parsed variable", "id", id, "type", v.Type, "value", v.Value, "selections", len(v.Selections))
+
 			annotateWithNonce(&v, nonce)
 
 			err = action(&v)
@@ -673,6 +681,8 @@ func ParseRulesAndDo(contentDom *xmlquery.Node, stdParser *referenceParser, pb *
 					annotations[cmpv1alpha1.RuleProfileAnnotationKey] = strings.Join(profileList, ",")
 				}
 			}
+			log.V(1).Info("Parsed rule", "id", id, "severity", severity, "fixes", len(fixes),
+				"profiles", profileList, "variables", valuesRendered)
 
 			p := cmpv1alpha1.Rule{
 				TypeMeta: metav1.TypeMeta{
@@ -723,14 +733,7 @@ func ParseRulesAndDo(contentDom *xmlquery.Node, stdParser *referenceParser, pb *
 			if instructions != "" {
 				p.Instructions = instructions
 			}
-			// Parse check type
-			if len(defs) == 0 {
-				p.CheckType = cmpv1alpha1.CheckTypeNone
-			} else if utils.RuleHasApiObjectWarning(ruleObj) {
-				p.CheckType = cmpv1alpha1.CheckTypePlatform
-			} else {
-				p.CheckType = cmpv1alpha1.CheckTypeNode
-			}
+			p.CheckType = utils.GetRuleCheckType(ruleObj, len(defs) > 0)
 			if len(fixes) > 0 {
 				p.AvailableFixes = fixes
 			}
@@ -752,6 +755,9 @@ func ParseRulesAndDo(contentDom *xmlquery.Node, stdParser *referenceParser, pb *
 	errchan := make(chan error)
 	waitchan := make(chan struct{})
 	ruleObjs := xmlquery.Find(contentDom, "//xccdf-1.2:Rule")
+	if duplicates := utils.FindDuplicateRuleIDs(contentDom); len(duplicates) > 0 {
+		log.Info(utils.DuplicateRuleIDsMessage, "ids", duplicates)
+	}
 	nworkers := 5
 	wg.Add(5)
 	for i := 0; i < nworkers; i++ {
@@ -759,7 +765,15 @@ func ParseRulesAndDo(contentDom *xmlquery.Node, stdParser *referenceParser, pb *
 	}
 
 	go func() {
+		// The rules are named after their IDs, so only the first rule with
+		// a given ID is parsed, like the scans do
+		seen := make(map[string]bool)
 		for _, varObj := range ruleObjs {
+			id := varObj.SelectAttr("id")
+			if id != "" && seen[id] {
+				continue
+			}
+			seen[id] = true
 			rulechan <- varObj
 		}
 		close(rulechan)
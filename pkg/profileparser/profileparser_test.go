@@ -320,12 +320,19 @@ var _ = Describe("Testing ParseBundle", func() {
 
 		When("Fetching the rule", func() {
 			var fetchedRule *cmpv1alpha1.Rule
+			BeforeEach(func() {
+				ruleChangedSeverityPre.Annotations[cmpv1alpha1.RuleExternalRefAnnotationKey] = "TICKET-123"
+				Expect(client.Update(context.TODO(), ruleChangedSeverityPre)).To(Succeed())
+			})
 			JustBeforeEach(func() {
 				fetchedRule = &cmpv1alpha1.Rule{}
 				key := types.NamespacedName{Namespace: testNamespace, Name: chronydMaxpollRuleName}
 				err := client.Get(context.TODO(), key, fetchedRule)
 				Expect(err).To(BeNil())
 			})
+			It("Keeps the external reference set on the rule", func() {
+				Expect(fetchedRule.Annotations).To(HaveKeyWithValue(cmpv1alpha1.RuleExternalRefAnnotationKey, "TICKET-123"))
+			})
 			It("Detects that a rule has changed severity", func() {
 				Expect(fetchedRule.Severity).ToNot(BeEquivalentTo(ruleChangedSeverityPre.Severity))
 			})
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"sort"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// CheckResultDrift lists the names of the ComplianceCheckResults that changed
+// between two runs of the same scans
+type CheckResultDrift struct {
+	// Checks that fail now but didn't before
+	NewlyFailing []string
+	// Checks that pass now but didn't before
+	NewlyPassing []string
+	// Checks that weren't there before
+	NewlyAppearing []string
+	// Checks that were there before but aren't anymore
+	Removed []string
+}
+
+// IsEmpty returns whether nothing changed between the two runs
+func (d *CheckResultDrift) IsEmpty() bool {
+	return len(d.NewlyFailing) == 0 && len(d.NewlyPassing) == 0 &&
+		len(d.NewlyAppearing) == 0 && len(d.Removed) == 0
+}
+
+// DiffCheckResults compares the check results of a previous run with the
+// current ones. The checks are matched by name, which is stable across runs
+// of the same scan. Checks that appear in the current run are only reported
+// as appearing, whatever their status. The names in each list are sorted.
+func DiffCheckResults(prior, current []compv1alpha1.ComplianceCheckResult) CheckResultDrift {
+	drift := CheckResultDrift{}
+
+	priorStatus := make(map[string]compv1alpha1.ComplianceCheckStatus, len(prior))
+	for i := range prior {
+		priorStatus[prior[i].Name] = prior[i].Status
+	}
+
+	seen := make(map[string]bool, len(current))
+	for i := range current {
+		check := &current[i]
+		seen[check.Name] = true
+
+		before, existed := priorStatus[check.Name]
+		switch {
+		case !existed:
+			drift.NewlyAppearing = append(drift.NewlyAppearing, check.Name)
+		case before == check.Status:
+			continue
+		case check.Status == compv1alpha1.CheckResultFail:
+			drift.NewlyFailing = append(drift.NewlyFailing, check.Name)
+		case check.Status == compv1alpha1.CheckResultPass:
+			drift.NewlyPassing = append(drift.NewlyPassing, check.Name)
+		}
+	}
+
+	for i := range prior {
+		if !seen[prior[i].Name] {
+			drift.Removed = append(drift.Removed, prior[i].Name)
+		}
+	}
+
+	sort.Strings(drift.NewlyFailing)
+	sort.Strings(drift.NewlyPassing)
+	sort.Strings(drift.NewlyAppearing)
+	sort.Strings(drift.Removed)
+	return drift
+}
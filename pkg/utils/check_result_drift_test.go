@@ -0,0 +1,74 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Diffing check results", func() {
+	// checks turns name/status pairs into check results
+	checks := func(pairs ...string) []compv1alpha1.ComplianceCheckResult {
+		results := make([]compv1alpha1.ComplianceCheckResult, 0, len(pairs)/2)
+		for i := 0; i+1 < len(pairs); i += 2 {
+			results = append(results, compv1alpha1.ComplianceCheckResult{
+				ObjectMeta: metav1.ObjectMeta{Name: pairs[i]},
+				Status:     compv1alpha1.ComplianceCheckStatus(pairs[i+1]),
+			})
+		}
+		return results
+	}
+
+	DescribeTable("reports what changed",
+		func(prior, current []compv1alpha1.ComplianceCheckResult, expected CheckResultDrift) {
+			drift := DiffCheckResults(prior, current)
+			Expect(drift).To(Equal(expected))
+			Expect(drift.IsEmpty()).To(Equal(expected.NewlyFailing == nil && expected.NewlyPassing == nil &&
+				expected.NewlyAppearing == nil && expected.Removed == nil))
+		},
+		Entry("nothing changed",
+			checks("a", "PASS", "b", "FAIL"),
+			checks("b", "FAIL", "a", "PASS"),
+			CheckResultDrift{}),
+		Entry("a check started failing",
+			checks("a", "PASS", "b", "PASS"),
+			checks("a", "PASS", "b", "FAIL"),
+			CheckResultDrift{NewlyFailing: []string{"b"}}),
+		Entry("a check got fixed",
+			checks("a", "FAIL"),
+			checks("a", "PASS"),
+			CheckResultDrift{NewlyPassing: []string{"a"}}),
+		Entry("an erroring check now fails and another passes",
+			checks("a", "ERROR", "b", "ERROR"),
+			checks("a", "FAIL", "b", "PASS"),
+			CheckResultDrift{NewlyFailing: []string{"a"}, NewlyPassing: []string{"b"}}),
+		Entry("a passing check now errors",
+			checks("a", "PASS"),
+			checks("a", "ERROR"),
+			CheckResultDrift{}),
+		Entry("checks were added",
+			checks("a", "PASS"),
+			checks("c", "FAIL", "a", "PASS", "b", "PASS"),
+			CheckResultDrift{NewlyAppearing: []string{"b", "c"}}),
+		Entry("checks were removed",
+			checks("a", "PASS", "c", "FAIL", "b", "FAIL"),
+			checks("a", "PASS"),
+			CheckResultDrift{Removed: []string{"b", "c"}}),
+		Entry("no prior run",
+			nil,
+			checks("a", "FAIL"),
+			CheckResultDrift{NewlyAppearing: []string{"a"}}),
+		Entry("everything at once",
+			checks("pass-to-fail", "PASS", "fail-to-pass", "FAIL", "gone", "PASS", "same", "FAIL"),
+			checks("same", "FAIL", "fail-to-pass", "PASS", "pass-to-fail", "FAIL", "new", "MANUAL"),
+			CheckResultDrift{
+				NewlyFailing:   []string{"pass-to-fail"},
+				NewlyPassing:   []string{"fail-to-pass"},
+				NewlyAppearing: []string{"new"},
+				Removed:        []string{"gone"},
+			}),
+	)
+})
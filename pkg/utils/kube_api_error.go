@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"strings"
+)
+
+// KubeAPIErrorMarkerPrefix starts the comment that's saved in place of a
+// resource the API server couldn't return. The content matches it as is,
+// e.g. the kubeadmin_removed check looks for "# kube-api-error=NotFound", so
+// it must not change.
+const KubeAPIErrorMarkerPrefix = "# kube-api-error="
+
+// NewKubeAPIErrorMarker returns the marker to save in place of a resource
+// that couldn't be fetched because of the API error with the given reason
+func NewKubeAPIErrorMarker(reason string) []byte {
+	return []byte(KubeAPIErrorMarkerPrefix + reason)
+}
+
+// ParseKubeAPIErrorMarker returns the reason of the API error if data is an
+// error marker, and false if it's a resource. Resources that merely contain
+// the marker text somewhere aren't markers, the marker must be the whole file.
+func ParseKubeAPIErrorMarker(data []byte) (string, bool) {
+	trimmed := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(trimmed, KubeAPIErrorMarkerPrefix) {
+		return "", false
+	}
+	reason := strings.TrimPrefix(trimmed, KubeAPIErrorMarkerPrefix)
+	if reason == "" || strings.ContainsAny(reason, " \t\n") {
+		return "", false
+	}
+	return reason, true
+}
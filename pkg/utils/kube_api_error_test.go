@@ -0,0 +1,35 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Kube API error markers", func() {
+	It("writes the marker the content matches", func() {
+		Expect(NewKubeAPIErrorMarker("NotFound")).To(Equal([]byte("# kube-api-error=NotFound")))
+	})
+
+	It("parses the markers it writes", func() {
+		reason, ok := ParseKubeAPIErrorMarker(NewKubeAPIErrorMarker("NotFound"))
+		Expect(ok).To(BeTrue())
+		Expect(reason).To(Equal("NotFound"))
+	})
+
+	table.DescribeTable("telling markers from resources",
+		func(data string, expectedReason string, expectedMarker bool) {
+			reason, ok := ParseKubeAPIErrorMarker([]byte(data))
+			Expect(ok).To(Equal(expectedMarker))
+			Expect(reason).To(Equal(expectedReason))
+		},
+		table.Entry("marker", "# kube-api-error=NotFound", "NotFound", true),
+		table.Entry("marker with a trailing newline", "# kube-api-error=Forbidden\n", "Forbidden", true),
+		table.Entry("empty file", "", "", false),
+		table.Entry("resource containing the marker",
+			`{"apiVersion":"v1","kind":"ConfigMap","data":{"note":"# kube-api-error=NotFound"}}`, "", false),
+		table.Entry("YAML resource with the marker in a comment",
+			"# kube-api-error=NotFound\napiVersion: v1\nkind: ConfigMap\n", "", false),
+		table.Entry("marker without a reason", "# kube-api-error=", "", false),
+	)
+})
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// How often WithPausedPool checks whether the pool settled after it was
+// un-paused
+var poolSettlePollInterval = 10 * time.Second
+
+// How long un-pausing a pool may take. The pool is un-paused with its own
+// context, so that a cancelled caller doesn't leave the pool paused.
+const poolUnpauseTimeout = 30 * time.Second
+
+// WithPausedPool pauses the MachineConfigPool called poolName, runs fn and
+// un-pauses the pool again, even if fn fails. Pausing the pool makes all the
+// changes fn makes to it get rolled out to the nodes at once. If fn succeeds,
+// it then waits up to timeout for the pool to finish updating the nodes.
+// Pools that were already paused are left paused, since whoever paused them
+// is expected to un-pause them.
+func WithPausedPool(ctx context.Context, client runtimeclient.Client, poolName string, timeout time.Duration, fn func() error) error {
+	paused, err := SetMcfgPoolPaused(ctx, client, poolName, true)
+	if err != nil {
+		return fmt.Errorf("couldn't pause pool %s: %w", poolName, err)
+	}
+	if !paused {
+		return fn()
+	}
+
+	fnErr := fn()
+	unpauseCtx, cancel := context.WithTimeout(context.Background(), poolUnpauseTimeout)
+	defer cancel()
+	if _, err := SetMcfgPoolPaused(unpauseCtx, client, poolName, false); err != nil {
+		return errors.Join(fnErr, fmt.Errorf("couldn't un-pause pool %s: %w", poolName, err))
+	}
+	if fnErr != nil {
+		return fnErr
+	}
+
+	return waitForPoolToSettle(ctx, client, poolName, timeout)
+}
+
+// SetMcfgPoolPaused pauses or un-pauses the MachineConfigPool called poolName,
+// retrying on conflicts, and returns whether the pool changed. A pool that
+// already was in the requested state is left alone.
+func SetMcfgPoolPaused(ctx context.Context, client runtimeclient.Client, poolName string, paused bool) (bool, error) {
+	changed := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool := &mcfgv1.MachineConfigPool{}
+		if err := client.Get(ctx, types.NamespacedName{Name: poolName}, pool); err != nil {
+			return err
+		}
+		if pool.Spec.Paused == paused {
+			changed = false
+			return nil
+		}
+		pool.Spec.Paused = paused
+		if err := client.Update(ctx, pool); err != nil {
+			return err
+		}
+		changed = true
+		return nil
+	})
+	return changed, err
+}
+
+// waitForPoolToSettle waits until the pool has observed its latest spec and
+// all of its machines are updated
+func waitForPoolToSettle(ctx context.Context, client runtimeclient.Client, poolName string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, poolSettlePollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		pool := &mcfgv1.MachineConfigPool{}
+		if err := client.Get(ctx, types.NamespacedName{Name: poolName}, pool); err != nil {
+			return false, err
+		}
+		return poolIsSettled(pool), nil
+	})
+	if err != nil {
+		return fmt.Errorf("pool %s didn't finish updating: %w", poolName, err)
+	}
+	return nil
+}
+
+func poolIsSettled(pool *mcfgv1.MachineConfigPool) bool {
+	return pool.Status.ObservedGeneration >= pool.Generation &&
+		pool.Status.UpdatedMachineCount == pool.Status.MachineCount &&
+		mcfgv1.IsMachineConfigPoolConditionTrue(pool.Status.Conditions, mcfgv1.MachineConfigPoolUpdated)
+}
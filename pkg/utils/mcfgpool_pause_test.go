@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	mcfgapi "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("Running changes with a paused pool", func() {
+	var (
+		c                client.Client
+		pool             *mcfgv1.MachineConfigPool
+		origPollInterval time.Duration
+	)
+
+	getPool := func() *mcfgv1.MachineConfigPool {
+		found := &mcfgv1.MachineConfigPool{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: "worker"}, found)).To(Succeed())
+		return found
+	}
+
+	BeforeEach(func() {
+		origPollInterval = poolSettlePollInterval
+		poolSettlePollInterval = 10 * time.Millisecond
+		pool = &mcfgv1.MachineConfigPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+			Status: mcfgv1.MachineConfigPoolStatus{
+				MachineCount:        3,
+				UpdatedMachineCount: 3,
+				Conditions: []mcfgv1.MachineConfigPoolCondition{
+					{Type: mcfgv1.MachineConfigPoolUpdated, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		poolSettlePollInterval = origPollInterval
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(mcfgapi.Install(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(pool).Build()
+	})
+
+	It("pauses the pool while running the changes and waits for it to settle", func() {
+		err := WithPausedPool(context.TODO(), c, "worker", time.Second, func() error {
+			Expect(getPool().Spec.Paused).To(BeTrue())
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(getPool().Spec.Paused).To(BeFalse())
+	})
+
+	It("un-pauses the pool even if the changes fail", func() {
+		changeErr := errors.New("couldn't apply the remediation")
+		err := WithPausedPool(context.TODO(), c, "worker", time.Second, func() error {
+			return changeErr
+		})
+		Expect(err).To(MatchError(changeErr))
+		Expect(getPool().Spec.Paused).To(BeFalse())
+	})
+
+	It("un-pauses the pool even if the context is cancelled", func() {
+		// The fake client doesn't care about cancelled contexts, the API
+		// server would
+		c = interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, wc client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				return wc.Get(ctx, key, obj, opts...)
+			},
+			Update: func(ctx context.Context, wc client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				return wc.Update(ctx, obj, opts...)
+			},
+		})
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		err := WithPausedPool(ctx, c, "worker", time.Second, func() error {
+			cancel()
+			return nil
+		})
+		// Waiting for the pool to settle is given up on, though
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(getPool().Spec.Paused).To(BeFalse())
+	})
+
+	It("retries pausing the pool on conflicts", func() {
+		conflicts := 0
+		c = interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+			Update: func(ctx context.Context, wc client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if conflicts == 0 {
+					conflicts++
+					return kerrors.NewConflict(schema.GroupResource{Resource: "machineconfigpools"}, obj.GetName(), errors.New("the object has been modified"))
+				}
+				return wc.Update(ctx, obj, opts...)
+			},
+		})
+		err := WithPausedPool(context.TODO(), c, "worker", time.Second, func() error {
+			Expect(getPool().Spec.Paused).To(BeTrue())
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(conflicts).To(Equal(1))
+		Expect(getPool().Spec.Paused).To(BeFalse())
+	})
+
+	When("the pool doesn't finish updating", func() {
+		BeforeEach(func() {
+			pool.Status.UpdatedMachineCount = 1
+		})
+
+		It("times out waiting for it", func() {
+			err := WithPausedPool(context.TODO(), c, "worker", 50*time.Millisecond, func() error {
+				return nil
+			})
+			Expect(err).To(MatchError(ContainSubstring("pool worker didn't finish updating")))
+			Expect(getPool().Spec.Paused).To(BeFalse())
+		})
+	})
+
+	When("the pool was already paused", func() {
+		BeforeEach(func() {
+			pool.Spec.Paused = true
+		})
+
+		It("leaves it paused", func() {
+			ran := false
+			err := WithPausedPool(context.TODO(), c, "worker", time.Second, func() error {
+				ran = true
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(ran).To(BeTrue())
+			Expect(getPool().Spec.Paused).To(BeTrue())
+		})
+	})
+})
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"net/url"
@@ -33,6 +34,7 @@ const (
 	ovalCheckPrefix      = "oval:ssg-"
 	objValuePrefix       = "oval:ssg-variable"
 	ovalCheckType        = "http://oval.mitre.org/XMLSchema/oval-definitions-5"
+	xccdfRoleUnscored    = "unscored"
 	//index to trim `{{`and`}}`
 	trimStartIndex = 2
 	trimEndIndex   = 2
@@ -68,6 +70,9 @@ const (
 	dumpLocationClass        = "ocp-dump-location"
 	filterTypeClass          = "ocp-api-filter"
 	filteredEndpointClass    = "filtered"
+	// Marks endpoints that are expected to be absent on some clusters, so
+	// failing to fetch them doesn't produce a scan warning
+	suppressWarningClass = "ocp-suppress-warning"
 )
 
 type ParseResult struct {
@@ -156,7 +161,7 @@ func warningHasSuppressTag(in *xmlquery.Node) bool {
 	codeNodes := in.SelectElements("//html:code")
 
 	for _, codeNode := range codeNodes {
-		if codeNode.SelectAttr("class") == "ocp-suppress-warning" {
+		if codeNode.SelectAttr("class") == suppressWarningClass {
 			return true
 		}
 	}
@@ -167,51 +172,101 @@ func warningHasSuppressTag(in *xmlquery.Node) bool {
 type NodeByIdHashTable map[string]*xmlquery.Node
 type nodeByIdHashVariablesTable map[string][]string
 
-func newByIdHashTable(nodes []*xmlquery.Node) NodeByIdHashTable {
+// newByIdHashTable indexes the nodes by their ID. If several nodes share an
+// ID, the first one wins and the ID is returned in the list of duplicates, in
+// the order in which the IDs were first repeated.
+func newByIdHashTable(nodes []*xmlquery.Node) (NodeByIdHashTable, []string) {
 	table := make(NodeByIdHashTable)
+	duplicates := []string{}
+	reported := make(map[string]bool)
 	for i := range nodes {
 		ruleDefinition := nodes[i]
 		ruleId := ruleDefinition.SelectAttr("id")
 
+		if _, found := table[ruleId]; found {
+			if !reported[ruleId] {
+				duplicates = append(duplicates, ruleId)
+				reported[ruleId] = true
+			}
+			continue
+		}
 		table[ruleId] = ruleDefinition
 	}
 
-	return table
+	return table, duplicates
 }
 
-func newHashTableFromRootAndQuery(dsDom *xmlquery.Node, root, query string) NodeByIdHashTable {
+func newHashTableFromRootAndQuery(dsDom *xmlquery.Node, root, query string) (NodeByIdHashTable, []string) {
 	benchmarkDom := dsDom.SelectElement(root)
 	rules := benchmarkDom.SelectElements(query)
 	return newByIdHashTable(rules)
 }
 
+// newFilteredHashTableFromRootAndQuery works like newHashTableFromRootAndQuery,
+// but only indexes the nodes whose ID is in ids
+func newFilteredHashTableFromRootAndQuery(dsDom *xmlquery.Node, root, query string, ids map[string]bool) NodeByIdHashTable {
+	nodes := dsDom.SelectElement(root).SelectElements(query)
+	filtered := make([]*xmlquery.Node, 0, len(ids))
+	for i := range nodes {
+		if ids[nodes[i].SelectAttr("id")] {
+			filtered = append(filtered, nodes[i])
+		}
+	}
+	table, _ := newByIdHashTable(filtered)
+	return table
+}
+
 func newRuleHashTable(dsDom *xmlquery.Node) NodeByIdHashTable {
-	return newHashTableFromRootAndQuery(dsDom, "//ds:component/xccdf-1.2:Benchmark", "//xccdf-1.2:Rule")
+	table, _ := newHashTableFromRootAndQuery(dsDom, "//ds:component/xccdf-1.2:Benchmark", "//xccdf-1.2:Rule")
+	return table
+}
+
+// DuplicateRuleIDsMessage is logged along with the IDs FindDuplicateRuleIDs
+// returns, wherever the content is read
+const DuplicateRuleIDsMessage = "WARNING: The content has several rules with the same ID, only the first one of each is used"
+
+// FindDuplicateRuleIDs returns the IDs that more than one rule of the data
+// stream has. Only the first of those rules is used, so this points at a bug
+// in the content.
+func FindDuplicateRuleIDs(dsDom *xmlquery.Node) []string {
+	_, duplicates := newByIdHashTable(xmlquery.Find(dsDom, "//xccdf-1.2:Rule"))
+	return duplicates
 }
 
 func NewOcilQuestionTable(dsDom *xmlquery.Node) NodeByIdHashTable {
-	return newHashTableFromRootAndQuery(dsDom, "//ds:component/ocil:ocil", "//ocil:boolean_question")
+	table, _ := newHashTableFromRootAndQuery(dsDom, "//ds:component/ocil:ocil", "//ocil:boolean_question")
+	return table
 }
 
 func NewProfileTable(dsDom *xmlquery.Node) NodeByIdHashTable {
-	return newHashTableFromRootAndQuery(dsDom, "//ds:component/xccdf-1.2:Benchmark", "//xccdf-1.2:Profile")
+	table, _ := newHashTableFromRootAndQuery(dsDom, "//ds:component/xccdf-1.2:Benchmark", "//xccdf-1.2:Profile")
+	return table
 }
 
 func newStateHashTable(dsDom *xmlquery.Node) NodeByIdHashTable {
-	return newHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:states", "*")
+	table, _ := newHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:states", "*")
+	return table
 }
 
 func newObjHashTable(dsDom *xmlquery.Node) NodeByIdHashTable {
-	return newHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:objects", "*")
+	table, _ := newHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:objects", "*")
+	return table
 }
 
 func NewDefHashTable(dsDom *xmlquery.Node) NodeByIdHashTable {
-	return newHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:definitions", "*")
+	table, _ := newHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:definitions", "*")
+	return table
 }
 
 func newValueListTable(dsDom *xmlquery.Node, statesTable, objectsTable NodeByIdHashTable) nodeByIdHashVariablesTable {
 	root := "//ds:component/oval-def:oval_definitions/oval-def:tests"
 	testsDom := dsDom.SelectElement(root).SelectElements("*")
+	return newValueListTableFromTests(testsDom, statesTable, objectsTable)
+}
+
+// newValueListTableFromTests maps the IDs of the given OVAL tests to the
+// variables their states and objects use
+func newValueListTableFromTests(testsDom []*xmlquery.Node, statesTable, objectsTable NodeByIdHashTable) nodeByIdHashVariablesTable {
 	table := make(nodeByIdHashVariablesTable)
 
 	for i := range testsDom {
@@ -328,21 +383,28 @@ func GetRuleProfile(rule *xmlquery.Node, profileTable NodeByIdHashTable) NodeByI
 	return ruleProfile
 }
 
-func GetRuleOvalTest(rule *xmlquery.Node, defTable NodeByIdHashTable) NodeByIdHashTable {
-	var ovalRefEl *xmlquery.Node
-	testList := make(map[string]*xmlquery.Node)
+// getRuleOvalDefinitionID returns the ID of the OVAL definition that checks
+// the rule, or an empty string if the rule has no OVAL check
+func getRuleOvalDefinitionID(rule *xmlquery.Node) string {
 	for _, check := range rule.SelectElements("//xccdf-1.2:check") {
 		if check.SelectAttr("system") == ovalCheckType {
-			ovalRefEl = check.SelectElement("xccdf-1.2:check-content-ref")
-			break
+			ovalRefEl := check.SelectElement("xccdf-1.2:check-content-ref")
+			if ovalRefEl == nil {
+				return ""
+			}
+			return strings.TrimSpace(ovalRefEl.SelectAttr("name"))
 		}
 	}
+	return ""
+}
 
-	if ovalRefEl == nil {
+func GetRuleOvalTest(rule *xmlquery.Node, defTable NodeByIdHashTable) NodeByIdHashTable {
+	testList := make(map[string]*xmlquery.Node)
+	ovalCheckName := getRuleOvalDefinitionID(rule)
+	if ovalCheckName == "" {
 		return testList
 	}
 
-	ovalCheckName := strings.TrimSpace(ovalRefEl.SelectAttr("name"))
 	ovalTest, ok := defTable[ovalCheckName]
 	if !ok {
 		return testList
@@ -487,8 +549,48 @@ func ParseContent(dsReader io.Reader) (*xmlquery.Node, error) {
 	return dsDom, nil
 }
 
+// ErrInvalidDataStream is returned when the content isn't a usable SCAP
+// data stream, for instance because the file was truncated
+var ErrInvalidDataStream = errors.New("invalid or truncated datastream")
+
+// ParseDataStream parses the DataStream like ParseContent, but also checks
+// that the document is a data stream with an XCCDF benchmark
+func ParseDataStream(dsReader io.Reader) (*xmlquery.Node, error) {
+	dsDom, err := ParseContent(dsReader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDataStream, err)
+	}
+	if err := ValidateDataStream(dsDom); err != nil {
+		return nil, err
+	}
+	return dsDom, nil
+}
+
+// ValidateDataStream checks that the parsed document has the elements the
+// operator reads from a data stream. Without them, looking up rules and
+// profiles would silently find nothing.
+func ValidateDataStream(dsDom *xmlquery.Node) error {
+	if xmlquery.FindOne(dsDom, "//ds:component") == nil {
+		return fmt.Errorf("%w: no ds:component element found", ErrInvalidDataStream)
+	}
+	if xmlquery.FindOne(dsDom, "//ds:component/xccdf-1.2:Benchmark") == nil {
+		return fmt.Errorf("%w: no XCCDF Benchmark found", ErrInvalidDataStream)
+	}
+	return nil
+}
+
 func ParseResultsFromContentAndXccdf(scheme *runtime.Scheme, scanName string, namespace string,
 	dsDom *xmlquery.Node, resultsReader io.Reader, manualRules []string) ([]*ParseResult, error) {
+	return ParseSelectedResultsFromContentAndXccdf(scheme, scanName, namespace, dsDom, resultsReader, manualRules, nil)
+}
+
+// ParseSelectedResultsFromContentAndXccdf works like ParseResultsFromContentAndXccdf,
+// but only looks at the results of the rules in selectedRules, typically the
+// rules the scanned profile selects. Unselected rules never produce a check
+// result, so skipping them up front saves looking up their definitions,
+// instructions and remediations. A nil selectedRules looks at every result.
+func ParseSelectedResultsFromContentAndXccdf(scheme *runtime.Scheme, scanName string, namespace string,
+	dsDom *xmlquery.Node, resultsReader io.Reader, manualRules []string, selectedRules map[string]bool) ([]*ParseResult, error) {
 
 	resultsDom, err := xmlquery.Parse(resultsReader)
 	if err != nil {
@@ -501,52 +603,269 @@ func ParseResultsFromContentAndXccdf(scheme *runtime.Scheme, scanName string, na
 		valuesList[strings.TrimPrefix(codeNode.SelectAttr("idref"), valuePrefix)] = codeNode.InnerText()
 	}
 
-	ruleTable := newRuleHashTable(dsDom)
-	questionsTable := NewOcilQuestionTable(dsDom)
-	statesTable := newStateHashTable(dsDom)
-	objsTable := newObjHashTable(dsDom)
-	defTable := NewDefHashTable(dsDom)
-	ovalTestVarTable := newValueListTable(dsDom, statesTable, objsTable)
+	parser := newResultParser(scheme, scanName, namespace, dsDom, manualRules, selectedRules)
+	parser.valuesList = valuesList
 	results := resultsDom.SelectElements("//rule-result")
 	parsedResults := make([]*ParseResult, 0)
 	var remErrs string
 
 	for i := range results {
-		result := results[i]
-		ruleIDRef := result.SelectAttr("idref")
-		if ruleIDRef == "" {
-			continue
+		pr, err := parser.parse(results[i])
+		if err != nil {
+			remErrs = err.Error()
 		}
-
-		resultRule := ruleTable[ruleIDRef]
-		if resultRule == nil {
-			continue
+		if pr != nil {
+			parsedResults = append(parsedResults, pr)
 		}
+	}
+	if remErrs != "" {
+		return parsedResults, errors.New(remErrs)
+	}
+	return parsedResults, nil
 
-		instructions, _ := GetInstructionsForRule(resultRule, questionsTable, valuesList)
-		ruleValues := getValueListUsedForRule(resultRule, ovalTestVarTable, defTable, questionsTable, valuesList)
-		resCheck, err := newComplianceCheckResult(result, resultRule, ruleIDRef, instructions, scanName, namespace, ruleValues, manualRules, valuesList)
-		if err != nil {
+}
+
+// StreamResultsFromContentAndXccdf returns the same results as
+// ParseResultsFromContentAndXccdf, but reads the results document one element
+// at a time instead of loading all of it, which keeps the memory use low on
+// large ARF reports. Like the XCCDF schema mandates, the set-value elements
+// of a TestResult must come before its rule-result elements.
+func StreamResultsFromContentAndXccdf(scheme *runtime.Scheme, scanName string, namespace string,
+	dsDom *xmlquery.Node, resultsReader io.Reader, manualRules []string) ([]*ParseResult, error) {
+	return StreamSelectedResultsFromContentAndXccdf(scheme, scanName, namespace, dsDom, resultsReader, manualRules, nil)
+}
+
+// StreamSelectedResultsFromContentAndXccdf is the streaming counterpart of
+// ParseSelectedResultsFromContentAndXccdf
+func StreamSelectedResultsFromContentAndXccdf(scheme *runtime.Scheme, scanName string, namespace string,
+	dsDom *xmlquery.Node, resultsReader io.Reader, manualRules []string, selectedRules map[string]bool) ([]*ParseResult, error) {
+
+	parser := newResultParser(scheme, scanName, namespace, dsDom, manualRules, selectedRules)
+	parsedResults := make([]*ParseResult, 0)
+	var remErrs string
+
+	decoder := xml.NewDecoder(resultsReader)
+	prefixes := make(map[string]string)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
 			continue
 		}
-
-		if resCheck != nil {
-			pr := &ParseResult{
-				Id:          ruleIDRef,
-				CheckResult: resCheck,
+		recordNamespacePrefixes(start, prefixes)
+
+		switch start.Name.Local {
+		case "set-value":
+			setValue := struct {
+				IDRef string `xml:"idref,attr"`
+				Value string `xml:",chardata"`
+			}{}
+			if err := decoder.DecodeElement(&setValue, &start); err != nil {
+				return nil, err
 			}
-			pr.Remediations, err = newComplianceRemediation(scheme, scanName, namespace, resultRule, valuesList)
+			parser.valuesList[strings.TrimPrefix(setValue.IDRef, valuePrefix)] = setValue.Value
+		case "rule-result":
+			result, err := decodeStreamedElement(decoder, start, prefixes)
 			if err != nil {
-				remErrs = "CheckID." + ruleIDRef + err.Error() + "\n"
+				return nil, err
+			}
+			pr, err := parser.parse(result)
+			if err != nil {
+				remErrs = err.Error()
+			}
+			if pr != nil {
+				parsedResults = append(parsedResults, pr)
 			}
-			parsedResults = append(parsedResults, pr)
 		}
 	}
 	if remErrs != "" {
 		return parsedResults, errors.New(remErrs)
 	}
 	return parsedResults, nil
+}
+
+// decodeStreamedElement reads the element that starts with start and returns
+// it as a standalone node. prefixes maps the namespaces declared so far to
+// their prefixes.
+func decodeStreamedElement(decoder *xml.Decoder, start xml.StartElement, prefixes map[string]string) (*xmlquery.Node, error) {
+	root := newStreamedNode(start, prefixes)
+	current := root
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			recordNamespacePrefixes(t, prefixes)
+			node := newStreamedNode(t, prefixes)
+			xmlquery.AddChild(current, node)
+			current = node
+		case xml.EndElement:
+			if current == root {
+				return root, nil
+			}
+			current = current.Parent
+		case xml.CharData:
+			xmlquery.AddChild(current, &xmlquery.Node{Type: xmlquery.TextNode, Data: string(t)})
+		}
+	}
+}
 
+func newStreamedNode(start xml.StartElement, prefixes map[string]string) *xmlquery.Node {
+	attrs := make([]xmlquery.Attr, len(start.Attr))
+	for i, attr := range start.Attr {
+		name := attr.Name
+		if prefix, ok := prefixes[name.Space]; ok {
+			name.Space = prefix
+		}
+		attrs[i] = xmlquery.Attr{Name: name, Value: attr.Value, NamespaceURI: attr.Name.Space}
+	}
+	return &xmlquery.Node{
+		Type:         xmlquery.ElementNode,
+		Data:         start.Name.Local,
+		Prefix:       prefixes[start.Name.Space],
+		NamespaceURI: start.Name.Space,
+		Attr:         attrs,
+	}
+}
+
+func recordNamespacePrefixes(start xml.StartElement, prefixes map[string]string) {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "xmlns" {
+			prefixes[attr.Value] = ""
+		} else if attr.Name.Space == "xmlns" {
+			prefixes[attr.Value] = attr.Name.Local
+		}
+	}
+}
+
+// resultParser turns rule-result elements into ParseResults, looking up the
+// rules they refer to in the content
+type resultParser struct {
+	scheme           *runtime.Scheme
+	scanName         string
+	namespace        string
+	manualRules      []string
+	selectedRules    map[string]bool
+	ruleTable        NodeByIdHashTable
+	questionsTable   NodeByIdHashTable
+	defTable         NodeByIdHashTable
+	ovalTestVarTable nodeByIdHashVariablesTable
+	// The values the scan used, keyed by their ID without the prefix
+	valuesList map[string]string
+}
+
+func newResultParser(scheme *runtime.Scheme, scanName, namespace string, dsDom *xmlquery.Node, manualRules []string, selectedRules map[string]bool) *resultParser {
+	p := &resultParser{
+		scheme:        scheme,
+		scanName:      scanName,
+		namespace:     namespace,
+		manualRules:   manualRules,
+		selectedRules: selectedRules,
+		valuesList:    make(map[string]string),
+	}
+	if selectedRules != nil {
+		p.indexSelectedRules(dsDom)
+		return p
+	}
+
+	statesTable := newStateHashTable(dsDom)
+	objsTable := newObjHashTable(dsDom)
+	p.ruleTable = newRuleHashTable(dsDom)
+	p.questionsTable = NewOcilQuestionTable(dsDom)
+	p.defTable = NewDefHashTable(dsDom)
+	p.ovalTestVarTable = newValueListTable(dsDom, statesTable, objsTable)
+	return p
+}
+
+// indexSelectedRules fills the tables of the parser with the selected rules
+// and only the OVAL definitions, tests, states and objects and the OCIL
+// questions those rules use, leaving out the rest of the content
+func (p *resultParser) indexSelectedRules(dsDom *xmlquery.Node) {
+	p.ruleTable = newFilteredHashTableFromRootAndQuery(dsDom, "//ds:component/xccdf-1.2:Benchmark", "//xccdf-1.2:Rule", p.selectedRules)
+
+	defIDs := make(map[string]bool)
+	questionIDs := make(map[string]bool)
+	for _, rule := range p.ruleTable {
+		if id := getRuleOvalDefinitionID(rule); id != "" {
+			defIDs[id] = true
+		}
+		if id := getRuleOcilQuestionID(rule); id != "" {
+			questionIDs[id] = true
+		}
+	}
+	p.defTable = newFilteredHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:definitions", "*", defIDs)
+	p.questionsTable = newFilteredHashTableFromRootAndQuery(dsDom, "//ds:component/ocil:ocil", "//ocil:boolean_question", questionIDs)
+
+	testIDs := make(map[string]bool)
+	for _, def := range p.defTable {
+		for _, criterion := range def.SelectElements("//oval-def:criterion") {
+			if ref := criterion.SelectAttr("test_ref"); ref != "" {
+				testIDs[ref] = true
+			}
+		}
+	}
+	testsTable := newFilteredHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:tests", "*", testIDs)
+
+	stateIDs := make(map[string]bool)
+	objIDs := make(map[string]bool)
+	tests := make([]*xmlquery.Node, 0, len(testsTable))
+	for _, test := range testsTable {
+		for _, state := range test.SelectElements("//ind:state") {
+			stateIDs[state.SelectAttr("state_ref")] = true
+		}
+		for _, obj := range test.SelectElements("//ind:object") {
+			objIDs[obj.SelectAttr("object_ref")] = true
+		}
+		tests = append(tests, test)
+	}
+	statesTable := newFilteredHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:states", "*", stateIDs)
+	objsTable := newFilteredHashTableFromRootAndQuery(dsDom, "//ds:component/oval-def:oval_definitions/oval-def:objects", "*", objIDs)
+	p.ovalTestVarTable = newValueListTableFromTests(tests, statesTable, objsTable)
+}
+
+// parse returns the ParseResult of a rule-result, or nil if the result
+// doesn't produce a check. The error is about the remediations, the check
+// result is still returned then.
+func (p *resultParser) parse(result *xmlquery.Node) (*ParseResult, error) {
+	ruleIDRef := result.SelectAttr("idref")
+	if ruleIDRef == "" {
+		return nil, nil
+	}
+	if p.selectedRules != nil && !p.selectedRules[ruleIDRef] {
+		return nil, nil
+	}
+	if resultIsNotSelected(result) {
+		return nil, nil
+	}
+
+	resultRule := p.ruleTable[ruleIDRef]
+	if resultRule == nil {
+		return nil, nil
+	}
+
+	instructions, _ := GetInstructionsForRule(resultRule, p.questionsTable, p.valuesList)
+	ruleValues := getValueListUsedForRule(resultRule, p.ovalTestVarTable, p.defTable, p.questionsTable, p.valuesList)
+	resCheck, err := newComplianceCheckResult(result, resultRule, ruleIDRef, instructions, p.scanName, p.namespace, ruleValues, p.manualRules, p.valuesList)
+	if err != nil || resCheck == nil {
+		return nil, nil
+	}
+
+	pr := &ParseResult{
+		Id:          ruleIDRef,
+		CheckResult: resCheck,
+	}
+	pr.Remediations, err = newComplianceRemediation(p.scheme, p.scanName, p.namespace, resultRule, p.valuesList)
+	if err != nil {
+		return pr, errors.New("CheckID." + ruleIDRef + err.Error() + "\n")
+	}
+	return pr, nil
 }
 
 // Returns a new complianceCheckResult if the check data is usable
@@ -565,13 +884,22 @@ func newComplianceCheckResult(result *xmlquery.Node, rule *xmlquery.Node, ruleId
 		return nil, err
 	}
 
+	annotations := make(map[string]string)
+
+	// An unscored rule is informational, a failure doesn't count against
+	// the compliance of the scan
+	if isUnscoredRule(result, rule) {
+		annotations[compv1alpha1.ComplianceCheckResultUnscoredAnnotation] = "true"
+		if mappedStatus == compv1alpha1.CheckResultFail {
+			mappedStatus = compv1alpha1.CheckResultInfo
+		}
+	}
+
 	// check if rule is set as manual rules in TailoredProfile
 	if xccdf.IsManualRule(IDToDNSFriendlyName(ruleIdRef), manualRules) {
 		mappedStatus = compv1alpha1.CheckResultManual
 	}
 
-	annotations := make(map[string]string)
-
 	if RuleHasHideTagWarning(rule) {
 		annotations[compv1alpha1.RuleHideTagAnnotationKey] = "true"
 	}
@@ -592,23 +920,52 @@ func newComplianceCheckResult(result *xmlquery.Node, rule *xmlquery.Node, ruleId
 		}
 	}
 
+	var notApplicableReason string
+	if mappedStatus == compv1alpha1.CheckResultNotApplicable {
+		notApplicableReason = getResultMessage(result)
+	}
+
 	return &compv1alpha1.ComplianceCheckResult{
 		ObjectMeta: v1.ObjectMeta{
 			Name:        name,
 			Namespace:   namespace,
 			Annotations: annotations,
 		},
-		ID:           ruleIdRef,
-		Status:       mappedStatus,
-		Severity:     mappedSeverity,
-		Instructions: instructions,
-		Description:  description,
-		Rationale:    rationale,
-		Warnings:     GetWarningsForRule(rule),
-		ValuesUsed:   ruleValues,
+		ID:                  ruleIdRef,
+		Status:              mappedStatus,
+		Severity:            mappedSeverity,
+		Instructions:        instructions,
+		Description:         description,
+		Rationale:           rationale,
+		Warnings:            GetWarningsForRule(rule),
+		ValuesUsed:          ruleValues,
+		NotApplicableReason: notApplicableReason,
 	}, renderError
 }
 
+// isUnscoredRule returns whether the XCCDF role of a rule is "unscored". The
+// role of the rule-result takes precedence over the one of the rule, as the
+// scanner may have been told to override it.
+func isUnscoredRule(result, rule *xmlquery.Node) bool {
+	role := result.SelectAttr("role")
+	if role == "" {
+		role = rule.SelectAttr("role")
+	}
+	return role == xccdfRoleUnscored
+}
+
+// getResultMessage returns the messages the scanner attached to a rule result,
+// one per line
+func getResultMessage(result *xmlquery.Node) string {
+	messages := []string{}
+	for _, msg := range result.SelectElements("message") {
+		if text := strings.TrimSpace(msg.InnerText()); text != "" {
+			messages = append(messages, text)
+		}
+	}
+	return strings.Join(messages, "\n")
+}
+
 func getSafeText(nptr *xmlquery.Node, elem string) string {
 	elemNode := nptr.SelectElement(elem)
 	if elemNode == nil {
@@ -696,6 +1053,19 @@ func RuleHasHideTagWarning(rule *xmlquery.Node) bool {
 	return false
 }
 
+// OverrideCheckSeverities sets the severity of the check results of the rules
+// whose severity is overridden, e.g. by the refinements of a tailored profile
+func OverrideCheckSeverities(results []*ParseResult, overrides map[string]compv1alpha1.ComplianceCheckResultSeverity) {
+	for _, pr := range results {
+		if pr == nil || pr.CheckResult == nil {
+			continue
+		}
+		if severity, ok := overrides[pr.CheckResult.ID]; ok {
+			pr.CheckResult.Severity = severity
+		}
+	}
+}
+
 func mapComplianceCheckResultSeverity(result *xmlquery.Node) (compv1alpha1.ComplianceCheckResultSeverity, error) {
 	severityAttr := result.SelectAttr("severity")
 	if severityAttr == "" {
@@ -720,6 +1090,13 @@ func mapComplianceCheckResultSeverity(result *xmlquery.Node) (compv1alpha1.Compl
 	return compv1alpha1.CheckResultSeverityUnknown, nil
 }
 
+// resultIsNotSelected is a shortcut for results that mapComplianceCheckResultStatus
+// would map to CheckResultNoResult
+func resultIsNotSelected(result *xmlquery.Node) bool {
+	resultEl := result.SelectElement("result")
+	return resultEl != nil && resultEl.InnerText() == "notselected"
+}
+
 func mapComplianceCheckResultStatus(result *xmlquery.Node) (compv1alpha1.ComplianceCheckStatus, error) {
 	resultEl := result.SelectElement("result")
 	if resultEl == nil {
@@ -804,6 +1181,7 @@ func remediationsFromString(scheme *runtime.Scheme, name string, namespace strin
 	if err != nil {
 		return nil, err
 	}
+	objs = dedupAndSortFixObjects(objs)
 	rems := make([]*compv1alpha1.ComplianceRemediation, 0, len(objs))
 	for idx := range objs {
 		obj := objs[idx]
@@ -882,6 +1260,33 @@ func remediationsFromString(scheme *runtime.Scheme, name string, namespace strin
 	return rems, nil
 }
 
+// fixObjectKey identifies an object in a fix by its GVK, namespace and name
+func fixObjectKey(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GroupVersionKind().String(), obj.GetNamespace(), obj.GetName())
+}
+
+// dedupAndSortFixObjects drops objects that appear more than once in a fix,
+// keeping the first occurrence, and sorts the rest by their key. This way the
+// remediation names derived from the object positions don't change when the
+// content lists the same objects in a different order.
+func dedupAndSortFixObjects(objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	seen := make(map[string]bool, len(objs))
+	deduped := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		key := fixObjectKey(obj)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, obj)
+	}
+
+	sort.SliceStable(deduped, func(i, j int) bool {
+		return fixObjectKey(deduped[i]) < fixObjectKey(deduped[j])
+	})
+	return deduped
+}
+
 func toArrayByComma(format string) []string {
 	return strings.Split(format, ",")
 }
@@ -1,12 +1,14 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"testing"
 
 	"github.com/antchfx/xmlquery"
 	igntypes "github.com/coreos/ignition/v2/config/v3_4/types"
@@ -361,6 +363,259 @@ Server 3.fedora.pool.ntp.org`
 
 	})
 
+	Describe("Test for not applicable rules", func() {
+		const notApplicableResults = `<?xml version="1.0" encoding="UTF-8"?>
+<TestResult xmlns="http://checklists.nist.gov/xccdf/1.2" id="xccdf_org.open-scap_testresult_xccdf_org.ssgproject.content_profile_moderate">
+  <rule-result idref="xccdf_org.ssgproject.content_rule_selinux_confinement_of_daemons" severity="medium" weight="1.000000">
+    <result>notapplicable</result>
+    <message severity="info">The rule only applies to systems running SELinux in targeted mode</message>
+    <check system="http://oval.mitre.org/XMLSchema/oval-definitions-5">
+      <check-content-ref name="oval:ssg-selinux_confinement_of_daemons:def:1" href="#oval0"/>
+    </check>
+  </rule-result>
+  <rule-result idref="xccdf_org.ssgproject.content_rule_selinux_policytype" severity="high" weight="1.000000">
+    <result>pass</result>
+    <check system="http://oval.mitre.org/XMLSchema/oval-definitions-5">
+      <check-content-ref name="oval:ssg-selinux_policytype:def:1" href="#oval0"/>
+    </check>
+  </rule-result>
+</TestResult>`
+
+		BeforeEach(func() {
+			mcInstance := &mcfgv1.MachineConfig{}
+			schema = scheme.Scheme
+			schema.AddKnownTypes(mcfgv1.SchemeGroupVersion, mcInstance)
+			dsFilename = "../../tests/data/ds-input-for-remediation-value.xml"
+		})
+
+		JustBeforeEach(func() {
+			xccdf = strings.NewReader(notApplicableResults)
+
+			ds, err = os.Open(dsFilename)
+			Expect(err).NotTo(HaveOccurred())
+			dsDom, err := ParseContent(ds)
+			Expect(err).NotTo(HaveOccurred())
+			resultList, err = ParseResultsFromContentAndXccdf(schema, "testScan", "testNamespace", dsDom, xccdf, []string{})
+			Expect(resultList).NotTo(BeEmpty())
+		})
+
+		findCheck := func(name string) *compv1alpha1.ComplianceCheckResult {
+			for i := range resultList {
+				if resultList[i].CheckResult != nil && resultList[i].CheckResult.Name == name {
+					return resultList[i].CheckResult
+				}
+			}
+			return nil
+		}
+
+		It("Should preserve the reason a check was not applicable", func() {
+			check := findCheck("testScan-selinux-confinement-of-daemons")
+			Expect(check).ToNot(BeNil())
+			Expect(check.Status).To(Equal(compv1alpha1.CheckResultNotApplicable))
+			Expect(check.NotApplicableReason).To(Equal("The rule only applies to systems running SELinux in targeted mode"))
+		})
+
+		It("Should not set a reason on applicable checks", func() {
+			check := findCheck("testScan-selinux-policytype")
+			Expect(check).ToNot(BeNil())
+			Expect(check.Status).ToNot(Equal(compv1alpha1.CheckResultNotApplicable))
+			Expect(check.NotApplicableReason).To(BeEmpty())
+		})
+	})
+
+	Describe("Test for unscored rules", func() {
+		const unscoredResults = `<?xml version="1.0" encoding="UTF-8"?>
+<TestResult xmlns="http://checklists.nist.gov/xccdf/1.2" id="xccdf_org.open-scap_testresult_xccdf_org.ssgproject.content_profile_moderate">
+  <rule-result idref="xccdf_org.ssgproject.content_rule_disable_ctrlaltdel_reboot" role="unscored" severity="high" weight="1.000000">
+    <result>fail</result>
+    <check system="http://oval.mitre.org/XMLSchema/oval-definitions-5">
+      <check-content-ref name="oval:ssg-disable_ctrlaltdel_reboot:def:1" href="#oval0"/>
+    </check>
+  </rule-result>
+  <rule-result idref="xccdf_org.ssgproject.content_rule_disable_ctrlaltdel_burstaction" severity="high" weight="1.000000">
+    <result>fail</result>
+    <check system="http://oval.mitre.org/XMLSchema/oval-definitions-5">
+      <check-content-ref name="oval:ssg-disable_ctrlaltdel_burstaction:def:1" href="#oval0"/>
+    </check>
+  </rule-result>
+</TestResult>`
+
+		BeforeEach(func() {
+			mcInstance := &mcfgv1.MachineConfig{}
+			schema = scheme.Scheme
+			schema.AddKnownTypes(mcfgv1.SchemeGroupVersion, mcInstance)
+			dsFilename = "../../tests/data/ds-input-for-remediation-value.xml"
+		})
+
+		JustBeforeEach(func() {
+			xccdf = strings.NewReader(unscoredResults)
+
+			ds, err = os.Open(dsFilename)
+			Expect(err).NotTo(HaveOccurred())
+			dsDom, err := ParseContent(ds)
+			Expect(err).NotTo(HaveOccurred())
+			resultList, err = ParseResultsFromContentAndXccdf(schema, "testScan", "testNamespace", dsDom, xccdf, []string{})
+			Expect(resultList).NotTo(BeEmpty())
+		})
+
+		findCheck := func(name string) *compv1alpha1.ComplianceCheckResult {
+			for i := range resultList {
+				if resultList[i].CheckResult != nil && resultList[i].CheckResult.Name == name {
+					return resultList[i].CheckResult
+				}
+			}
+			return nil
+		}
+
+		It("Should make the failure of an unscored rule informational", func() {
+			check := findCheck("testScan-disable-ctrlaltdel-reboot")
+			Expect(check).ToNot(BeNil())
+			Expect(check.Status).To(Equal(compv1alpha1.CheckResultInfo))
+			Expect(check.Severity).To(Equal(compv1alpha1.CheckResultSeverityHigh))
+			Expect(check.Annotations).To(HaveKeyWithValue(compv1alpha1.ComplianceCheckResultUnscoredAnnotation, "true"))
+		})
+
+		It("Should keep the failures of scored rules", func() {
+			check := findCheck("testScan-disable-ctrlaltdel-burstaction")
+			Expect(check).ToNot(BeNil())
+			Expect(check.Status).To(Equal(compv1alpha1.CheckResultFail))
+			Expect(check.Annotations).ToNot(HaveKey(compv1alpha1.ComplianceCheckResultUnscoredAnnotation))
+		})
+	})
+
+	Describe("Test for severity overrides", func() {
+		const (
+			overriddenID = "xccdf_org.ssgproject.content_rule_selinux_policytype"
+			otherID      = "xccdf_org.ssgproject.content_rule_grub2_enable_selinux"
+		)
+
+		var overrides map[string]compv1alpha1.ComplianceCheckResultSeverity
+
+		BeforeEach(func() {
+			mcInstance := &mcfgv1.MachineConfig{}
+			schema = scheme.Scheme
+			schema.AddKnownTypes(mcfgv1.SchemeGroupVersion, mcInstance)
+			resultsFilename = "../../tests/data/xccdf-result.xml"
+			dsFilename = "../../tests/data/ds-input.xml"
+
+			tailoring, err := ParseContent(strings.NewReader(`<xccdf-1.2:Tailoring xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2">
+				<xccdf-1.2:Profile id="tailored" extends="xccdf_org.ssgproject.content_profile_coreos-ncp">
+					<xccdf-1.2:refine-rule idref="` + overriddenID + `" severity="low"/>
+				</xccdf-1.2:Profile>
+			</xccdf-1.2:Tailoring>`))
+			Expect(err).NotTo(HaveOccurred())
+			overrides = GetRuleSeverityOverrides(tailoring, "tailored")
+		})
+
+		JustBeforeEach(func() {
+			xccdf, err = os.Open(resultsFilename)
+			Expect(err).NotTo(HaveOccurred())
+
+			ds, err = os.Open(dsFilename)
+			Expect(err).NotTo(HaveOccurred())
+			dsDom, err := ParseContent(ds)
+			Expect(err).NotTo(HaveOccurred())
+			resultList, err = ParseResultsFromContentAndXccdf(schema, "testScan", "testNamespace", dsDom, xccdf, []string{})
+			Expect(err).NotTo(HaveOccurred())
+			OverrideCheckSeverities(resultList, overrides)
+		})
+
+		findCheck := func(id string) *compv1alpha1.ComplianceCheckResult {
+			for i := range resultList {
+				if resultList[i].CheckResult != nil && resultList[i].CheckResult.ID == id {
+					return resultList[i].CheckResult
+				}
+			}
+			return nil
+		}
+
+		It("Should report the check with the overridden severity", func() {
+			check := findCheck(overriddenID)
+			Expect(check).ToNot(BeNil())
+			Expect(check.Severity).To(Equal(compv1alpha1.CheckResultSeverityLow))
+		})
+
+		It("Should keep the severity of the content when it isn't overridden", func() {
+			check := findCheck(otherID)
+			Expect(check).ToNot(BeNil())
+			Expect(check.Severity).To(Equal(compv1alpha1.CheckResultSeverityMedium))
+		})
+
+		It("Should override nothing without a tailoring", func() {
+			Expect(GetRuleSeverityOverrides(nil, "tailored")).To(BeEmpty())
+		})
+	})
+
+	Describe("Indexing nodes by their ID", func() {
+		parseNodes := func(xml string) []*xmlquery.Node {
+			doc, err := xmlquery.Parse(strings.NewReader(xml))
+			Expect(err).NotTo(HaveOccurred())
+			return xmlquery.Find(doc, "//Rule")
+		}
+
+		It("Should report the duplicated IDs", func() {
+			nodes := parseNodes(`<Benchmark>
+				<Rule id="a"><title>first a</title></Rule>
+				<Rule id="b"/>
+				<Rule id="a"><title>second a</title></Rule>
+				<Rule id="c"/>
+				<Rule id="a"/>
+			</Benchmark>`)
+			table, duplicates := newByIdHashTable(nodes)
+			Expect(duplicates).To(Equal([]string{"a"}))
+			Expect(table).To(HaveLen(3))
+			Expect(table["a"]).To(BeIdenticalTo(nodes[0]))
+		})
+
+		It("Should report nothing without duplicated IDs", func() {
+			_, duplicates := newByIdHashTable(parseNodes(`<Benchmark><Rule id="a"/><Rule id="b"/></Benchmark>`))
+			Expect(duplicates).To(BeEmpty())
+		})
+
+		It("Should find no duplicated rules in the shipped content", func() {
+			content, err := os.Open("../../tests/data/ssg-ocp4-ds-new.xml")
+			Expect(err).NotTo(HaveOccurred())
+			defer content.Close()
+			dsDom, err := ParseDataStream(content)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(FindDuplicateRuleIDs(dsDom)).To(BeEmpty())
+		})
+	})
+
+	Describe("Validating the data stream", func() {
+		var content []byte
+
+		BeforeEach(func() {
+			content, err = os.ReadFile("../../tests/data/ssg-ocp4-ds-new.xml")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should accept a complete data stream", func() {
+			dsDom, err := ParseDataStream(bytes.NewReader(content))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dsDom).NotTo(BeNil())
+		})
+
+		It("Should reject a truncated data stream", func() {
+			_, err := ParseDataStream(bytes.NewReader(content[:len(content)/2]))
+			Expect(err).To(MatchError(ErrInvalidDataStream))
+		})
+
+		It("Should reject a data stream cut before the benchmark", func() {
+			truncated := string(content[:bytes.Index(content, []byte("<ds:component "))]) +
+				`<ds:component id="truncated"/></ds:data-stream-collection>`
+			_, err := ParseDataStream(strings.NewReader(truncated))
+			Expect(err).To(MatchError(ErrInvalidDataStream))
+			Expect(err.Error()).To(ContainSubstring("no XCCDF Benchmark found"))
+		})
+
+		It("Should reject XML that isn't SCAP content", func() {
+			_, err := ParseDataStream(strings.NewReader(`<html><body><p>Not found</p></body></html>`))
+			Expect(err).To(MatchError(ErrInvalidDataStream))
+			Expect(err.Error()).To(ContainSubstring("no ds:component element found"))
+		})
+	})
+
 	Describe("Load the XCCDF and the DS separately", func() {
 		BeforeEach(func() {
 			mcInstance := &mcfgv1.MachineConfig{}
@@ -971,3 +1226,229 @@ func printUniquePaths(node *xmlquery.Node, currentPath string, visitedPaths map[
 		printUniquePaths(child, path, visitedPaths)
 	}
 }
+
+var _ = Describe("Generating remediations from a multi-object fix", func() {
+	const (
+		cmA = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  namespace: openshift-config
+`
+		cmB = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  namespace: openshift-config
+`
+		secretA = `apiVersion: v1
+kind: Secret
+metadata:
+  name: a
+  namespace: openshift-config
+`
+	)
+
+	remediationObjects := func(fix string) map[string]string {
+		rems, err := remediationsFromString(scheme.Scheme, "test-rem", "test-ns", fix, nil)
+		Expect(err).To(BeNil())
+		byName := make(map[string]string)
+		for _, rem := range rems {
+			byName[rem.Name] = rem.Spec.Current.Object.GetKind() + "/" + rem.Spec.Current.Object.GetName()
+		}
+		return byName
+	}
+
+	It("drops objects that appear more than once", func() {
+		rems := remediationObjects(strings.Join([]string{cmA, cmB, cmA}, "---\n"))
+		Expect(rems).To(Equal(map[string]string{
+			"test-rem":   "ConfigMap/a",
+			"test-rem-1": "ConfigMap/b",
+		}))
+	})
+
+	It("names the remediations the same regardless of the object order", func() {
+		first := remediationObjects(strings.Join([]string{cmA, cmB, secretA}, "---\n"))
+		second := remediationObjects(strings.Join([]string{secretA, cmB, cmA}, "---\n"))
+		Expect(first).To(HaveLen(3))
+		Expect(second).To(Equal(first))
+	})
+})
+
+var _ = Describe("Parsing API paths from rule warnings", func() {
+	parseWarning := func(warning string) *xmlquery.Node {
+		doc, err := xmlquery.Parse(strings.NewReader(warning))
+		Expect(err).To(BeNil())
+		return doc.SelectElement("//warning")
+	}
+
+	It("doesn't suppress warnings by default", func() {
+		warning := parseWarning(`<warning xmlns:html="http://www.w3.org/1999/xhtml" category="general">` +
+			`<html:code class="ocp-api-endpoint">/apis/config.openshift.io/v1/oauths/cluster</html:code>` +
+			`</warning>`)
+		paths, err := GetPathFromWarningXML(warning, nil)
+		Expect(err).To(BeNil())
+		Expect(paths).To(ConsistOf(ResourcePath{
+			ObjPath:  "/apis/config.openshift.io/v1/oauths/cluster",
+			DumpPath: "/apis/config.openshift.io/v1/oauths/cluster",
+		}))
+	})
+
+	It("suppresses warnings for endpoints marked as optional", func() {
+		warning := parseWarning(`<warning xmlns:html="http://www.w3.org/1999/xhtml" category="general">` +
+			`<html:code class="ocp-api-endpoint">/apis/hypershift.openshift.io/v1beta1/hostedclusters</html:code>` +
+			`<html:code class="ocp-suppress-warning"/>` +
+			`</warning>`)
+		paths, err := GetPathFromWarningXML(warning, nil)
+		Expect(err).To(BeNil())
+		Expect(paths).To(ConsistOf(ResourcePath{
+			ObjPath:         "/apis/hypershift.openshift.io/v1beta1/hostedclusters",
+			DumpPath:        "/apis/hypershift.openshift.io/v1beta1/hostedclusters",
+			SuppressWarning: true,
+		}))
+	})
+})
+
+var _ = Describe("Parsing only the selected results", func() {
+	var dsDom *xmlquery.Node
+
+	parseResults := func(selectedRules map[string]bool) []*ParseResult {
+		xccdf, err := os.Open("../../tests/data/xccdf-result.xml")
+		Expect(err).NotTo(HaveOccurred())
+		defer xccdf.Close()
+		results, err := ParseSelectedResultsFromContentAndXccdf(scheme.Scheme, "testScan", "testNamespace", dsDom, xccdf, []string{}, selectedRules)
+		Expect(err).NotTo(HaveOccurred())
+		return results
+	}
+
+	BeforeEach(func() {
+		ds, err := os.Open("../../tests/data/ds-input.xml")
+		Expect(err).NotTo(HaveOccurred())
+		defer ds.Close()
+		dsDom, err = ParseContent(ds)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns the same results when given all the selected rules", func() {
+		allResults := parseResults(nil)
+		Expect(allResults).NotTo(BeEmpty())
+
+		selectedRules := make(map[string]bool)
+		for _, res := range allResults {
+			selectedRules[res.Id] = true
+		}
+		Expect(parseResults(selectedRules)).To(Equal(allResults))
+	})
+
+	It("only returns the results of the given rules", func() {
+		allResults := parseResults(nil)
+		Expect(len(allResults)).To(BeNumerically(">", 1))
+
+		selected := allResults[0].Id
+		results := parseResults(map[string]bool{selected: true})
+		Expect(results).To(HaveLen(1))
+		Expect(results[0]).To(Equal(allResults[0]))
+	})
+
+	It("only indexes the content the selected rules use", func() {
+		allResults := parseResults(nil)
+		selectedRules := make(map[string]bool)
+		for _, res := range allResults {
+			selectedRules[res.Id] = true
+		}
+
+		full := newResultParser(scheme.Scheme, "testScan", "testNamespace", dsDom, []string{}, nil)
+		selected := newResultParser(scheme.Scheme, "testScan", "testNamespace", dsDom, []string{}, selectedRules)
+		Expect(len(selected.ruleTable)).To(Equal(len(selectedRules)))
+		Expect(len(selected.ruleTable)).To(BeNumerically("<", len(full.ruleTable)))
+		Expect(len(selected.defTable)).To(BeNumerically("<", len(full.defTable)))
+		Expect(len(selected.questionsTable)).To(BeNumerically("<", len(full.questionsTable)))
+		Expect(len(selected.ovalTestVarTable)).To(BeNumerically("<", len(full.ovalTestVarTable)))
+		for rule := range selected.ruleTable {
+			Expect(selected.ruleTable[rule]).To(BeIdenticalTo(full.ruleTable[rule]))
+		}
+		for test, values := range selected.ovalTestVarTable {
+			Expect(values).To(Equal(full.ovalTestVarTable[test]))
+		}
+	})
+})
+
+var _ = Describe("Streaming the results", func() {
+	parseBoth := func(dsFilename, resultsFilename string, selectedRules map[string]bool) {
+		ds, err := os.Open(dsFilename)
+		Expect(err).NotTo(HaveOccurred())
+		defer ds.Close()
+		dsDom, err := ParseContent(ds)
+		Expect(err).NotTo(HaveOccurred())
+
+		results, err := os.ReadFile(resultsFilename)
+		Expect(err).NotTo(HaveOccurred())
+
+		domResults, domErr := ParseSelectedResultsFromContentAndXccdf(scheme.Scheme, "testScan", "testNamespace", dsDom, bytes.NewReader(results), []string{}, selectedRules)
+		streamedResults, streamErr := StreamSelectedResultsFromContentAndXccdf(scheme.Scheme, "testScan", "testNamespace", dsDom, bytes.NewReader(results), []string{}, selectedRules)
+		Expect(domResults).NotTo(BeEmpty())
+		Expect(streamedResults).To(Equal(domResults))
+		if domErr == nil {
+			Expect(streamErr).To(BeNil())
+		} else {
+			Expect(streamErr).To(MatchError(domErr.Error()))
+		}
+	}
+
+	It("returns the same results as parsing the whole document", func() {
+		parseBoth("../../tests/data/ds-input.xml", "../../tests/data/xccdf-result.xml", nil)
+	})
+
+	It("returns the same results for the selected rules", func() {
+		parseBoth("../../tests/data/ds-input.xml", "../../tests/data/xccdf-result.xml", map[string]bool{
+			"xccdf_org.ssgproject.content_rule_selinux_policytype": true,
+		})
+	})
+
+	It("renders the values and reports the remediation errors the same way", func() {
+		parseBoth("../../tests/data/ds-input-for-remediation-value-wrong-formate.xml", "../../tests/data/xccdf-result-remdiation-templating.xml", nil)
+	})
+
+	It("fails on malformed results", func() {
+		ds, err := os.Open("../../tests/data/ds-input.xml")
+		Expect(err).NotTo(HaveOccurred())
+		defer ds.Close()
+		dsDom, err := ParseContent(ds)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = StreamResultsFromContentAndXccdf(scheme.Scheme, "testScan", "testNamespace", dsDom, strings.NewReader("<TestResult><rule-result idref=\"foo\">"), []string{})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func benchmarkResultParsing(b *testing.B, parse func(*runtime.Scheme, string, string, *xmlquery.Node, io.Reader, []string) ([]*ParseResult, error)) {
+	ds, err := os.Open("../../tests/data/ds-input.xml")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ds.Close()
+	dsDom, err := ParseContent(ds)
+	if err != nil {
+		b.Fatal(err)
+	}
+	results, err := os.ReadFile("../../tests/data/xccdf-result.xml")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parse(scheme.Scheme, "testScan", "testNamespace", dsDom, bytes.NewReader(results), []string{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseResultsFromContentAndXccdf(b *testing.B) {
+	benchmarkResultParsing(b, ParseResultsFromContentAndXccdf)
+}
+
+func BenchmarkStreamResultsFromContentAndXccdf(b *testing.B) {
+	benchmarkResultParsing(b, StreamResultsFromContentAndXccdf)
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/antchfx/xmlquery"
+)
+
+// ProfileDiff lists the rules that were added to or removed from the
+// selection of a profile
+type ProfileDiff struct {
+	ID           string   `json:"id"`
+	AddedRules   []string `json:"addedRules,omitempty"`
+	RemovedRules []string `json:"removedRules,omitempty"`
+}
+
+// ContentDiff describes how the profiles of two data streams differ. All the
+// lists are sorted by ID.
+type ContentDiff struct {
+	AddedProfiles   []string      `json:"addedProfiles,omitempty"`
+	RemovedProfiles []string      `json:"removedProfiles,omitempty"`
+	ChangedProfiles []ProfileDiff `json:"changedProfiles,omitempty"`
+}
+
+// IsEmpty returns whether the data streams had the same profiles selecting
+// the same rules
+func (d *ContentDiff) IsEmpty() bool {
+	return len(d.AddedProfiles) == 0 && len(d.RemovedProfiles) == 0 && len(d.ChangedProfiles) == 0
+}
+
+// DiffContentProfiles compares the profiles of two data streams, as parsed by
+// ParseContent, and the rules each profile selects.
+func DiffContentProfiles(oldContent, newContent *xmlquery.Node) *ContentDiff {
+	oldProfiles := getProfileRuleSelections(oldContent)
+	newProfiles := getProfileRuleSelections(newContent)
+	diff := &ContentDiff{}
+
+	for id, newRules := range newProfiles {
+		oldRules, ok := oldProfiles[id]
+		if !ok {
+			diff.AddedProfiles = append(diff.AddedProfiles, id)
+			continue
+		}
+		profileDiff := ProfileDiff{
+			ID:           id,
+			AddedRules:   setDifference(newRules, oldRules),
+			RemovedRules: setDifference(oldRules, newRules),
+		}
+		if len(profileDiff.AddedRules) > 0 || len(profileDiff.RemovedRules) > 0 {
+			diff.ChangedProfiles = append(diff.ChangedProfiles, profileDiff)
+		}
+	}
+	for id := range oldProfiles {
+		if _, ok := newProfiles[id]; !ok {
+			diff.RemovedProfiles = append(diff.RemovedProfiles, id)
+		}
+	}
+
+	sort.Strings(diff.AddedProfiles)
+	sort.Strings(diff.RemovedProfiles)
+	sort.Slice(diff.ChangedProfiles, func(i, j int) bool {
+		return diff.ChangedProfiles[i].ID < diff.ChangedProfiles[j].ID
+	})
+	return diff
+}
+
+// getProfileRuleSelections maps the ID of every profile in the content to the
+// set of rule IDs it selects. When a profile selects the same rule more than
+// once, the last selection wins, as in XCCDF.
+func getProfileRuleSelections(content *xmlquery.Node) map[string]map[string]bool {
+	profiles := make(map[string]map[string]bool)
+	for _, profileObj := range xmlquery.Find(content, "//xccdf-1.2:Profile") {
+		id := profileObj.SelectAttr("id")
+		if id == "" {
+			continue
+		}
+		selections := make(map[string]bool)
+		applyProfileSelections(profileObj, selections)
+
+		rules := make(map[string]bool)
+		for idref, selected := range selections {
+			if selected {
+				rules[idref] = true
+			}
+		}
+		profiles[id] = rules
+	}
+	return profiles
+}
+
+// applyProfileSelections records in selections whether the profile selects
+// or unselects each rule it mentions, overriding earlier entries
+func applyProfileSelections(profileObj *xmlquery.Node, selections map[string]bool) {
+	for _, ruleObj := range profileObj.SelectElements("xccdf-1.2:select") {
+		idref := ruleObj.SelectAttr("idref")
+		if idref == "" {
+			continue
+		}
+		selections[idref] = ruleObj.SelectAttr("selected") == "true"
+	}
+}
+
+// setDifference returns the sorted keys of a that are not in b
+func setDifference(a, b map[string]bool) []string {
+	var diff []string
+	for key := range a {
+		if !b[key] {
+			diff = append(diff, key)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diffing the profiles of two data streams", func() {
+	// profiles maps profile IDs to the rule IDs they select
+	newContent := func(profiles map[string][]string) string {
+		var sb strings.Builder
+		sb.WriteString(`<xccdf-1.2:Benchmark xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2">`)
+		for id, rules := range profiles {
+			fmt.Fprintf(&sb, `<xccdf-1.2:Profile id="%s">`, id)
+			for _, rule := range rules {
+				fmt.Fprintf(&sb, `<xccdf-1.2:select idref="%s" selected="true"/>`, rule)
+			}
+			sb.WriteString(`</xccdf-1.2:Profile>`)
+		}
+		sb.WriteString(`</xccdf-1.2:Benchmark>`)
+		return sb.String()
+	}
+
+	diff := func(oldContent, newContent string) *ContentDiff {
+		oldDom, err := ParseContent(strings.NewReader(oldContent))
+		Expect(err).To(BeNil())
+		newDom, err := ParseContent(strings.NewReader(newContent))
+		Expect(err).To(BeNil())
+		return DiffContentProfiles(oldDom, newDom)
+	}
+
+	It("reports added and removed rules per profile", func() {
+		oldContent := newContent(map[string][]string{
+			"profile_cis":      {"rule_a", "rule_b", "rule_c"},
+			"profile_moderate": {"rule_a", "rule_b"},
+			"profile_removed":  {"rule_a"},
+		})
+		newContent := newContent(map[string][]string{
+			"profile_cis":      {"rule_a", "rule_c", "rule_d", "rule_e"},
+			"profile_moderate": {"rule_b", "rule_a"},
+			"profile_added":    {"rule_a"},
+		})
+
+		Expect(diff(oldContent, newContent)).To(Equal(&ContentDiff{
+			AddedProfiles:   []string{"profile_added"},
+			RemovedProfiles: []string{"profile_removed"},
+			ChangedProfiles: []ProfileDiff{
+				{
+					ID:           "profile_cis",
+					AddedRules:   []string{"rule_d", "rule_e"},
+					RemovedRules: []string{"rule_b"},
+				},
+			},
+		}))
+	})
+
+	It("honors the last selection of a rule", func() {
+		oldContent := newContent(map[string][]string{
+			"profile_cis": {"rule_a", "rule_b"},
+		})
+		newContent := strings.Replace(oldContent,
+			`</xccdf-1.2:Profile>`,
+			`<xccdf-1.2:select idref="rule_b" selected="false"/></xccdf-1.2:Profile>`, 1)
+
+		Expect(diff(oldContent, newContent)).To(Equal(&ContentDiff{
+			ChangedProfiles: []ProfileDiff{
+				{ID: "profile_cis", RemovedRules: []string{"rule_b"}},
+			},
+		}))
+	})
+
+	It("reports no differences for the same content", func() {
+		content := newContent(map[string][]string{
+			"profile_cis": {"rule_a", "rule_b"},
+		})
+		Expect(diff(content, content).IsEmpty()).To(BeTrue())
+	})
+})
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/antchfx/xmlquery"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// ContentProfile describes a profile of a data stream
+type ContentProfile struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+	// The ID of the profile this one extends, if any
+	Extends string `json:"extends,omitempty"`
+	// The IDs of the rules the profile selects, including the ones selected
+	// by the profiles it extends. Only set by DescribeContentProfile.
+	Rules []string `json:"rules,omitempty"`
+}
+
+// ListContentProfiles returns the profiles of the content, sorted by ID
+func ListContentProfiles(content *xmlquery.Node) []ContentProfile {
+	var profiles []ContentProfile
+	for _, profileObj := range xmlquery.Find(content, "//xccdf-1.2:Profile") {
+		if profileObj.SelectAttr("id") == "" {
+			continue
+		}
+		profiles = append(profiles, newContentProfile(profileObj))
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].ID < profiles[j].ID
+	})
+	return profiles
+}
+
+// DescribeContentProfile returns the profile of the content with the given
+// ID, along with the sorted IDs of the rules it ends up selecting once the
+// profiles it extends are taken into account.
+func DescribeContentProfile(content *xmlquery.Node, profileID string) (*ContentProfile, error) {
+	profileObjs := findProfiles(content, nil)
+	selections, err := resolveProfileSelections(profileObjs, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := newContentProfile(profileObjs[profileID])
+	profile.Rules = []string{}
+	for idref, selected := range selections {
+		if selected {
+			profile.Rules = append(profile.Rules, idref)
+		}
+	}
+	sort.Strings(profile.Rules)
+	return &profile, nil
+}
+
+// GetSelectedRuleIDs returns the IDs of the rules the profile selects, like
+// DescribeContentProfile does, except that the profile may also come from the
+// tailoring, which may be nil. Like the profileparser, it counts on the content
+// not selecting any rule unless a profile does.
+func GetSelectedRuleIDs(content, tailoring *xmlquery.Node, profileID string) (map[string]bool, error) {
+	selections, err := resolveProfileSelections(findProfiles(content, tailoring), profileID)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool)
+	for idref, isSelected := range selections {
+		if isSelected {
+			selected[idref] = true
+		}
+	}
+	return selected, nil
+}
+
+// GetRuleSeverityOverrides returns the severities the tailored profile
+// refines, by rule ID. The tailoring may be nil, in which case nothing is
+// overridden.
+func GetRuleSeverityOverrides(tailoring *xmlquery.Node, profileID string) map[string]compv1alpha1.ComplianceCheckResultSeverity {
+	overrides := make(map[string]compv1alpha1.ComplianceCheckResultSeverity)
+	if tailoring == nil {
+		return overrides
+	}
+	profileObj, ok := findProfiles(nil, tailoring)[profileID]
+	if !ok {
+		return overrides
+	}
+	for _, refinement := range profileObj.SelectElements("xccdf-1.2:refine-rule") {
+		idref := refinement.SelectAttr("idref")
+		if idref == "" || refinement.SelectAttr("severity") == "" {
+			continue
+		}
+		severity, _ := mapComplianceCheckResultSeverity(refinement)
+		overrides[idref] = severity
+	}
+	return overrides
+}
+
+// findProfiles indexes the profiles of the content and of the tailoring by
+// their ID
+func findProfiles(content, tailoring *xmlquery.Node) map[string]*xmlquery.Node {
+	profileObjs := make(map[string]*xmlquery.Node)
+	for _, doc := range []*xmlquery.Node{content, tailoring} {
+		if doc == nil {
+			continue
+		}
+		for _, profileObj := range xmlquery.Find(doc, "//xccdf-1.2:Profile") {
+			profileObjs[profileObj.SelectAttr("id")] = profileObj
+		}
+	}
+	return profileObjs
+}
+
+// resolveProfileSelections returns what the profile selects and deselects
+// once the selections of the profiles it extends are taken into account
+func resolveProfileSelections(profileObjs map[string]*xmlquery.Node, profileID string) (map[string]bool, error) {
+	profileObj, ok := profileObjs[profileID]
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found in the content", profileID)
+	}
+
+	// Walk up to the base profile, then apply the selections from the base
+	// down, so that the derived profiles override what they extend
+	var chain []*xmlquery.Node
+	seen := make(map[string]bool)
+	for obj := profileObj; obj != nil; obj = profileObjs[obj.SelectAttr("extends")] {
+		id := obj.SelectAttr("id")
+		if seen[id] {
+			return nil, fmt.Errorf("profile %s extends itself", id)
+		}
+		seen[id] = true
+		chain = append(chain, obj)
+	}
+	selections := make(map[string]bool)
+	for i := len(chain) - 1; i >= 0; i-- {
+		applyProfileSelections(chain[i], selections)
+	}
+	return selections, nil
+}
+
+func newContentProfile(profileObj *xmlquery.Node) ContentProfile {
+	profile := ContentProfile{
+		ID:      profileObj.SelectAttr("id"),
+		Extends: profileObj.SelectAttr("extends"),
+	}
+	if titleObj := profileObj.SelectElement("xccdf-1.2:title"); titleObj != nil {
+		profile.Title = titleObj.InnerText()
+	}
+	return profile
+}
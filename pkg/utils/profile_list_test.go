@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Describing the profiles of a data stream", func() {
+	const content = `<xccdf-1.2:Benchmark xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2">
+		<xccdf-1.2:Profile id="profile_base">
+			<xccdf-1.2:title>Base</xccdf-1.2:title>
+			<xccdf-1.2:select idref="rule_a" selected="true"/>
+			<xccdf-1.2:select idref="rule_b" selected="true"/>
+		</xccdf-1.2:Profile>
+		<xccdf-1.2:Profile id="profile_derived" extends="profile_base">
+			<xccdf-1.2:title>Derived</xccdf-1.2:title>
+			<xccdf-1.2:select idref="rule_b" selected="false"/>
+			<xccdf-1.2:select idref="rule_c" selected="true"/>
+		</xccdf-1.2:Profile>
+		<xccdf-1.2:Profile id="profile_loop" extends="profile_loop"/>
+	</xccdf-1.2:Benchmark>`
+
+	parse := func() *xmlquery.Node {
+		dom, err := ParseContent(strings.NewReader(content))
+		Expect(err).To(BeNil())
+		return dom
+	}
+
+	It("lists the profiles sorted by ID", func() {
+		Expect(ListContentProfiles(parse())).To(Equal([]ContentProfile{
+			{ID: "profile_base", Title: "Base"},
+			{ID: "profile_derived", Title: "Derived", Extends: "profile_base"},
+			{ID: "profile_loop", Extends: "profile_loop"},
+		}))
+	})
+
+	It("includes the rules selected by the extended profile", func() {
+		profile, err := DescribeContentProfile(parse(), "profile_derived")
+		Expect(err).To(BeNil())
+		Expect(profile.Extends).To(Equal("profile_base"))
+		Expect(profile.Rules).To(Equal([]string{"rule_a", "rule_c"}))
+	})
+
+	It("fails on profiles that extend themselves", func() {
+		_, err := DescribeContentProfile(parse(), "profile_loop")
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("selects the rules of a profile from the tailoring", func() {
+		tailoring, err := ParseContent(strings.NewReader(`<xccdf-1.2:Tailoring xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2">
+			<xccdf-1.2:Profile id="profile_tailored" extends="profile_derived">
+				<xccdf-1.2:select idref="rule_a" selected="false"/>
+				<xccdf-1.2:select idref="rule_d" selected="true"/>
+			</xccdf-1.2:Profile>
+		</xccdf-1.2:Tailoring>`))
+		Expect(err).To(BeNil())
+		selected, err := GetSelectedRuleIDs(parse(), tailoring, "profile_tailored")
+		Expect(err).To(BeNil())
+		Expect(selected).To(Equal(map[string]bool{"rule_c": true, "rule_d": true}))
+	})
+})
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/image/reference"
+)
+
+// The annotation ORAS and most other artifact tools use to record the file
+// name of a layer
+const ArtifactTitleAnnotation = "org.opencontainers.image.title"
+
+var authParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// RegistryRequest sends a request to a container registry using the Docker
+// registry v2 API, getting an anonymous token first if the registry asks for
+// one. The caller must close the response body.
+func RegistryRequest(ctx context.Context, client *http.Client, method, requestURL string, accept ...string) (*http.Response, error) {
+	resp, err := sendRegistryRequest(ctx, client, method, requestURL, "", accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenge := resp.Header.Get("Www-Authenticate")
+	resp.Body.Close()
+	token, err := getAnonymousRegistryToken(ctx, client, challenge)
+	if err != nil {
+		return nil, err
+	}
+	return sendRegistryRequest(ctx, client, method, requestURL, token, accept)
+}
+
+func sendRegistryRequest(ctx context.Context, client *http.Client, method, requestURL, token string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return client.Do(req)
+}
+
+// getAnonymousRegistryToken follows a bearer challenge to get a token that
+// allows pulling public images
+func getAnonymousRegistryToken(ctx context.Context, client *http.Client, challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication challenge: %q", challenge)
+	}
+	params := map[string]string{}
+	for _, match := range authParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid realm in registry authentication challenge: %q", challenge)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status getting a registry token: %s", resp.Status)
+	}
+
+	tokenResp := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	return tokenResp.AccessToken, nil
+}
+
+// PullArtifactFile downloads the layer of an OCI artifact that holds the file
+// fileName and writes it to out. The layer is picked by its title annotation,
+// or if the artifact has a single layer, that one is used regardless of its
+// title. Only anonymous registry access is supported.
+func PullArtifactFile(ctx context.Context, client *http.Client, artifact, fileName string, out io.Writer) error {
+	ref, err := reference.Parse(artifact)
+	if err != nil {
+		return err
+	}
+	ref = ref.DockerClientDefaults().AsV2()
+	repoURL := fmt.Sprintf("https://%s/v2/%s", ref.Registry, ref.RepositoryName())
+	manifestRef := ref.Tag
+	if ref.ID != "" {
+		manifestRef = ref.ID
+	}
+
+	resp, err := RegistryRequest(ctx, client, http.MethodGet, repoURL+"/manifests/"+manifestRef,
+		"application/vnd.oci.image.manifest.v1+json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status getting the manifest of %s: %s", artifact, resp.Status)
+	}
+
+	manifest := struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return fmt.Errorf("couldn't parse the manifest of %s: %w", artifact, err)
+	}
+
+	layerDigest := ""
+	for _, layer := range manifest.Layers {
+		if layer.Annotations[ArtifactTitleAnnotation] == path.Base(fileName) {
+			layerDigest = layer.Digest
+			break
+		}
+	}
+	if layerDigest == "" && len(manifest.Layers) == 1 {
+		layerDigest = manifest.Layers[0].Digest
+	}
+	if layerDigest == "" {
+		return fmt.Errorf("artifact %s has no layer for %s", artifact, fileName)
+	}
+	if !strings.HasPrefix(layerDigest, "sha256:") {
+		return fmt.Errorf("unsupported digest algorithm for layer %s", layerDigest)
+	}
+
+	blobResp, err := RegistryRequest(ctx, client, http.MethodGet, repoURL+"/blobs/"+layerDigest)
+	if err != nil {
+		return err
+	}
+	defer blobResp.Body.Close()
+	if blobResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status getting blob %s: %s", layerDigest, blobResp.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hash), blobResp.Body); err != nil {
+		return err
+	}
+	if layerDigest != "sha256:"+hex.EncodeToString(hash.Sum(nil)) {
+		return fmt.Errorf("the contents of blob %s don't match its digest", layerDigest)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pulling files from OCI artifacts", func() {
+	const content = "<ds:data-stream-collection/>"
+	var (
+		server   *httptest.Server
+		artifact string
+		layers   string
+		blob     string
+	)
+
+	digestOf := func(data string) string {
+		sum := sha256.Sum256([]byte(data))
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	BeforeEach(func() {
+		blob = content
+		layers = fmt.Sprintf(`[{"digest": "%s", "annotations": {"%s": "README.md"}},
+			{"digest": "%s", "annotations": {"%s": "ssg-ocp4-ds.xml"}}]`,
+			digestOf("readme"), ArtifactTitleAnnotation, digestOf(content), ArtifactTitleAnnotation)
+	})
+
+	JustBeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"token": "anonymous"}`)
+		})
+		mux.HandleFunc("/v2/complianceascode/ocp4/", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer anonymous" {
+				w.Header().Set("Www-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch r.URL.Path {
+			case "/v2/complianceascode/ocp4/manifests/latest":
+				fmt.Fprintf(w, `{"layers": %s}`, layers)
+			case "/v2/complianceascode/ocp4/blobs/" + digestOf(content):
+				fmt.Fprint(w, blob)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		server = httptest.NewTLSServer(mux)
+		artifact = strings.TrimPrefix(server.URL, "https://") + "/complianceascode/ocp4:latest"
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("downloads the layer with the file name as its title", func() {
+		out := &bytes.Buffer{}
+		Expect(PullArtifactFile(context.TODO(), server.Client(), artifact, "ssg-ocp4-ds.xml", out)).To(Succeed())
+		Expect(out.String()).To(Equal(content))
+	})
+
+	When("the artifact has a single untitled layer", func() {
+		BeforeEach(func() {
+			layers = fmt.Sprintf(`[{"digest": "%s"}]`, digestOf(content))
+		})
+
+		It("downloads that layer", func() {
+			out := &bytes.Buffer{}
+			Expect(PullArtifactFile(context.TODO(), server.Client(), artifact, "ssg-ocp4-ds.xml", out)).To(Succeed())
+			Expect(out.String()).To(Equal(content))
+		})
+	})
+
+	It("fails if no layer holds the file", func() {
+		err := PullArtifactFile(context.TODO(), server.Client(), artifact, "ssg-rhcos4-ds.xml", &bytes.Buffer{})
+		Expect(err).To(MatchError(ContainSubstring("has no layer for ssg-rhcos4-ds.xml")))
+	})
+
+	When("the blob doesn't match its digest", func() {
+		BeforeEach(func() {
+			blob = "tampered"
+		})
+
+		It("fails", func() {
+			err := PullArtifactFile(context.TODO(), server.Client(), artifact, "ssg-ocp4-ds.xml", &bytes.Buffer{})
+			Expect(err).To(MatchError(ContainSubstring("don't match its digest")))
+		})
+	})
+})
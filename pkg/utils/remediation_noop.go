@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	ign3types "github.com/coreos/ignition/v2/config/v3_4/types"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	mcfgcommon "github.com/openshift/machine-config-operator/pkg/controller/common"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RemediationIsNoOp returns whether applying the remediation wouldn't change
+// the state of the cluster. A MachineConfig remediation is a no-op if the
+// rendered MachineConfig of the pool it applies to already has the same files,
+// units and kernel arguments. Any other remediation is a no-op if an object
+// with the same name already has all the fields the remediation sets.
+func RemediationIsNoOp(ctx context.Context, client runtimeclient.Client, rem *compv1alpha1.ComplianceRemediation) (bool, error) {
+	obj := rem.Spec.Current.Object
+	if obj == nil {
+		return false, fmt.Errorf("remediation %s has no object", rem.Name)
+	}
+
+	if IsMachineConfig(obj) {
+		return machineConfigRemediationIsNoOp(ctx, client, rem)
+	}
+
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	key := types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+	if err := client.Get(ctx, key, live); errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("couldn't get the object of remediation %s: %w", rem.Name, err)
+	}
+
+	for field, value := range obj.Object {
+		switch field {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		if !equality.Semantic.DeepDerivative(value, live.Object[field]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func machineConfigRemediationIsNoOp(ctx context.Context, client runtimeclient.Client, rem *compv1alpha1.ComplianceRemediation) (bool, error) {
+	desired := &mcfgv1.MachineConfig{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rem.Spec.Current.Object.Object, desired); err != nil {
+		return false, fmt.Errorf("couldn't parse the MachineConfig of remediation %s: %w", rem.Name, err)
+	}
+
+	scan := &compv1alpha1.ComplianceScan{}
+	scanKey := types.NamespacedName{Name: rem.Labels[compv1alpha1.ComplianceScanLabel], Namespace: rem.Namespace}
+	if err := client.Get(ctx, scanKey, scan); err != nil {
+		return false, fmt.Errorf("couldn't get scan for remediation %s: %w", rem.Name, err)
+	}
+	mcfgpools := &mcfgv1.MachineConfigPoolList{}
+	if err := client.List(ctx, mcfgpools); err != nil {
+		return false, fmt.Errorf("couldn't list the pools for the remediation: %w", err)
+	}
+	matches, pool := AnyMcfgPoolLabelMatches(scan.Spec.NodeSelector, mcfgpools)
+	if !matches || pool.Status.Configuration.Name == "" {
+		// Nothing is rendered for the nodes, so applying will change them
+		return false, nil
+	}
+
+	rendered := &mcfgv1.MachineConfig{}
+	if err := client.Get(ctx, types.NamespacedName{Name: pool.Status.Configuration.Name}, rendered); err != nil {
+		return false, fmt.Errorf("couldn't get the rendered MachineConfig of pool %s: %w", pool.Name, err)
+	}
+
+	return machineConfigIsNoOp(desired, rendered)
+}
+
+// machineConfigIsNoOp returns whether the rendered MachineConfig already
+// contains everything the desired MachineConfig would add
+func machineConfigIsNoOp(desired, rendered *mcfgv1.MachineConfig) (bool, error) {
+	if desired.Spec.FIPS && !rendered.Spec.FIPS {
+		return false, nil
+	}
+	if desired.Spec.KernelType != "" && desired.Spec.KernelType != rendered.Spec.KernelType {
+		return false, nil
+	}
+	if !stringsContained(desired.Spec.KernelArguments, rendered.Spec.KernelArguments) ||
+		!stringsContained(desired.Spec.Extensions, rendered.Spec.Extensions) {
+		return false, nil
+	}
+
+	if len(desired.Spec.Config.Raw) == 0 {
+		return true, nil
+	}
+	desiredIgn, err := mcfgcommon.ParseAndConvertConfig(desired.Spec.Config.Raw)
+	if err != nil {
+		return false, fmt.Errorf("couldn't parse the Ignition config of MachineConfig %s: %w", desired.Name, err)
+	}
+	renderedIgn, err := mcfgcommon.ParseAndConvertConfig(rendered.Spec.Config.Raw)
+	if err != nil {
+		return false, fmt.Errorf("couldn't parse the Ignition config of MachineConfig %s: %w", rendered.Name, err)
+	}
+
+	for _, file := range desiredIgn.Storage.Files {
+		same, err := ignitionFileIsRendered(file, renderedIgn.Storage.Files)
+		if err != nil || !same {
+			return false, err
+		}
+	}
+	for _, unit := range desiredIgn.Systemd.Units {
+		if !ignitionUnitIsRendered(unit, renderedIgn.Systemd.Units) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ignitionFileIsRendered returns whether one of the rendered files has the
+// same path, contents and mode as the given file
+func ignitionFileIsRendered(file ign3types.File, rendered []ign3types.File) (bool, error) {
+	for _, renderedFile := range rendered {
+		if renderedFile.Path != file.Path {
+			continue
+		}
+		if file.Mode != nil && (renderedFile.Mode == nil || *file.Mode != *renderedFile.Mode) {
+			return false, nil
+		}
+		contents, err := mcfgcommon.DecodeIgnitionFileContents(file.Contents.Source, file.Contents.Compression)
+		if err != nil {
+			return false, fmt.Errorf("couldn't decode the contents of file %s: %w", file.Path, err)
+		}
+		renderedContents, err := mcfgcommon.DecodeIgnitionFileContents(renderedFile.Contents.Source, renderedFile.Contents.Compression)
+		if err != nil {
+			return false, fmt.Errorf("couldn't decode the rendered contents of file %s: %w", file.Path, err)
+		}
+		return bytes.Equal(contents, renderedContents), nil
+	}
+	return false, nil
+}
+
+// ignitionUnitIsRendered returns whether one of the rendered units is the
+// same as the given unit
+func ignitionUnitIsRendered(unit ign3types.Unit, rendered []ign3types.Unit) bool {
+	for _, renderedUnit := range rendered {
+		if renderedUnit.Name == unit.Name {
+			return reflect.DeepEqual(unit, renderedUnit)
+		}
+	}
+	return false
+}
+
+// stringsContained returns whether all of the wanted strings are in have
+func stringsContained(wanted, have []string) bool {
+	for _, w := range wanted {
+		found := false
+		for _, h := range have {
+			if w == h {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
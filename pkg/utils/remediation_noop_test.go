@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	mcfgapi "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Detecting remediations that don't change the cluster", func() {
+	const (
+		namespace = "openshift-compliance"
+		scanName  = "ocp4-cis-node-worker"
+	)
+
+	var c client.Client
+
+	// The rendered config has the same contents as the remediations, but
+	// encoded differently
+	const renderedIgnition = `{"ignition":{"version":"3.1.0"},"storage":{"files":[` +
+		`{"path":"/etc/sysctl.d/75-sysctl_kernel_kptr_restrict.conf","mode":420,` +
+		`"contents":{"source":"data:text/plain;charset=utf-8;base64,a2VybmVsLmtwdHJfcmVzdHJpY3Q9MQo="}},` +
+		`{"path":"/etc/other.conf","mode":420,"contents":{"source":"data:,other"}}]}}`
+
+	newMcRemediation := func(rule, ignition string, kargs []string) *compv1alpha1.ComplianceRemediation {
+		mc := &mcfgv1.MachineConfig{
+			TypeMeta: metav1.TypeMeta{APIVersion: mcfgapi.GroupName + "/v1", Kind: "MachineConfig"},
+			Spec: mcfgv1.MachineConfigSpec{
+				Config:          runtime.RawExtension{Raw: []byte(ignition)},
+				KernelArguments: kargs,
+			},
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mc)
+		Expect(err).To(BeNil())
+
+		rem := &compv1alpha1.ComplianceRemediation{}
+		rem.Name = nameFromId(scanName, rule)
+		rem.Namespace = namespace
+		rem.Labels = map[string]string{compv1alpha1.ComplianceScanLabel: scanName}
+		rem.Spec.Current.Object = &unstructured.Unstructured{Object: obj}
+		return rem
+	}
+
+	newFileIgnition := func(source string) string {
+		return `{"ignition":{"version":"3.1.0"},"storage":{"files":[` +
+			`{"path":"/etc/sysctl.d/75-sysctl_kernel_kptr_restrict.conf","mode":420,` +
+			`"contents":{"source":"` + source + `"}}]}}`
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).To(Succeed())
+		Expect(mcfgapi.Install(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		objs := []runtime.Object{
+			&compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{Name: scanName, Namespace: namespace},
+				Spec: compv1alpha1.ComplianceScanSpec{
+					NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+				},
+			},
+			&mcfgv1.MachineConfigPool{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+				Spec: mcfgv1.MachineConfigPoolSpec{
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"node-role.kubernetes.io/worker": ""},
+					},
+				},
+				Status: mcfgv1.MachineConfigPoolStatus{
+					Configuration: mcfgv1.MachineConfigPoolStatusConfiguration{
+						ObjectReference: corev1.ObjectReference{Name: "rendered-worker-1234"},
+					},
+				},
+			},
+			&mcfgv1.MachineConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "rendered-worker-1234"},
+				Spec: mcfgv1.MachineConfigSpec{
+					Config:          runtime.RawExtension{Raw: []byte(renderedIgnition)},
+					KernelArguments: []string{"audit=1"},
+				},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "banner", Namespace: "openshift-config"},
+				Data:       map[string]string{"banner": "Authorized use only", "other": "value"},
+			},
+		}
+		c = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+	})
+
+	It("detects a MachineConfig remediation whose files are already rendered", func() {
+		rem := newMcRemediation("sysctl_kernel_kptr_restrict",
+			newFileIgnition("data:,kernel.kptr_restrict%3D1%0A"), []string{"audit=1"})
+		noop, err := RemediationIsNoOp(context.TODO(), c, rem)
+		Expect(err).To(BeNil())
+		Expect(noop).To(BeTrue())
+	})
+
+	It("detects a MachineConfig remediation whose file contents differ", func() {
+		rem := newMcRemediation("sysctl_kernel_kptr_restrict",
+			newFileIgnition("data:,kernel.kptr_restrict%3D2%0A"), nil)
+		noop, err := RemediationIsNoOp(context.TODO(), c, rem)
+		Expect(err).To(BeNil())
+		Expect(noop).To(BeFalse())
+	})
+
+	It("detects a MachineConfig remediation with a missing kernel argument", func() {
+		rem := newMcRemediation("coreos_audit_backlog_limit_kernel_argument",
+			`{"ignition":{"version":"3.1.0"}}`, []string{"audit_backlog_limit=8192"})
+		noop, err := RemediationIsNoOp(context.TODO(), c, rem)
+		Expect(err).To(BeNil())
+		Expect(noop).To(BeFalse())
+	})
+
+	It("compares other remediations with the existing object", func() {
+		newCmRemediation := func(data map[string]interface{}) *compv1alpha1.ComplianceRemediation {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"data": data}}
+			obj.SetAPIVersion("v1")
+			obj.SetKind("ConfigMap")
+			obj.SetNamespace("openshift-config")
+			obj.SetName("banner")
+			rem := &compv1alpha1.ComplianceRemediation{}
+			rem.Name = nameFromId(scanName, "banner")
+			rem.Namespace = namespace
+			rem.Spec.Current.Object = obj
+			return rem
+		}
+
+		noop, err := RemediationIsNoOp(context.TODO(), c, newCmRemediation(map[string]interface{}{"banner": "Authorized use only"}))
+		Expect(err).To(BeNil())
+		Expect(noop).To(BeTrue())
+
+		noop, err = RemediationIsNoOp(context.TODO(), c, newCmRemediation(map[string]interface{}{"banner": "Welcome"}))
+		Expect(err).To(BeNil())
+		Expect(noop).To(BeFalse())
+	})
+})
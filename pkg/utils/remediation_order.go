@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// RemediationDependencyCycleError is returned when the dependency annotations
+// of a set of remediations form a cycle, so there's no order in which they
+// could all be applied.
+type RemediationDependencyCycleError struct {
+	// The names of the remediations that are part of or depend on a cycle
+	Remediations []string
+}
+
+func (e *RemediationDependencyCycleError) Error() string {
+	return fmt.Sprintf("remediations have cyclic dependencies: %s", strings.Join(e.Remediations, ", "))
+}
+
+// SortRemediationsByDependencies returns the remediations in an order in which
+// every remediation comes after the remediations it depends on. Dependencies
+// are read from the RemediationDependencyAnnotation, whose rule IDs are
+// matched to the remediations of the check results of those rules in the same
+// scan, and from the RemediationObjectDependencyAnnotation, whose objects are
+// matched to the remediations that create them. Dependencies not satisfied by
+// any of the remediations in the list are ignored. Remediations that don't
+// depend on each other keep their relative order.
+func SortRemediationsByDependencies(rems []compv1alpha1.ComplianceRemediation) ([]compv1alpha1.ComplianceRemediation, error) {
+	byCheck := make(map[string][]int)
+	byObject := make(map[string][]int)
+	for i := range rems {
+		checkName := remediationCheckName(&rems[i])
+		byCheck[checkName] = append(byCheck[checkName], i)
+		if obj := rems[i].Spec.Current.Object; obj != nil {
+			key := remediationObjectKey(obj.GetAPIVersion(), obj.GetKind(), obj.GetNamespace(), obj.GetName())
+			byObject[key] = append(byObject[key], i)
+		}
+	}
+
+	// dependents[i] lists the remediations that have to wait for i
+	dependents := make([][]int, len(rems))
+	nDeps := make([]int, len(rems))
+	addDependencies := func(dependent int, prerequisites []int) {
+		for _, prereq := range prerequisites {
+			if prereq == dependent {
+				continue
+			}
+			dependents[prereq] = append(dependents[prereq], dependent)
+			nDeps[dependent]++
+		}
+	}
+
+	for i := range rems {
+		rem := &rems[i]
+		if deps := rem.Annotations[compv1alpha1.RemediationDependencyAnnotation]; deps != "" {
+			scanName := rem.Labels[compv1alpha1.ComplianceScanLabel]
+			for _, ruleID := range strings.Split(deps, ",") {
+				addDependencies(i, byCheck[nameFromId(scanName, strings.TrimSpace(ruleID))])
+			}
+		}
+		if _, ok := rem.Annotations[compv1alpha1.RemediationObjectDependencyAnnotation]; ok {
+			objDeps, err := rem.ParseRemediationDependencyRefs()
+			if err != nil {
+				return nil, fmt.Errorf("remediation %s: %w", rem.Name, err)
+			}
+			for _, dep := range objDeps {
+				addDependencies(i, byObject[remediationObjectKey(dep.APIVersion, dep.Kind, dep.Namespace, dep.Name)])
+			}
+		}
+	}
+
+	// Kahn's algorithm, always picking the first ready remediation in the
+	// original order so that the result is stable
+	sorted := make([]compv1alpha1.ComplianceRemediation, 0, len(rems))
+	done := make([]bool, len(rems))
+	for len(sorted) < len(rems) {
+		next := -1
+		for i := range rems {
+			if !done[i] && nDeps[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			cycleErr := &RemediationDependencyCycleError{}
+			for i := range rems {
+				if !done[i] {
+					cycleErr.Remediations = append(cycleErr.Remediations, rems[i].Name)
+				}
+			}
+			return nil, cycleErr
+		}
+
+		done[next] = true
+		sorted = append(sorted, rems[next])
+		for _, dependent := range dependents[next] {
+			nDeps[dependent]--
+		}
+	}
+
+	return sorted, nil
+}
+
+// remediationCheckName returns the name of the check result the remediation
+// was created for
+func remediationCheckName(rem *compv1alpha1.ComplianceRemediation) string {
+	for _, ref := range rem.OwnerReferences {
+		if ref.Kind == "ComplianceCheckResult" {
+			return ref.Name
+		}
+	}
+	// A check with a single remediation gives it its own name
+	return rem.Name
+}
+
+func remediationObjectKey(apiVersion, kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", apiVersion, kind, namespace, name)
+}
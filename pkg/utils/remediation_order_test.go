@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Ordering remediations by their dependencies", func() {
+	const scanName = "ocp4-cis"
+
+	newRemediation := func(rule string, annotations map[string]string) compv1alpha1.ComplianceRemediation {
+		name := nameFromId(scanName, rule)
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetNamespace("openshift-config")
+		obj.SetName(name)
+		rem := compv1alpha1.ComplianceRemediation{}
+		rem.Name = name
+		rem.Labels = map[string]string{compv1alpha1.ComplianceScanLabel: scanName}
+		rem.Annotations = annotations
+		rem.Spec.Current.Object = obj
+		return rem
+	}
+	dependsOn := func(rules string) map[string]string {
+		return map[string]string{compv1alpha1.RemediationDependencyAnnotation: rules}
+	}
+	names := func(rems []compv1alpha1.ComplianceRemediation) []string {
+		out := make([]string, 0, len(rems))
+		for _, rem := range rems {
+			out = append(out, rem.Name)
+		}
+		return out
+	}
+
+	It("applies a chain of dependencies in order", func() {
+		rems := []compv1alpha1.ComplianceRemediation{
+			newRemediation("xccdf_org.ssgproject.content_rule_c", dependsOn("xccdf_org.ssgproject.content_rule_b")),
+			newRemediation("xccdf_org.ssgproject.content_rule_b", dependsOn("xccdf_org.ssgproject.content_rule_a")),
+			newRemediation("xccdf_org.ssgproject.content_rule_a", nil),
+		}
+		sorted, err := SortRemediationsByDependencies(rems)
+		Expect(err).To(BeNil())
+		Expect(names(sorted)).To(Equal([]string{"ocp4-cis-a", "ocp4-cis-b", "ocp4-cis-c"}))
+	})
+
+	It("orders remediations after the objects they depend on", func() {
+		rems := []compv1alpha1.ComplianceRemediation{
+			newRemediation("b", map[string]string{
+				compv1alpha1.RemediationObjectDependencyAnnotation: `[{"apiVersion":"v1","kind":"ConfigMap","name":"ocp4-cis-a","namespace":"openshift-config"}]`,
+			}),
+			newRemediation("a", nil),
+		}
+		sorted, err := SortRemediationsByDependencies(rems)
+		Expect(err).To(BeNil())
+		Expect(names(sorted)).To(Equal([]string{"ocp4-cis-a", "ocp4-cis-b"}))
+	})
+
+	It("keeps the order of independent remediations", func() {
+		rems := []compv1alpha1.ComplianceRemediation{
+			newRemediation("b", nil),
+			newRemediation("c", dependsOn("a")),
+			newRemediation("a", nil),
+			newRemediation("d", dependsOn("not_in_the_list")),
+		}
+		sorted, err := SortRemediationsByDependencies(rems)
+		Expect(err).To(BeNil())
+		Expect(names(sorted)).To(Equal([]string{"ocp4-cis-b", "ocp4-cis-a", "ocp4-cis-c", "ocp4-cis-d"}))
+	})
+
+	It("detects cycles", func() {
+		rems := []compv1alpha1.ComplianceRemediation{
+			newRemediation("independent", nil),
+			newRemediation("a", dependsOn("b")),
+			newRemediation("b", dependsOn("a")),
+		}
+		_, err := SortRemediationsByDependencies(rems)
+		var cycleErr *RemediationDependencyCycleError
+		Expect(errors.As(err, &cycleErr)).To(BeTrue())
+		Expect(cycleErr.Remediations).To(Equal([]string{"ocp4-cis-a", "ocp4-cis-b"}))
+	})
+})
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UnapplyProgressFunc is called by UnapplySuiteRemediations after each
+// remediation was set to not be applied. done is the number of remediations
+// unapplied so far out of total.
+type UnapplyProgressFunc func(rem *compv1alpha1.ComplianceRemediation, done, total int)
+
+// UnapplySuiteRemediations sets all the applied remediations of the given
+// suite to not be applied. Remediations are unapplied in the reverse of their
+// dependency order, so a remediation is only unapplied once nothing that
+// depends on it is applied anymore. The MachineConfigPools that
+// MachineConfig and KubeletConfig remediations are rendered into are paused
+// before any remediation is unapplied so that the nodes are only updated
+// once; it's up to the caller to un-pause them. It returns the names of the
+// pools that were paused.
+func UnapplySuiteRemediations(ctx context.Context, client runtimeclient.Client, namespace, suiteName string,
+	progress UnapplyProgressFunc) ([]string, error) {
+	remList := &compv1alpha1.ComplianceRemediationList{}
+	listOpts := []runtimeclient.ListOption{
+		runtimeclient.InNamespace(namespace),
+		runtimeclient.MatchingLabels{compv1alpha1.SuiteLabel: suiteName},
+	}
+	if err := client.List(ctx, remList, listOpts...); err != nil {
+		return nil, fmt.Errorf("couldn't list the remediations of suite %s: %w", suiteName, err)
+	}
+
+	applied := []compv1alpha1.ComplianceRemediation{}
+	for _, rem := range remList.Items {
+		if rem.Spec.Apply {
+			applied = append(applied, rem)
+		}
+	}
+
+	sorted, err := SortRemediationsByDependencies(applied)
+	if err != nil {
+		return nil, err
+	}
+
+	pausedPools, err := pauseRemediationPools(ctx, client, namespace, sorted)
+	if err != nil {
+		return pausedPools, err
+	}
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remCopy := sorted[i].DeepCopy()
+		remCopy.Spec.Apply = false
+		if err := client.Update(ctx, remCopy); err != nil {
+			return pausedPools, fmt.Errorf("couldn't unapply remediation %s: %w", remCopy.Name, err)
+		}
+		if progress != nil {
+			progress(remCopy, len(sorted)-i, len(sorted))
+		}
+	}
+
+	return pausedPools, nil
+}
+
+// pauseRemediationPools pauses the pools that the MachineConfig and
+// KubeletConfig remediations apply to and returns the names of the pools
+// that weren't paused already.
+func pauseRemediationPools(ctx context.Context, client runtimeclient.Client, namespace string,
+	rems []compv1alpha1.ComplianceRemediation) ([]string, error) {
+	var mcfgpools *mcfgv1.MachineConfigPoolList
+	pausedPools := []string{}
+	for i := range rems {
+		obj := rems[i].Spec.Current.Object
+		if !IsMachineConfig(obj) && !IsKubeletConfig(obj) {
+			continue
+		}
+
+		if mcfgpools == nil {
+			mcfgpools = &mcfgv1.MachineConfigPoolList{}
+			if err := client.List(ctx, mcfgpools); err != nil {
+				return pausedPools, fmt.Errorf("couldn't list the pools for the remediations: %w", err)
+			}
+		}
+
+		scan := &compv1alpha1.ComplianceScan{}
+		scanKey := types.NamespacedName{Name: rems[i].Labels[compv1alpha1.ComplianceScanLabel], Namespace: namespace}
+		if err := client.Get(ctx, scanKey, scan); err != nil {
+			return pausedPools, fmt.Errorf("couldn't get scan for remediation %s: %w", rems[i].Name, err)
+		}
+
+		matches, pool := AnyMcfgPoolLabelMatches(scan.Spec.NodeSelector, mcfgpools)
+		if !matches {
+			continue
+		}
+		paused, err := SetMcfgPoolPaused(ctx, client, pool.Name, true)
+		if err != nil {
+			return pausedPools, fmt.Errorf("couldn't pause pool %s: %w", pool.Name, err)
+		}
+		if paused {
+			pausedPools = append(pausedPools, pool.Name)
+		}
+	}
+	return pausedPools, nil
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	mcfgapi "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Unapplying the remediations of a suite", func() {
+	const (
+		namespace = "openshift-compliance"
+		suiteName = "my-suite"
+		scanName  = "ocp4-cis-node-worker"
+	)
+
+	var c client.Client
+
+	newRemediation := func(rule, suite string, apply bool, annotations map[string]string) *compv1alpha1.ComplianceRemediation {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetNamespace("openshift-config")
+		obj.SetName(rule)
+		rem := &compv1alpha1.ComplianceRemediation{}
+		rem.Name = nameFromId(scanName, rule)
+		rem.Namespace = namespace
+		rem.Labels = map[string]string{
+			compv1alpha1.SuiteLabel:          suite,
+			compv1alpha1.ComplianceScanLabel: scanName,
+		}
+		rem.Annotations = annotations
+		rem.Spec.Apply = apply
+		rem.Spec.Current.Object = obj
+		return rem
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).To(Succeed())
+		Expect(mcfgapi.Install(scheme)).To(Succeed())
+
+		mcRem := newRemediation("xccdf_org.ssgproject.content_rule_mc", suiteName, true, nil)
+		mcRem.Spec.Current.Object.SetAPIVersion(mcfgapi.GroupName + "/v1")
+		mcRem.Spec.Current.Object.SetKind("MachineConfig")
+		mcRem.Spec.Current.Object.SetNamespace("")
+
+		objs := []runtime.Object{
+			&compv1alpha1.ComplianceScan{
+				ObjectMeta: metav1.ObjectMeta{Name: scanName, Namespace: namespace},
+				Spec: compv1alpha1.ComplianceScanSpec{
+					NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+				},
+			},
+			&mcfgv1.MachineConfigPool{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+				Spec: mcfgv1.MachineConfigPoolSpec{
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"node-role.kubernetes.io/worker": ""},
+					},
+				},
+			},
+			newRemediation("xccdf_org.ssgproject.content_rule_a", suiteName, true, nil),
+			newRemediation("xccdf_org.ssgproject.content_rule_b", suiteName, true, map[string]string{
+				compv1alpha1.RemediationDependencyAnnotation: "xccdf_org.ssgproject.content_rule_a",
+			}),
+			newRemediation("xccdf_org.ssgproject.content_rule_c", suiteName, false, nil),
+			newRemediation("xccdf_org.ssgproject.content_rule_d", "other-suite", true, nil),
+			mcRem,
+		}
+		c = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+	})
+
+	getRemediation := func(name string) *compv1alpha1.ComplianceRemediation {
+		rem := &compv1alpha1.ComplianceRemediation{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, rem)).To(Succeed())
+		return rem
+	}
+
+	It("unapplies all the applied remediations of the suite", func() {
+		unapplied := []string{}
+		pausedPools, err := UnapplySuiteRemediations(context.TODO(), c, namespace, suiteName,
+			func(rem *compv1alpha1.ComplianceRemediation, done, total int) {
+				Expect(total).To(Equal(3))
+				Expect(done).To(Equal(len(unapplied) + 1))
+				unapplied = append(unapplied, rem.Name)
+			})
+		Expect(err).To(BeNil())
+		Expect(pausedPools).To(Equal([]string{"worker"}))
+
+		By("unapplying dependents before their dependencies")
+		Expect(unapplied).To(Equal([]string{
+			"ocp4-cis-node-worker-mc",
+			"ocp4-cis-node-worker-b",
+			"ocp4-cis-node-worker-a",
+		}))
+
+		for _, name := range []string{"ocp4-cis-node-worker-a", "ocp4-cis-node-worker-b", "ocp4-cis-node-worker-c", "ocp4-cis-node-worker-mc"} {
+			Expect(getRemediation(name).Spec.Apply).To(BeFalse())
+		}
+
+		By("leaving the remediations of other suites alone")
+		Expect(getRemediation("ocp4-cis-node-worker-d").Spec.Apply).To(BeTrue())
+
+		By("pausing the affected pool")
+		pool := &mcfgv1.MachineConfigPool{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: "worker"}, pool)).To(Succeed())
+		Expect(pool.Spec.Paused).To(BeTrue())
+	})
+})
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// GetRuleCheckType classifies the rule by where it's checked, based on its
+// metadata rather than on the name of the profiles it's part of. hasCheck
+// tells whether the rule has a check at all; rules without one are of
+// neither type.
+//
+// Rules that fetch objects from the API server are platform rules. Otherwise,
+// rules that are fixed with a MachineConfig or a KubeletConfig change the
+// nodes and are node rules, while rules that are fixed with any other
+// Kubernetes object are platform rules. Rules without fixes are node rules,
+// since their checks don't need anything from the API server.
+func GetRuleCheckType(rule *xmlquery.Node, hasCheck bool) string {
+	if !hasCheck {
+		return compv1alpha1.CheckTypeNone
+	}
+	if RuleHasApiObjectWarning(rule) {
+		return compv1alpha1.CheckTypePlatform
+	}
+
+	hasPlatformFix := false
+	for _, fix := range rule.SelectElements("xccdf-1.2:fix") {
+		switch fix.SelectAttr("system") {
+		case machineConfigFixType:
+			return compv1alpha1.CheckTypeNode
+		case kubernetesFixType:
+			if kubernetesFixChangesNodes(fix) {
+				return compv1alpha1.CheckTypeNode
+			}
+			hasPlatformFix = true
+		}
+	}
+	if hasPlatformFix {
+		return compv1alpha1.CheckTypePlatform
+	}
+	return compv1alpha1.CheckTypeNode
+}
+
+// kubernetesFixChangesNodes returns whether the Kubernetes fix is rendered
+// onto the nodes
+func kubernetesFixChangesNodes(fix *xmlquery.Node) bool {
+	objs, err := ReadObjectsFromYAML(strings.NewReader(fix.InnerText()))
+	if err != nil {
+		return false
+	}
+	for _, obj := range objs {
+		if IsKubeletConfig(obj) || IsMachineConfig(obj) {
+			return true
+		}
+	}
+	return false
+}
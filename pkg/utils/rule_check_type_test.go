@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Classifying the check type of rules", func() {
+	parseRule := func(body string) *xmlquery.Node {
+		doc, err := ParseContent(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<xccdf-1.2:Benchmark xmlns:xccdf-1.2="http://checklists.nist.gov/xccdf/1.2" xmlns:html="http://www.w3.org/1999/xhtml" id="xccdf_org.ssgproject.content_benchmark_OCP-4">
+  <xccdf-1.2:Rule id="xccdf_org.ssgproject.content_rule_test" selected="true">` + body + `</xccdf-1.2:Rule>
+</xccdf-1.2:Benchmark>`))
+		Expect(err).To(BeNil())
+		rule := xmlquery.FindOne(doc, "//xccdf-1.2:Rule")
+		Expect(rule).NotTo(BeNil())
+		return rule
+	}
+
+	It("classifies rules that fetch API objects as platform rules", func() {
+		rule := parseRule(`
+    <xccdf-1.2:warning category="general" lang="en-US"><html:code class="ocp-api-endpoint">/apis/config.openshift.io/v1/oauths/cluster</html:code></xccdf-1.2:warning>
+    <xccdf-1.2:fix system="urn:xccdf:fix:script:kubernetes">
+apiVersion: config.openshift.io/v1
+kind: OAuth
+metadata:
+  name: cluster
+</xccdf-1.2:fix>`)
+		Expect(GetRuleCheckType(rule, true)).To(Equal(compv1alpha1.CheckTypePlatform))
+	})
+
+	It("classifies rules fixed with a MachineConfig as node rules", func() {
+		rule := parseRule(`
+    <xccdf-1.2:fix system="urn:xccdf:fix:script:ignition">
+apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+spec:
+  config:
+    ignition:
+      version: 3.1.0
+</xccdf-1.2:fix>`)
+		Expect(GetRuleCheckType(rule, true)).To(Equal(compv1alpha1.CheckTypeNode))
+	})
+
+	It("classifies rules fixed with a KubeletConfig as node rules", func() {
+		rule := parseRule(`
+    <xccdf-1.2:fix system="urn:xccdf:fix:script:kubernetes">
+apiVersion: machineconfiguration.openshift.io/v1
+kind: KubeletConfig
+spec:
+  kubeletConfig:
+    eventRecordQPS: 5
+</xccdf-1.2:fix>`)
+		Expect(GetRuleCheckType(rule, true)).To(Equal(compv1alpha1.CheckTypeNode))
+	})
+
+	It("classifies rules fixed with other Kubernetes objects as platform rules", func() {
+		rule := parseRule(`
+    <xccdf-1.2:fix system="urn:xccdf:fix:script:kubernetes">
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: banner
+  namespace: openshift-config
+</xccdf-1.2:fix>`)
+		Expect(GetRuleCheckType(rule, true)).To(Equal(compv1alpha1.CheckTypePlatform))
+	})
+
+	It("classifies rules without fixes as node rules", func() {
+		Expect(GetRuleCheckType(parseRule(""), true)).To(Equal(compv1alpha1.CheckTypeNode))
+	})
+
+	It("doesn't classify rules without a check", func() {
+		rule := parseRule(`
+    <xccdf-1.2:warning category="general" lang="en-US"><html:code class="ocp-api-endpoint">/apis/config.openshift.io/v1/oauths/cluster</html:code></xccdf-1.2:warning>`)
+		Expect(GetRuleCheckType(rule, false)).To(Equal(compv1alpha1.CheckTypeNone))
+	})
+})
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+// AggregateSuiteResult returns the result of a ComplianceSuite made of the
+// given scans, using the same precedence as the suite controller: any scan
+// that is not done yet makes the result not available, then any error,
+// inconsistent or non-compliant scan wins, in that order. The suite is only
+// compliant if every scan is, and not applicable if no scan had applicable
+// checks.
+func AggregateSuiteResult(scans []compv1alpha1.ComplianceScan) compv1alpha1.ComplianceScanStatusResult {
+	results := make([]compv1alpha1.ComplianceScanStatusResult, 0, len(scans))
+	for i := range scans {
+		results = append(results, scans[i].Status.Result)
+	}
+	return compv1alpha1.LowestCommonResultOf(results)
+}
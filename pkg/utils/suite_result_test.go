@@ -0,0 +1,48 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Aggregating the suite result", func() {
+	scansWithResults := func(results ...compv1alpha1.ComplianceScanStatusResult) []compv1alpha1.ComplianceScan {
+		scans := make([]compv1alpha1.ComplianceScan, 0, len(results))
+		for _, result := range results {
+			scan := compv1alpha1.ComplianceScan{}
+			scan.Status.Result = result
+			scans = append(scans, scan)
+		}
+		return scans
+	}
+
+	DescribeTable("picks the lowest common result",
+		func(expected compv1alpha1.ComplianceScanStatusResult, results ...compv1alpha1.ComplianceScanStatusResult) {
+			Expect(AggregateSuiteResult(scansWithResults(results...))).To(Equal(expected))
+		},
+		Entry("without scans", compv1alpha1.ResultNotAvailable),
+		Entry("with a single compliant scan", compv1alpha1.ResultCompliant,
+			compv1alpha1.ResultCompliant),
+		Entry("with compliant scans", compv1alpha1.ResultCompliant,
+			compv1alpha1.ResultCompliant, compv1alpha1.ResultCompliant),
+		Entry("not applicable over compliant", compv1alpha1.ResultNotApplicable,
+			compv1alpha1.ResultCompliant, compv1alpha1.ResultNotApplicable),
+		Entry("non-compliant over not applicable", compv1alpha1.ResultNonCompliant,
+			compv1alpha1.ResultNotApplicable, compv1alpha1.ResultNonCompliant),
+		Entry("non-compliant over compliant", compv1alpha1.ResultNonCompliant,
+			compv1alpha1.ResultNonCompliant, compv1alpha1.ResultCompliant),
+		Entry("inconsistent over non-compliant", compv1alpha1.ResultInconsistent,
+			compv1alpha1.ResultNonCompliant, compv1alpha1.ResultInconsistent, compv1alpha1.ResultCompliant),
+		Entry("error over inconsistent", compv1alpha1.ResultError,
+			compv1alpha1.ResultInconsistent, compv1alpha1.ResultError),
+		Entry("error over non-compliant", compv1alpha1.ResultError,
+			compv1alpha1.ResultCompliant, compv1alpha1.ResultNonCompliant, compv1alpha1.ResultError),
+		Entry("not available over error", compv1alpha1.ResultNotAvailable,
+			compv1alpha1.ResultError, compv1alpha1.ResultNotAvailable),
+		Entry("not available over compliant", compv1alpha1.ResultNotAvailable,
+			compv1alpha1.ResultCompliant, compv1alpha1.ResultNotAvailable),
+	)
+})
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+	"github.com/ComplianceAsCode/compliance-operator/pkg/xccdf"
+)
+
+const testResultIDPrefix = "xccdf_compliance.openshift.io_testresult_"
+
+type testResultElement struct {
+	XMLName   xml.Name            `xml:"TestResult"`
+	Xmlns     string              `xml:"xmlns,attr"`
+	ID        string              `xml:"id,attr"`
+	StartTime string              `xml:"start-time,attr"`
+	EndTime   string              `xml:"end-time,attr"`
+	Title     string              `xml:"title"`
+	Profile   *profileRefElement  `xml:"profile,omitempty"`
+	Results   []ruleResultElement `xml:"rule-result"`
+}
+
+type profileRefElement struct {
+	IDRef string `xml:"idref,attr"`
+}
+
+type ruleResultElement struct {
+	IDRef    string `xml:"idref,attr"`
+	Severity string `xml:"severity,attr"`
+	Time     string `xml:"time,attr"`
+	Result   string `xml:"result"`
+}
+
+// XccdfResultFromCheckResults renders a minimal XCCDF TestResult document out of
+// a list of ComplianceCheckResults. This allows results that were not produced
+// by OpenSCAP to flow through the same tooling that consumes the oscap output.
+func XccdfResultFromCheckResults(scanName, profileID string, results []compv1alpha1.ComplianceCheckResult) (string, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	testResult := testResultElement{
+		Xmlns:     xccdf.XCCDFURI,
+		ID:        testResultIDPrefix + scanName,
+		StartTime: now,
+		EndTime:   now,
+		Title:     "Compliance Operator Scan Result",
+		Results:   make([]ruleResultElement, 0, len(results)),
+	}
+	if profileID != "" {
+		testResult.Profile = &profileRefElement{IDRef: profileID}
+	}
+
+	for i := range results {
+		res := &results[i]
+		if res.ID == "" {
+			return "", fmt.Errorf("check result %s has no ID", res.Name)
+		}
+		severity := res.Severity
+		if severity == "" {
+			severity = compv1alpha1.CheckResultSeverityUnknown
+		}
+		testResult.Results = append(testResult.Results, ruleResultElement{
+			IDRef:    res.ID,
+			Severity: string(severity),
+			Time:     now,
+			Result:   xccdfResultFromCheckStatus(res.Status),
+		})
+	}
+
+	output, err := xml.MarshalIndent(testResult, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xccdf.XMLHeader + "\n" + string(output), nil
+}
+
+// xccdfResultFromCheckStatus is the inverse of mapComplianceCheckResultStatus.
+// Where several XCCDF results map to the same status, the canonical one is used.
+func xccdfResultFromCheckStatus(status compv1alpha1.ComplianceCheckStatus) string {
+	switch status {
+	case compv1alpha1.CheckResultPass:
+		return "pass"
+	case compv1alpha1.CheckResultFail:
+		return "fail"
+	case compv1alpha1.CheckResultError:
+		return "error"
+	case compv1alpha1.CheckResultManual:
+		return "notchecked"
+	case compv1alpha1.CheckResultInfo:
+		return "informational"
+	case compv1alpha1.CheckResultNotApplicable:
+		return "notapplicable"
+	case compv1alpha1.CheckResultNoResult:
+		return "notselected"
+	}
+	// Inconsistent results don't have an XCCDF counterpart, "unknown" is
+	// the closest thing and maps back to an error.
+	return "unknown"
+}
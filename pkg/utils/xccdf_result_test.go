@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+var _ = Describe("Rendering XCCDF results", func() {
+	newCheck := func(id string, status compv1alpha1.ComplianceCheckStatus, severity compv1alpha1.ComplianceCheckResultSeverity) compv1alpha1.ComplianceCheckResult {
+		return compv1alpha1.ComplianceCheckResult{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: IDToDNSFriendlyName(id),
+			},
+			ID:       id,
+			Status:   status,
+			Severity: severity,
+		}
+	}
+
+	It("renders a document that parses back to the same results", func() {
+		checks := []compv1alpha1.ComplianceCheckResult{
+			newCheck("xccdf_org.ssgproject.content_rule_pass", compv1alpha1.CheckResultPass, compv1alpha1.CheckResultSeverityHigh),
+			newCheck("xccdf_org.ssgproject.content_rule_fail", compv1alpha1.CheckResultFail, compv1alpha1.CheckResultSeverityMedium),
+			newCheck("xccdf_org.ssgproject.content_rule_error", compv1alpha1.CheckResultError, compv1alpha1.CheckResultSeverityLow),
+			newCheck("xccdf_org.ssgproject.content_rule_manual", compv1alpha1.CheckResultManual, compv1alpha1.CheckResultSeverityInfo),
+			newCheck("xccdf_org.ssgproject.content_rule_info", compv1alpha1.CheckResultInfo, compv1alpha1.CheckResultSeverityLow),
+			newCheck("xccdf_org.ssgproject.content_rule_na", compv1alpha1.CheckResultNotApplicable, ""),
+		}
+
+		out, err := XccdfResultFromCheckResults("test-scan", "xccdf_org.ssgproject.content_profile_moderate", checks)
+		Expect(err).To(BeNil())
+
+		dom, err := ParseContent(strings.NewReader(out))
+		Expect(err).To(BeNil())
+
+		profile := dom.SelectElement("//profile")
+		Expect(profile).ToNot(BeNil())
+		Expect(profile.SelectAttr("idref")).To(Equal("xccdf_org.ssgproject.content_profile_moderate"))
+
+		ruleResults := dom.SelectElements("//rule-result")
+		Expect(ruleResults).To(HaveLen(len(checks)))
+		for i, rr := range ruleResults {
+			Expect(rr.SelectAttr("idref")).To(Equal(checks[i].ID))
+			status, err := mapComplianceCheckResultStatus(rr)
+			Expect(err).To(BeNil())
+			Expect(status).To(Equal(checks[i].Status))
+			severity, err := mapComplianceCheckResultSeverity(rr)
+			Expect(err).To(BeNil())
+			if checks[i].Severity == "" {
+				Expect(severity).To(Equal(compv1alpha1.CheckResultSeverityUnknown))
+			} else {
+				Expect(severity).To(Equal(checks[i].Severity))
+			}
+		}
+	})
+
+	It("maps inconsistent results to an error", func() {
+		checks := []compv1alpha1.ComplianceCheckResult{
+			newCheck("xccdf_org.ssgproject.content_rule_inconsistent", compv1alpha1.CheckResultInconsistent, compv1alpha1.CheckResultSeverityHigh),
+		}
+
+		out, err := XccdfResultFromCheckResults("test-scan", "", checks)
+		Expect(err).To(BeNil())
+
+		dom, err := ParseContent(strings.NewReader(out))
+		Expect(err).To(BeNil())
+		Expect(dom.SelectElement("//profile")).To(BeNil())
+		rr := dom.SelectElement("//rule-result")
+		Expect(rr).ToNot(BeNil())
+		status, err := mapComplianceCheckResultStatus(rr)
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(compv1alpha1.CheckResultError))
+	})
+
+	It("fails on results without an ID", func() {
+		checks := []compv1alpha1.ComplianceCheckResult{
+			newCheck("", compv1alpha1.CheckResultPass, compv1alpha1.CheckResultSeverityHigh),
+		}
+		_, err := XccdfResultFromCheckResults("test-scan", "", checks)
+		Expect(err).ToNot(BeNil())
+	})
+})
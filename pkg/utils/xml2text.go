@@ -106,7 +106,7 @@ func formatStartElement(e xml.StartElement) string {
 }
 
 func RenderValues(in string, valuesList map[string]string) (string, []string, error) {
-	t, err := template.New("").Option("missingkey=zero").Parse(in)
+	t, err := template.New("").Option("missingkey=zero").Funcs(template.FuncMap{"toArrayByComma": toArrayByComma}).Parse(in)
 
 	if err != nil {
 		return in, nil, errors.Wrap(err, "wrongly formatted context: ")
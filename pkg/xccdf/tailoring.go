@@ -59,6 +59,7 @@ type ProfileElement struct {
 	Description *TitleOrDescriptionElement `xml:"xccdf-1.2:description"`
 	Selections  []SelectElement
 	Values      []SetValueElement
+	Refinements []RefineRuleElement
 }
 
 type TitleOrDescriptionElement struct {
@@ -78,6 +79,12 @@ type SetValueElement struct {
 	Value   string   `xml:",chardata"`
 }
 
+type RefineRuleElement struct {
+	XMLName  xml.Name `xml:"xccdf-1.2:refine-rule"`
+	IDRef    string   `xml:"idref,attr"`
+	Severity string   `xml:"severity,attr"`
+}
+
 // GetContentFileName gets the file name for a profile bundle
 func GetContentFileName(productName string) string {
 	return fmt.Sprintf("%s%s%s", ContentFileNamePrefix, productName, ContentFileNameSuffix)
@@ -159,6 +166,24 @@ func getSelections(tp *cmpv1alpha1.TailoredProfile, rules map[string]*cmpv1alpha
 	return selections
 }
 
+// getRuleRefinements returns the refinements for the rules whose severity
+// the TailoredProfile overrides
+func getRuleRefinements(tp *cmpv1alpha1.TailoredProfile, rules map[string]*cmpv1alpha1.Rule) []RefineRuleElement {
+	refinements := []RefineRuleElement{}
+	for _, selections := range [][]cmpv1alpha1.RuleReferenceSpec{tp.Spec.EnableRules, tp.Spec.ManualRules} {
+		for _, selection := range selections {
+			if selection.Severity == "" {
+				continue
+			}
+			refinements = append(refinements, RefineRuleElement{
+				IDRef:    rules[selection.Name].ID,
+				Severity: string(selection.Severity),
+			})
+		}
+	}
+	return refinements
+}
+
 func GetManualRules(tp *cmpv1alpha1.TailoredProfile) []string {
 	ruleList := []string{}
 	for _, selection := range tp.Spec.ManualRules {
@@ -208,9 +233,10 @@ func TailoredProfileToXML(tp *cmpv1alpha1.TailoredProfile, p *cmpv1alpha1.Profil
 			Href: filepath.Join("/content", pb.Spec.ContentFile),
 		},
 		Profile: ProfileElement{
-			ID:         GetXCCDFProfileID(tp),
-			Selections: getSelections(tp, rules),
-			Values:     getValuesFromVariables(variables),
+			ID:          GetXCCDFProfileID(tp),
+			Selections:  getSelections(tp, rules),
+			Values:      getValuesFromVariables(variables),
+			Refinements: getRuleRefinements(tp, rules),
 		},
 	}
 	if p != nil {
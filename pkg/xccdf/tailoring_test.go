@@ -34,6 +34,19 @@ func findVariablesInTailoring(tailoring string) ([]tailoredValue, error) {
 	return tailoredVars, nil
 }
 
+func findRuleRefinementsInTailoring(tailoring string) (map[string]string, error) {
+	tailoringDom, err := xmlquery.Parse(strings.NewReader(tailoring))
+	if err != nil {
+		return nil, err
+	}
+
+	refinements := make(map[string]string)
+	for _, refineNode := range tailoringDom.SelectElements("//xccdf-1.2:refine-rule") {
+		refinements[refineNode.SelectAttr("idref")] = refineNode.SelectAttr("severity")
+	}
+	return refinements, nil
+}
+
 var _ = Describe("Testing parse variables", func() {
 	var (
 		tp        *cmpv1alpha1.TailoredProfile
@@ -110,4 +123,50 @@ var _ = Describe("Testing parse variables", func() {
 				tailoredValue{ID: "baz_id", Value: "true"}))
 		})
 	})
+
+	Context("overriding rule severities", func() {
+		var rules map[string]*cmpv1alpha1.Rule
+
+		newRule := func(name, id string) *cmpv1alpha1.Rule {
+			return &cmpv1alpha1.Rule{
+				ObjectMeta:  v1.ObjectMeta{Name: name},
+				RulePayload: cmpv1alpha1.RulePayload{ID: id, Severity: "medium"},
+			}
+		}
+
+		BeforeEach(func() {
+			rules = map[string]*cmpv1alpha1.Rule{
+				"rule-a": newRule("rule-a", "xccdf_org.ssgproject.content_rule_a"),
+				"rule-b": newRule("rule-b", "xccdf_org.ssgproject.content_rule_b"),
+				"rule-c": newRule("rule-c", "xccdf_org.ssgproject.content_rule_c"),
+			}
+			tp.Spec.EnableRules = []cmpv1alpha1.RuleReferenceSpec{
+				{Name: "rule-a", Rationale: "noisy", Severity: cmpv1alpha1.CheckResultSeverityLow},
+				{Name: "rule-b", Rationale: "default severity"},
+			}
+			tp.Spec.ManualRules = []cmpv1alpha1.RuleReferenceSpec{
+				{Name: "rule-c", Rationale: "we care", Severity: cmpv1alpha1.CheckResultSeverityHigh},
+			}
+		})
+
+		It("refines only the rules with a severity override", func() {
+			tailoring, err = TailoredProfileToXML(tp, p, pb, rules, nil)
+			Expect(err).To(BeNil())
+
+			refinements, err := findRuleRefinementsInTailoring(tailoring)
+			Expect(err).To(BeNil())
+			Expect(refinements).To(Equal(map[string]string{
+				"xccdf_org.ssgproject.content_rule_a": "low",
+				"xccdf_org.ssgproject.content_rule_c": "high",
+			}))
+		})
+
+		It("doesn't refine anything without overrides", func() {
+			tp.Spec.EnableRules[0].Severity = ""
+			tp.Spec.ManualRules[0].Severity = ""
+			tailoring, err = TailoredProfileToXML(tp, p, pb, rules, nil)
+			Expect(err).To(BeNil())
+			Expect(tailoring).NotTo(ContainSubstring("refine-rule"))
+		})
+	})
 })
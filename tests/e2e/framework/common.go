@@ -9,6 +9,7 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -1768,6 +1769,41 @@ func (f *Framework) AssertHasCheck(suiteName, scanName string, check compv1alpha
 	return nil
 }
 
+// AssertCheckCounts lists the ComplianceCheckResults of the given suite and
+// compares how many of them have each status with the expected counts. A
+// status that isn't in expected must not have any checks.
+func (f *Framework) AssertCheckCounts(suiteName string, expected map[compv1alpha1.ComplianceCheckStatus]int) error {
+	checkList := compv1alpha1.ComplianceCheckResultList{}
+	err := f.Client.List(context.TODO(), &checkList,
+		client.InNamespace(f.OperatorNamespace),
+		client.MatchingLabels{compv1alpha1.SuiteLabel: suiteName})
+	if err != nil {
+		return fmt.Errorf("failed to list the checks of suite %s: %w", suiteName, err)
+	}
+
+	counts := make(map[compv1alpha1.ComplianceCheckStatus]int)
+	for _, check := range checkList.Items {
+		counts[check.Status]++
+	}
+
+	mismatches := []string{}
+	for status, count := range counts {
+		if expected[status] != count {
+			mismatches = append(mismatches, fmt.Sprintf("%s: expected %d, got %d", status, expected[status], count))
+		}
+	}
+	for status, count := range expected {
+		if _, found := counts[status]; !found && count != 0 {
+			mismatches = append(mismatches, fmt.Sprintf("%s: expected %d, got 0", status, count))
+		}
+	}
+	if len(mismatches) > 0 {
+		sort.Strings(mismatches)
+		return fmt.Errorf("unexpected check counts for suite %s: %s", suiteName, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
 func (f *Framework) AssertHasRemediations(suiteName, scanName, roleLabel string, remNameList []string) error {
 	var scanSuiteMapNames = make(map[string]bool)
 	var scanSuiteRemediations []compv1alpha1.ComplianceRemediation
@@ -2118,15 +2154,7 @@ func (f *Framework) ResumeMachinePool(poolName string) error {
 }
 
 func (f *Framework) modMachinePoolPause(poolName string, pause bool) error {
-	pool := &mcfgv1.MachineConfigPool{}
-	err := f.Client.Get(context.TODO(), types.NamespacedName{Name: poolName}, pool)
-	if err != nil {
-		return fmt.Errorf("could not find pool to modify: %s", err)
-	}
-
-	poolCopy := pool.DeepCopy()
-	poolCopy.Spec.Paused = pause
-	err = f.Client.Update(context.TODO(), poolCopy)
+	_, err := utils.SetMcfgPoolPaused(context.TODO(), f.Client.Client, poolName, pause)
 	if err != nil {
 		return fmt.Errorf("failed to update pool: %s", err)
 	}
@@ -2134,6 +2162,13 @@ func (f *Framework) modMachinePoolPause(poolName string, pause bool) error {
 	return nil
 }
 
+// WithPausedMachinePool pauses the pool while running fn, so that all the
+// changes fn makes to the pool are rolled out at once, and waits for the
+// pool to finish updating once it was resumed
+func (f *Framework) WithPausedMachinePool(poolName string, fn func() error) error {
+	return utils.WithPausedPool(context.TODO(), f.Client.Client, poolName, machineOperationTimeout, fn)
+}
+
 func (f *Framework) ApplyRemediationAndCheck(namespace, name, pool string) error {
 	rem := &compv1alpha1.ComplianceRemediation{}
 	err := f.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, rem)
@@ -2222,6 +2257,49 @@ func (f *Framework) UnApplyRemediationAndCheck(namespace, name, pool string) err
 	return nil
 }
 
+// UnApplySuiteRemediationsAndCheck unapplies all the applied remediations of
+// the suite at once, resumes the pools that were paused for it and waits
+// until none of their MachineConfigs is in the pool anymore.
+func (f *Framework) UnApplySuiteRemediationsAndCheck(namespace, suiteName, pool string) error {
+	mcNames := map[string]bool{}
+	unApplyRemediations := func() error {
+		pausedPools, err := utils.UnapplySuiteRemediations(context.TODO(), f.Client.Client, namespace, suiteName,
+			func(rem *compv1alpha1.ComplianceRemediation, done, total int) {
+				log.Printf("remediation %s unapplied (%d/%d)\n", rem.Name, done, total)
+				mcNames[rem.GetMcName()] = true
+			})
+		for _, pausedPool := range pausedPools {
+			if resumeErr := f.ResumeMachinePool(pausedPool); resumeErr != nil {
+				log.Printf("cannot resume pool %s: %s\n", pausedPool, resumeErr)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("cannot unapply the remediations of suite %s: %w", suiteName, err)
+		}
+		return nil
+	}
+
+	predicate := func(pool *mcfgv1.MachineConfigPool) (bool, error) {
+		for _, mc := range pool.Status.Configuration.Source {
+			if mcNames[mc.Name] {
+				log.Printf("remediation MachineConfig %s present in pool %s, returning false\n", mc.Name, pool.Name)
+				return false, nil
+			}
+		}
+
+		log.Printf("no remediation MachineConfig of suite %s present in pool %s, returning true\n", suiteName, pool.Name)
+		return true, nil
+	}
+
+	err := f.WaitForMachinePoolUpdate(pool, unApplyRemediations, predicate, nil)
+	if err != nil {
+		return fmt.Errorf("failed to wait for pool to update after unapplying the remediations: %v", err)
+	}
+
+	log.Printf("machines updated without the remediations of suite %s\n", suiteName)
+	return nil
+}
+
 func (f *Framework) runPod(namespace string, podToRun *core.Pod) (*core.Pod, error) {
 	pod, err := f.KubeClient.CoreV1().Pods(namespace).Create(context.TODO(), podToRun, metav1.CreateOptions{})
 	if err != nil {
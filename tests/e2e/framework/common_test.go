@@ -0,0 +1,114 @@
+package framework
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/ComplianceAsCode/compliance-operator/pkg/apis"
+	compv1alpha1 "github.com/ComplianceAsCode/compliance-operator/pkg/apis/compliance/v1alpha1"
+)
+
+func newCheckCountsFramework(t *testing.T) *Framework {
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build the scheme: %v", err)
+	}
+
+	objs := []runtime.Object{}
+	addChecks := func(suite string, status compv1alpha1.ComplianceCheckStatus, n int) {
+		for i := 0; i < n; i++ {
+			objs = append(objs, &compv1alpha1.ComplianceCheckResult{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-%s-%d", suite, strings.ToLower(string(status)), i),
+					Namespace: "openshift-compliance",
+					Labels:    map[string]string{compv1alpha1.SuiteLabel: suite},
+				},
+				Status: status,
+			})
+		}
+	}
+	addChecks("moderate", compv1alpha1.CheckResultPass, 3)
+	addChecks("moderate", compv1alpha1.CheckResultFail, 2)
+	addChecks("moderate", compv1alpha1.CheckResultManual, 1)
+	addChecks("other", compv1alpha1.CheckResultFail, 4)
+
+	return &Framework{
+		Client:            &frameworkClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()},
+		OperatorNamespace: "openshift-compliance",
+	}
+}
+
+func TestAssertCheckCounts(t *testing.T) {
+	f := newCheckCountsFramework(t)
+
+	tests := []struct {
+		name     string
+		expected map[compv1alpha1.ComplianceCheckStatus]int
+		wantErr  string
+	}{
+		{
+			name: "matching counts",
+			expected: map[compv1alpha1.ComplianceCheckStatus]int{
+				compv1alpha1.CheckResultPass:   3,
+				compv1alpha1.CheckResultFail:   2,
+				compv1alpha1.CheckResultManual: 1,
+			},
+		},
+		{
+			name: "explicit zero count",
+			expected: map[compv1alpha1.ComplianceCheckStatus]int{
+				compv1alpha1.CheckResultPass:   3,
+				compv1alpha1.CheckResultFail:   2,
+				compv1alpha1.CheckResultManual: 1,
+				compv1alpha1.CheckResultError:  0,
+			},
+		},
+		{
+			name: "wrong count",
+			expected: map[compv1alpha1.ComplianceCheckStatus]int{
+				compv1alpha1.CheckResultPass:   3,
+				compv1alpha1.CheckResultFail:   6,
+				compv1alpha1.CheckResultManual: 1,
+			},
+			wantErr: "FAIL: expected 6, got 2",
+		},
+		{
+			name: "unexpected status",
+			expected: map[compv1alpha1.ComplianceCheckStatus]int{
+				compv1alpha1.CheckResultPass: 3,
+				compv1alpha1.CheckResultFail: 2,
+			},
+			wantErr: "MANUAL: expected 0, got 1",
+		},
+		{
+			name: "missing status",
+			expected: map[compv1alpha1.ComplianceCheckStatus]int{
+				compv1alpha1.CheckResultPass:   3,
+				compv1alpha1.CheckResultFail:   2,
+				compv1alpha1.CheckResultManual: 1,
+				compv1alpha1.CheckResultInfo:   1,
+			},
+			wantErr: "INFO: expected 1, got 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.AssertCheckCounts("moderate", tt.expected)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -727,29 +727,26 @@ func TestUnapplyRemediation(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// Pause the MC so that we have only one reboot
-	err = f.PauseMachinePool(framework.TestPoolName)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	// Apply both remediations
+	// Apply both remediations with the MC pool paused so that we have only one reboot
 	workersNoRootLoginsRemName := fmt.Sprintf("%s-no-direct-root-logins", workerScanName)
-	err = f.ApplyRemediationAndCheck(f.OperatorNamespace, workersNoRootLoginsRemName, framework.TestPoolName)
-	if err != nil {
-		log.Printf("WARNING: Got an error while applying remediation '%s': %v\n", workersNoRootLoginsRemName, err)
-	}
-	log.Printf("remediation %s applied", workersNoRootLoginsRemName)
-
 	workersNoEmptyPassRemName := fmt.Sprintf("%s-no-empty-passwords", workerScanName)
-	err = f.ApplyRemediationAndCheck(f.OperatorNamespace, workersNoEmptyPassRemName, framework.TestPoolName)
+	err = f.WithPausedMachinePool(framework.TestPoolName, func() error {
+		err := f.ApplyRemediationAndCheck(f.OperatorNamespace, workersNoRootLoginsRemName, framework.TestPoolName)
+		if err != nil {
+			log.Printf("WARNING: Got an error while applying remediation '%s': %v\n", workersNoRootLoginsRemName, err)
+		}
+		log.Printf("remediation %s applied", workersNoRootLoginsRemName)
+
+		err = f.ApplyRemediationAndCheck(f.OperatorNamespace, workersNoEmptyPassRemName, framework.TestPoolName)
+		if err != nil {
+			log.Printf("WARNING: Got an error while applying remediation '%s': %v\n", workersNoEmptyPassRemName, err)
+		}
+		log.Printf("remediation %s applied", workersNoEmptyPassRemName)
+		return nil
+	})
 	if err != nil {
-		log.Printf("WARNING: Got an error while applying remediation '%s': %v\n", workersNoEmptyPassRemName, err)
+		t.Fatal(err)
 	}
-	log.Printf("remediation %s applied", workersNoEmptyPassRemName)
-
-	// resume the MCP so that the remediation gets applied
-	f.ResumeMachinePool(framework.TestPoolName)
 
 	err = f.WaitForNodesToBeReady()
 	if err != nil {
@@ -774,14 +771,14 @@ func TestUnapplyRemediation(t *testing.T) {
 	}
 	log.Printf("remediation %s reverted\n", workersNoEmptyPassRemName)
 
-	// When we unapply the second remediation, the MC should be deleted, too
-	log.Printf("reverting remediation %s", workersNoRootLoginsRemName)
-	err = f.UnApplyRemediationAndCheck(f.OperatorNamespace, workersNoRootLoginsRemName, framework.TestPoolName)
+	// When we unapply the remaining remediations of the suite, the MC should be deleted, too
+	log.Printf("reverting the remaining remediations of suite %s", suiteName)
+	err = f.UnApplySuiteRemediationsAndCheck(f.OperatorNamespace, suiteName, framework.TestPoolName)
 	if err != nil {
-		log.Printf("WARNING: Got an error while unapplying remediation '%s': %v\n", workersNoEmptyPassRemName, err)
+		log.Printf("WARNING: Got an error while unapplying the remediations of suite '%s': %v\n", suiteName, err)
 	}
 
-	log.Printf("remediation %s reverted", workersNoEmptyPassRemName)
+	log.Printf("remediations of suite %s reverted", suiteName)
 
 	log.Printf("no remediation-based MachineConfigs should exist now")
 	mcShouldntExist := &mcfgv1.MachineConfig{}
@@ -1171,6 +1168,13 @@ func TestUpdateRemediation(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	err = f.AssertCheckCounts(origSuiteName, map[compv1alpha1.ComplianceCheckStatus]int{
+		compv1alpha1.CheckResultFail: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	workersNoEmptyPassRemName := fmt.Sprintf("%s-no-empty-passwords", workerScanName)
 	err = f.ApplyRemediationAndCheck(f.OperatorNamespace, workersNoEmptyPassRemName, framework.TestPoolName)
 	if err != nil {
@@ -1200,6 +1204,13 @@ func TestUpdateRemediation(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	err = f.AssertCheckCounts(origSuiteName, map[compv1alpha1.ComplianceCheckStatus]int{
+		compv1alpha1.CheckResultPass: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	err = f.AssertRemediationIsObsolete(f.OperatorNamespace, workersNoEmptyPassRemName)
 	if err != nil {
 		t.Fatal(err)
@@ -1223,8 +1234,8 @@ func TestUpdateRemediation(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// Finally clean up by removing the remediation and waiting for the nodes to reboot one more time
-	err = f.UnApplyRemediationAndCheck(f.OperatorNamespace, workersNoEmptyPassRemName, framework.TestPoolName)
+	// Finally clean up by removing the remediations and waiting for the nodes to reboot one more time
+	err = f.UnApplySuiteRemediationsAndCheck(f.OperatorNamespace, origSuiteName, framework.TestPoolName)
 	if err != nil {
 		t.Fatal(err)
 	}